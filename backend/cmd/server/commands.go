@@ -0,0 +1,268 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/supabase-community/supabase-go"
+	"github.com/toof-jp/shisha-log/backend/internal/backup"
+	"github.com/toof-jp/shisha-log/backend/internal/config"
+	"github.com/toof-jp/shisha-log/backend/internal/repository"
+	seedpkg "github.com/toof-jp/shisha-log/backend/internal/seed"
+	"github.com/toof-jp/shisha-log/backend/internal/service"
+	"github.com/toof-jp/shisha-log/backend/internal/webhook"
+)
+
+// runCommand runs a maintenance subcommand instead of the server
+func runCommand(cfg *config.Config, name string, args []string) error {
+	switch name {
+	case "backup":
+		return runBackup(cfg, args)
+	case "restore":
+		return runRestore(cfg, args)
+	case "seed":
+		return runSeed(cfg, args)
+	case "webhook-receiver":
+		return runWebhookReceiver(args)
+	default:
+		return fmt.Errorf("unknown command %q, available commands: backup, restore, seed, webhook-receiver", name)
+	}
+}
+
+// openDatabase connects to the database configured by DATABASE_URL
+func openDatabase(cfg *config.Config) (*sql.DB, error) {
+	db, err := sql.Open("postgres", cfg.DatabaseURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// runBackup writes a backup archive of the database to a file or stdout
+func runBackup(cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("backup", flag.ExitOnError)
+	output := flags.String("o", "", "Output file (default shisha-log-backup-YYYYMMDD-HHMMSS.tar.gz, - for stdout)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: server backup [-o file]")
+		fmt.Fprintln(flags.Output(), "Dump the users, sessions, flavors, token and related tables into a compressed, checksummed archive.")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	db, err := openDatabase(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	path := *output
+	if path == "" {
+		path = "shisha-log-backup-" + time.Now().Format("20060102-150405") + ".tar.gz"
+	}
+
+	var w io.Writer = os.Stdout
+	if path != "-" {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	manifest, err := backup.Backup(context.Background(), db, w)
+	if err != nil {
+		if path != "-" {
+			os.Remove(path)
+		}
+		return fmt.Errorf("backup failed: %w", err)
+	}
+
+	for _, table := range manifest.Tables {
+		log.Printf("Backed up %s: %d rows", table.Name, table.Rows)
+	}
+	if path != "-" {
+		log.Printf("Backup written to %s (schema version %q)", path, manifest.SchemaVersion)
+	}
+	return nil
+}
+
+// runRestore restores a backup archive into an empty database
+func runRestore(cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("restore", flag.ExitOnError)
+	allowSchemaMismatch := flags.Bool("allow-schema-mismatch", false, "Restore even if the schema version differs, as long as all archived columns exist")
+	verifyOnly := flags.Bool("verify", false, "Only verify the archive checksums without connecting to the database")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: server restore [-verify] [-allow-schema-mismatch] file")
+		fmt.Fprintln(flags.Output(), "Verify a backup archive and restore it into an empty database in one transaction.")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("restore needs exactly one archive file")
+	}
+
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if *verifyOnly {
+		manifest, err := backup.Verify(file)
+		if err != nil {
+			return fmt.Errorf("verification failed: %w", err)
+		}
+		log.Printf("Archive is valid: %d tables, created %s, schema version %q",
+			len(manifest.Tables), manifest.CreatedAt.Format(time.RFC3339), manifest.SchemaVersion)
+		return nil
+	}
+
+	db, err := openDatabase(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	manifest, err := backup.Restore(context.Background(), db, file, backup.RestoreOptions{
+		AllowSchemaMismatch: *allowSchemaMismatch,
+	})
+	if err != nil {
+		return fmt.Errorf("restore failed: %w", err)
+	}
+
+	for _, table := range manifest.Tables {
+		log.Printf("Restored %s: %d rows", table.Name, table.Rows)
+	}
+	return nil
+}
+
+// runSeed creates demo users with generated sessions
+func runSeed(cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("seed", flag.ExitOnError)
+	users := flags.Int("users", 1, "Number of users")
+	sessions := flags.Int("sessions", 50, "Number of sessions per user")
+	days := flags.Int("days", 60, "Spread the sessions over this many days before -end")
+	end := flags.String("end", "", "Latest session date YYYY-MM-DD (default today), fix it for reproducible dates")
+	seed := flags.Int64("seed", 1, "Random seed, the same seed generates the same data")
+	prefix := flags.String("prefix", "demo", "User ID prefix, users are named prefix1, prefix2, ...")
+	password := flags.String("password", "demo-password", "Password of new users")
+	timezone := flags.String("timezone", "Asia/Tokyo", "Timezone of the session times")
+	reset := flags.Bool("reset", false, "Delete the sessions of existing users first")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: server seed [flags]")
+		fmt.Fprintln(flags.Output(), "Create users with realistic generated sessions for development and load testing.")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if *users < 1 || *sessions < 0 || *days < 1 {
+		return fmt.Errorf("users and days must be positive and sessions must not be negative")
+	}
+
+	loc, err := time.LoadLocation(*timezone)
+	if err != nil {
+		return fmt.Errorf("invalid timezone: %w", err)
+	}
+	endTime := time.Now()
+	if *end != "" {
+		day, err := time.ParseInLocation("2006-01-02", *end, loc)
+		if err != nil {
+			return fmt.Errorf("invalid end date, use YYYY-MM-DD: %w", err)
+		}
+		endTime = day.Add(24*time.Hour - time.Second)
+	}
+
+	db, err := openDatabase(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	supabaseClient, err := supabase.NewClient(cfg.SupabaseURL, cfg.SupabaseServiceRole, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create Supabase client: %w", err)
+	}
+
+	seeder := seedpkg.NewSeeder(
+		repository.NewUserRepository(db),
+		repository.NewSessionRepository(supabaseClient),
+		service.NewPasswordService(),
+	)
+
+	for i := 0; i < *users; i++ {
+		userID := fmt.Sprintf("%s%d", *prefix, i+1)
+		_, err := seeder.SeedUser(context.Background(), userID, *password, seedpkg.Options{
+			Sessions: *sessions,
+			Days:     *days,
+			End:      endTime,
+			Seed:     *seed + int64(i),
+			Location: loc,
+		}, *reset)
+		if err != nil {
+			return fmt.Errorf("failed to seed %s: %w", userID, err)
+		}
+		log.Printf("Seeded %s with %d sessions", userID, *sessions)
+	}
+
+	return nil
+}
+
+// runWebhookReceiver serves a local endpoint that logs webhook deliveries and
+// verifies their signatures, for testing webhooks during development
+func runWebhookReceiver(args []string) error {
+	flags := flag.NewFlagSet("webhook-receiver", flag.ExitOnError)
+	addr := flags.String("addr", "127.0.0.1:9000", "Listen address")
+	secret := flags.String("secret", "", "Webhook secret used to verify signatures, not verified when empty")
+	status := flags.Int("status", http.StatusOK, "Status code to respond with, use a 5xx code to test retries")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: server webhook-receiver [flags]")
+		fmt.Fprintln(flags.Output(), "Log incoming webhook deliveries. Register http://<addr>/ as webhook URL with ENVIRONMENT=development.")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		verified := "not verified"
+		if *secret != "" {
+			verified = "valid signature"
+			if err := webhook.Verify(*secret, r.Header.Get(webhook.SignatureHeader), body, time.Now(), webhook.DefaultTolerance); err != nil {
+				log.Printf("Rejected delivery %s: %v", r.Header.Get(webhook.DeliveryHeader), err)
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+		}
+
+		var pretty bytes.Buffer
+		if err := json.Indent(&pretty, body, "", "  "); err != nil {
+			pretty.Write(body)
+		}
+		log.Printf("%s delivery %s (%s)\n%s", r.Header.Get(webhook.EventHeader), r.Header.Get(webhook.DeliveryHeader), verified, pretty.String())
+
+		w.WriteHeader(*status)
+	}
+
+	log.Printf("Webhook receiver listening on http://%s/", *addr)
+	return http.ListenAndServe(*addr, http.HandlerFunc(handler))
+}
@@ -1,8 +1,11 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"log"
+	"os"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
@@ -13,7 +16,10 @@ import (
 	"github.com/toof-jp/shisha-log/backend/internal/api"
 	"github.com/toof-jp/shisha-log/backend/internal/auth"
 	"github.com/toof-jp/shisha-log/backend/internal/config"
+	"github.com/toof-jp/shisha-log/backend/internal/demo"
+	"github.com/toof-jp/shisha-log/backend/internal/events"
 	"github.com/toof-jp/shisha-log/backend/internal/repository"
+	"github.com/toof-jp/shisha-log/backend/internal/seed"
 	"github.com/toof-jp/shisha-log/backend/internal/service"
 	"github.com/toof-jp/shisha-log/backend/internal/version"
 )
@@ -44,6 +50,14 @@ func main() {
 		log.Fatal("Failed to load config:", err)
 	}
 
+	// Run a maintenance command such as backup or restore instead of the server
+	if len(os.Args) > 1 {
+		if err := runCommand(cfg, os.Args[1], os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	// Initialize database connection
 	db, err := sql.Open("postgres", cfg.DatabaseURL)
 	if err != nil {
@@ -73,13 +87,60 @@ func main() {
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(db)
 	sessionRepo := repository.NewSessionRepository(supabaseClient)
+	equipmentRepo := repository.NewEquipmentRepository(supabaseClient)
+	inventoryRepo := repository.NewInventoryRepository(supabaseClient)
+	recipeRepo := repository.NewRecipeRepository(supabaseClient)
+	exchangeRateRepo := repository.NewExchangeRateRepository(supabaseClient)
+	budgetRepo := repository.NewBudgetRepository(supabaseClient)
+	feedTokenRepo := repository.NewFeedTokenRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	accountRepo := repository.NewAccountRepository(db)
+
+	// Initialize event bus
+	eventBus := events.NewBus()
+	eventBus.Subscribe(events.BudgetBreached, func(event events.Event) {
+		log.Printf("Budget breached for user %s: %+v", event.UserID, event.Data)
+	})
+
+	// Deliver session events to user webhooks. Local receivers are only reachable in development.
+	webhookService := service.NewWebhookService(webhookRepo, cfg.Environment == "development")
+	for _, eventType := range events.SessionEvents {
+		eventBus.Subscribe(eventType, webhookService.HandleEvent)
+	}
+	go webhookService.Run(context.Background())
+
+	budgetService := service.NewBudgetService(budgetRepo, sessionRepo, userRepo, exchangeRateRepo, eventBus)
+	accountService := service.NewAccountService(accountRepo, sessionRepo, equipmentRepo, inventoryRepo, recipeRepo, budgetRepo)
 
 	// Initialize handlers
 	authHandler := api.NewAuthHandler(userRepo, passwordService, jwtService)
-	sessionHandler := api.NewSessionHandler(sessionRepo)
+	sessionHandler := api.NewSessionHandler(sessionRepo, equipmentRepo, inventoryRepo, recipeRepo, userRepo, budgetService, eventBus)
+	equipmentHandler := api.NewEquipmentHandler(equipmentRepo, sessionRepo)
+	inventoryHandler := api.NewInventoryHandler(inventoryRepo)
+	recipeHandler := api.NewRecipeHandler(recipeRepo, sessionRepo)
+	spendingHandler := api.NewSpendingHandler(sessionRepo, userRepo, exchangeRateRepo)
+	exchangeRateHandler := api.NewExchangeRateHandler(exchangeRateRepo)
+	budgetHandler := api.NewBudgetHandler(budgetRepo, userRepo, budgetService)
+	statsHandler := api.NewStatsHandler(sessionRepo, userRepo, exchangeRateRepo)
+	flavorHandler := api.NewFlavorHandler(sessionRepo)
+	recommendationHandler := api.NewRecommendationHandler(sessionRepo, inventoryRepo)
+	exportHandler := api.NewExportHandler(sessionRepo, userRepo, exchangeRateRepo)
+	importHandler := api.NewImportHandler(sessionRepo, userRepo)
+	feedHandler := api.NewFeedHandler(feedTokenRepo, sessionRepo, cfg.PublicAPIURL)
+	webhookHandler := api.NewWebhookHandler(webhookRepo, webhookService)
+	accountHandler := api.NewAccountHandler(sessionRepo, userRepo, equipmentRepo, inventoryRepo, recipeRepo, budgetRepo, accountService)
+
+	// Initialize demo account (disabled when no demo user ID is configured)
+	var demoAccount *demo.Account
+	if cfg.DemoUserID != "" {
+		demoAccount = demo.NewAccount(seed.NewSeeder(userRepo, sessionRepo, passwordService), cfg.DemoUserID)
+		go demoAccount.Run(context.Background(), parseDuration(cfg.DemoResetInterval, 6*time.Hour))
+	}
+	demoHandler := api.NewDemoHandler(demoAccount, jwtService, parseDuration(cfg.DemoTokenDuration, time.Hour))
 
 	// Initialize auth middleware
 	authMiddleware := auth.NewAuthMiddleware(jwtService)
+	adminMiddleware := auth.NewAdminMiddleware(cfg.AdminToken)
 
 	// Create Echo instance
 	e := echo.New()
@@ -92,7 +153,7 @@ func main() {
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins:     cfg.AllowedOrigins,
 		AllowMethods:     []string{echo.GET, echo.HEAD, echo.PUT, echo.PATCH, echo.POST, echo.DELETE},
-		AllowHeaders:     []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization},
+		AllowHeaders:     []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization, "X-Admin-Token"},
 		AllowCredentials: true, // Allow cookies
 	}))
 
@@ -127,19 +188,30 @@ func main() {
 	authGroup.POST("/refresh", authHandler.Refresh)
 	authGroup.POST("/request-password-reset", authHandler.RequestPasswordReset)
 	authGroup.POST("/reset-password", authHandler.ResetPassword)
+	authGroup.POST("/demo", demoHandler.CreateDemoSession)
+
+	// Calendar feed routes (public, authorized by the secret token)
+	apiGroup.GET("/feeds/:token/sessions.ics", feedHandler.GetSessionsICS)
 
 	// Protected auth routes
 	protectedAuth := authGroup.Group("")
 	protectedAuth.Use(authMiddleware.Authenticate)
-	protectedAuth.POST("/change-password", authHandler.ChangePassword)
+	protectedAuth.POST("/change-password", authHandler.ChangePassword, auth.DemoReadOnly)
 	protectedAuth.POST("/logout", authHandler.Logout)
 
 	// Protected routes
 	protected := apiGroup.Group("")
 	protected.Use(authMiddleware.Authenticate)
+	protected.Use(auth.DemoReadOnly)
 
 	// User routes
 	protected.GET("/users/me", authHandler.GetCurrentUser)
+	protected.PUT("/users/me", authHandler.UpdateCurrentUser)
+	protected.GET("/users/me/export", accountHandler.ExportAccount)
+	protected.POST("/users/me/import", accountHandler.ImportAccount)
+	protected.GET("/users/me/feed", feedHandler.GetFeed)
+	protected.POST("/users/me/feed", feedHandler.RegenerateFeed)
+	protected.DELETE("/users/me/feed", feedHandler.RevokeFeed)
 
 	// Session routes
 	protected.POST("/sessions", sessionHandler.CreateSession)
@@ -147,22 +219,109 @@ func main() {
 	protected.GET("/sessions/calendar", sessionHandler.GetCalendarData)
 	protected.GET("/sessions/by-date", sessionHandler.GetSessionsByDate)
 	protected.GET("/sessions/:id", sessionHandler.GetSession)
+	protected.GET("/sessions/:id/similar", sessionHandler.GetSimilarSessions)
 	protected.PUT("/sessions/:id", sessionHandler.UpdateSession)
 	protected.DELETE("/sessions/:id", sessionHandler.DeleteSession)
 
 	// Flavor statistics route
 	protected.GET("/flavors/stats", sessionHandler.GetFlavorStats)
+	protected.GET("/flavors/pairs", flavorHandler.GetFlavorPairs)
+	protected.GET("/flavors/:name/partners", flavorHandler.GetFlavorPartners)
 
 	// Store and creator statistics routes
 	protected.GET("/stores/stats", sessionHandler.GetStoreStats)
 	protected.GET("/creators/stats", sessionHandler.GetCreatorStats)
 
+	// Brand statistics route
+	protected.GET("/brands/stats", flavorHandler.GetBrandStats)
+
 	// Order statistics route
 	protected.GET("/orders/stats", sessionHandler.GetOrderStats)
 
+	// Equipment routes
+	protected.POST("/equipment", equipmentHandler.CreateEquipment)
+	protected.GET("/equipment", equipmentHandler.GetUserEquipment)
+	protected.GET("/equipment/stats", equipmentHandler.GetEquipmentStats)
+	protected.GET("/equipment/:id", equipmentHandler.GetEquipment)
+	protected.PUT("/equipment/:id", equipmentHandler.UpdateEquipment)
+	protected.DELETE("/equipment/:id", equipmentHandler.DeleteEquipment)
+
+	// Tobacco inventory routes
+	protected.POST("/inventory", inventoryHandler.CreateInventoryItem)
+	protected.GET("/inventory", inventoryHandler.GetInventory)
+	protected.GET("/inventory/:id", inventoryHandler.GetInventoryItem)
+	protected.PUT("/inventory/:id", inventoryHandler.UpdateInventoryItem)
+	protected.DELETE("/inventory/:id", inventoryHandler.DeleteInventoryItem)
+
+	// Recipe routes
+	protected.POST("/recipes", recipeHandler.CreateRecipe)
+	protected.GET("/recipes", recipeHandler.GetUserRecipes)
+	protected.GET("/recipes/stats", recipeHandler.GetRecipeStats)
+	protected.GET("/recipes/:id", recipeHandler.GetRecipe)
+	protected.PUT("/recipes/:id", recipeHandler.UpdateRecipe)
+	protected.DELETE("/recipes/:id", recipeHandler.DeleteRecipe)
+
+	// Spending statistics route
+	protected.GET("/spending/stats", spendingHandler.GetSpendingStats)
+
+	// Activity statistics routes
+	protected.GET("/stats/timeseries", statsHandler.GetTimeSeries)
+	protected.GET("/stats/heatmap", statsHandler.GetHeatmap)
+	protected.GET("/stats/habits", statsHandler.GetHabitStats)
+	protected.GET("/stats/discoveries", statsHandler.GetDiscoveryStats)
+
+	// Recommendation route
+	protected.GET("/recommendations", recommendationHandler.GetRecommendations)
+
+	// Report routes
+	protected.GET("/reports/year/:year", statsHandler.GetYearReport)
+
+	// Export routes
+	protected.GET("/export/sessions.csv", exportHandler.ExportSessionsCSV)
+	protected.GET("/export/journal", exportHandler.ExportJournal)
+
+	// Import routes
+	protected.POST("/import/csv", importHandler.ImportCSV)
+
+	// Budget routes
+	protected.POST("/budgets", budgetHandler.CreateBudget)
+	protected.GET("/budgets", budgetHandler.GetUserBudgets)
+	protected.GET("/budgets/status", budgetHandler.GetBudgetStatus)
+	protected.GET("/budgets/:id", budgetHandler.GetBudget)
+	protected.PUT("/budgets/:id", budgetHandler.UpdateBudget)
+	protected.DELETE("/budgets/:id", budgetHandler.DeleteBudget)
+
+	// Webhook routes
+	protected.POST("/webhooks", webhookHandler.CreateWebhook)
+	protected.GET("/webhooks", webhookHandler.GetWebhooks)
+	protected.GET("/webhooks/:id", webhookHandler.GetWebhook)
+	protected.PUT("/webhooks/:id", webhookHandler.UpdateWebhook)
+	protected.DELETE("/webhooks/:id", webhookHandler.DeleteWebhook)
+	protected.POST("/webhooks/:id/rotate-secret", webhookHandler.RotateWebhookSecret)
+	protected.POST("/webhooks/:id/ping", webhookHandler.PingWebhook)
+	protected.GET("/webhooks/:id/deliveries", webhookHandler.GetWebhookDeliveries)
+	protected.POST("/webhooks/:id/deliveries/:delivery_id/redeliver", webhookHandler.RedeliverWebhookDelivery)
+
+	// Exchange rate routes
+	protected.GET("/exchange-rates", exchangeRateHandler.GetExchangeRates)
+
+	// Admin routes
+	adminGroup := apiGroup.Group("/admin")
+	adminGroup.Use(adminMiddleware.Authenticate)
+	adminGroup.POST("/exchange-rates/import", exchangeRateHandler.ImportExchangeRates)
+
 	// Start server
 	log.Printf("Server starting on port %s", cfg.Port)
 	if err := e.Start(":" + cfg.Port); err != nil {
 		log.Fatal("Failed to start server:", err)
 	}
 }
+
+// parseDuration parses a configured duration, falling back to the default when it is invalid
+func parseDuration(value string, defaultValue time.Duration) time.Duration {
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed <= 0 {
+		return defaultValue
+	}
+	return parsed
+}
@@ -23,6 +23,87 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/admin/exchange-rates/import": {
+            "post": {
+                "description": "Import exchange rates into the local rate table (admin only). Accepts JSON or CSV with the columns date,base,quote,rate. Existing rates for the same date and currency pair are replaced.",
+                "consumes": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Import exchange rates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Source of the rates (CSV only)",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Exchange rates",
+                        "name": "rates",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportExchangeRatesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Import result",
+                        "schema": {
+                            "$ref": "#/definitions/models.ExchangeRateImportResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid admin token",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to import exchange rates",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
         "/auth/change-password": {
             "post": {
                 "security": [
@@ -108,6 +189,70 @@ const docTemplate = `{
                 }
             }
         },
+        "/auth/demo": {
+            "post": {
+                "description": "Issue a short-lived token for the shared read-only demo account. Its data is regenerated periodically and write requests are rejected with 403.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Start a demo session",
+                "responses": {
+                    "200": {
+                        "description": "Demo token issued",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "expires_at": {
+                                    "type": "string"
+                                },
+                                "token": {
+                                    "type": "string"
+                                },
+                                "user": {
+                                    "$ref": "#/definitions/models.User"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Demo mode is disabled",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Demo account is not ready yet",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
         "/auth/login": {
             "post": {
                 "description": "Authenticate a user and receive a JWT token",
@@ -512,66 +657,52 @@ const docTemplate = `{
                 }
             }
         },
-        "/creators/stats": {
+        "/brands/stats": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Get creator statistics for the authenticated user",
+                "description": "Get session counts, main flavor share and top flavors per brand, and the brand diversity per week, month or year",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "statistics"
                 ],
-                "summary": "Get creator statistics",
-                "responses": {
-                    "200": {
-                        "description": "Creator statistics",
-                        "schema": {
-                            "$ref": "#/definitions/models.CreatorStats"
-                        }
+                "summary": "Get brand statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "month",
+                        "description": "Diversity bucket size: week, month or year",
+                        "name": "bucket",
+                        "in": "query"
                     },
-                    "500": {
-                        "description": "Failed to get creator statistics",
-                        "schema": {
-                            "type": "object",
-                            "properties": {
-                                "error": {
-                                    "type": "string"
-                                }
-                            }
-                        }
-                    }
-                }
-            }
-        },
-        "/flavors/stats": {
-            "get": {
-                "security": [
                     {
-                        "Bearer": []
+                        "type": "string",
+                        "description": "Timezone (default UTC)",
+                        "name": "timezone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Number of top flavors per brand",
+                        "name": "flavor_limit",
+                        "in": "query"
                     }
                 ],
-                "description": "Get flavor usage statistics for the authenticated user",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "statistics"
-                ],
-                "summary": "Get flavor statistics",
                 "responses": {
                     "200": {
-                        "description": "Flavor statistics",
+                        "description": "Brand statistics",
                         "schema": {
-                            "$ref": "#/definitions/models.FlavorStats"
+                            "$ref": "#/definitions/models.BrandStats"
                         }
                     },
-                    "500": {
-                        "description": "Failed to get flavor statistics",
+                    "400": {
+                        "description": "Invalid parameters",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -580,34 +711,9 @@ const docTemplate = `{
                                 }
                             }
                         }
-                    }
-                }
-            }
-        },
-        "/orders/stats": {
-            "get": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "description": "Get order statistics for the authenticated user",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "statistics"
-                ],
-                "summary": "Get order statistics",
-                "responses": {
-                    "200": {
-                        "description": "Order statistics",
-                        "schema": {
-                            "$ref": "#/definitions/models.OrderStats"
-                        }
                     },
                     "500": {
-                        "description": "Failed to get order statistics",
+                        "description": "Failed to get brand statistics",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -620,63 +726,38 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions": {
+        "/budgets": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Get paginated list of sessions for the authenticated user",
+                "description": "Get all budgets of the authenticated user",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "sessions"
-                ],
-                "summary": "Get user's sessions",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 20,
-                        "description": "Number of items per page",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 0,
-                        "description": "Number of items to skip",
-                        "name": "offset",
-                        "in": "query"
-                    }
+                    "budgets"
                 ],
+                "summary": "Get user's budgets",
                 "responses": {
                     "200": {
-                        "description": "Paginated sessions list",
+                        "description": "Budget list",
                         "schema": {
                             "type": "object",
                             "properties": {
-                                "limit": {
-                                    "type": "integer"
-                                },
-                                "offset": {
-                                    "type": "integer"
-                                },
-                                "sessions": {
+                                "budgets": {
                                     "type": "array",
                                     "items": {
-                                        "$ref": "#/definitions/models.SessionWithFlavors"
+                                        "$ref": "#/definitions/models.Budget"
                                     }
-                                },
-                                "total": {
-                                    "type": "integer"
                                 }
                             }
                         }
                     },
                     "500": {
-                        "description": "Failed to get sessions",
+                        "description": "Failed to get budgets",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -694,7 +775,7 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Create a new shisha session for the authenticated user",
+                "description": "Create a weekly or monthly spending cap, optionally limited to one store",
                 "consumes": [
                     "application/json"
                 ],
@@ -702,25 +783,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sessions"
+                    "budgets"
                 ],
-                "summary": "Create a new session",
+                "summary": "Create a budget",
                 "parameters": [
                     {
-                        "description": "Session data",
-                        "name": "session",
+                        "description": "Budget data",
+                        "name": "budget",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateSessionRequest"
+                            "$ref": "#/definitions/models.CreateBudgetRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Created session with flavors",
+                        "description": "Created budget",
                         "schema": {
-                            "$ref": "#/definitions/models.SessionWithFlavors"
+                            "$ref": "#/definitions/models.Budget"
                         }
                     },
                     "400": {
@@ -735,7 +816,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Failed to create session",
+                        "description": "Failed to create budget",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -748,67 +829,38 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/by-date": {
+        "/budgets/status": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Get all sessions for a specific date",
+                "description": "Get the spend of the current period against each budget and the projected spend at the end of the period",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "sessions"
-                ],
-                "summary": "Get sessions by date",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Date in YYYY-MM-DD format",
-                        "name": "date",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Timezone (default UTC)",
-                        "name": "timezone",
-                        "in": "query"
-                    }
+                    "budgets"
                 ],
+                "summary": "Get budget status",
                 "responses": {
                     "200": {
-                        "description": "Sessions for the specified date",
+                        "description": "Budget statuses",
                         "schema": {
                             "type": "object",
                             "properties": {
-                                "date": {
-                                    "type": "string"
-                                },
-                                "sessions": {
+                                "budgets": {
                                     "type": "array",
                                     "items": {
-                                        "$ref": "#/definitions/models.SessionWithFlavors"
+                                        "$ref": "#/definitions/models.BudgetStatus"
                                     }
                                 }
                             }
                         }
                     },
-                    "400": {
-                        "description": "Invalid date format",
-                        "schema": {
-                            "type": "object",
-                            "properties": {
-                                "error": {
-                                    "type": "string"
-                                }
-                            }
-                        }
-                    },
                     "500": {
-                        "description": "Failed to get sessions",
+                        "description": "Failed to get budget status",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -821,55 +873,50 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/calendar": {
+        "/budgets/{id}": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Get session counts for each day in a specific month",
+                "description": "Get a specific budget by its ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "sessions"
+                    "budgets"
                 ],
-                "summary": "Get calendar data",
+                "summary": "Get a budget by ID",
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Year",
-                        "name": "year",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Month (1-12)",
-                        "name": "month",
-                        "in": "query",
-                        "required": true
-                    },
                     {
                         "type": "string",
-                        "description": "Timezone (default UTC)",
-                        "name": "timezone",
-                        "in": "query"
+                        "description": "Budget ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Map of date strings to session counts",
+                        "description": "Budget details",
+                        "schema": {
+                            "$ref": "#/definitions/models.Budget"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "integer"
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
                             }
                         }
                     },
-                    "400": {
-                        "description": "Invalid parameters",
+                    "404": {
+                        "description": "Budget not found",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -880,7 +927,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Failed to get calendar data",
+                        "description": "Internal server error",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -891,37 +938,58 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/sessions/{id}": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Get a specific session by its ID",
+                "description": "Update an existing budget. Changing a budget allows a new breach event in the current period.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "sessions"
+                    "budgets"
                 ],
-                "summary": "Get a session by ID",
+                "summary": "Update a budget",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Session ID",
+                        "description": "Budget ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Updated budget data",
+                        "name": "budget",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateBudgetRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Session details with flavors",
+                        "description": "Updated budget",
                         "schema": {
-                            "$ref": "#/definitions/models.SessionWithFlavors"
+                            "$ref": "#/definitions/models.Budget"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
                         }
                     },
                     "403": {
@@ -936,7 +1004,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Session not found",
+                        "description": "Budget not found",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -947,7 +1015,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Failed to update budget",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -959,50 +1027,43 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
+            "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Update an existing session",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Delete a budget",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "sessions"
+                    "budgets"
                 ],
-                "summary": "Update a session",
+                "summary": "Delete a budget",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Session ID",
+                        "description": "Budget ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Updated session data",
-                        "name": "session",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.UpdateSessionRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Updated session",
+                        "description": "Budget deleted successfully",
                         "schema": {
-                            "$ref": "#/definitions/models.SessionWithFlavors"
+                            "type": "object",
+                            "properties": {
+                                "message": {
+                                    "type": "string"
+                                }
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Invalid request body",
+                    "403": {
+                        "description": "Access denied",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -1012,8 +1073,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "403": {
-                        "description": "Access denied",
+                    "404": {
+                        "description": "Budget not found",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -1023,8 +1084,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Session not found",
+                    "500": {
+                        "description": "Failed to delete budget",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -1033,9 +1094,34 @@ const docTemplate = `{
                                 }
                             }
                         }
+                    }
+                }
+            }
+        },
+        "/creators/stats": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get creator statistics for the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "statistics"
+                ],
+                "summary": "Get creator statistics",
+                "responses": {
+                    "200": {
+                        "description": "Creator statistics",
+                        "schema": {
+                            "$ref": "#/definitions/models.CreatorStats"
+                        }
                     },
                     "500": {
-                        "description": "Failed to update session",
+                        "description": "Failed to get creator statistics",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -1046,44 +1132,59 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/equipment": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Delete a session by ID",
+                "description": "Get the equipment inventory of the authenticated user",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "sessions"
+                    "equipment"
                 ],
-                "summary": "Delete a session",
+                "summary": "Get user's equipment",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Session ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Filter by category (bowl, hookah, heat_management, charcoal)",
+                        "name": "category",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Session deleted successfully",
+                        "description": "Equipment list",
                         "schema": {
                             "type": "object",
                             "properties": {
-                                "message": {
+                                "equipment": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/models.Equipment"
+                                    }
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid category",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
                                     "type": "string"
                                 }
                             }
                         }
                     },
-                    "403": {
-                        "description": "Access denied",
+                    "500": {
+                        "description": "Failed to get equipment",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -1092,9 +1193,46 @@ const docTemplate = `{
                                 }
                             }
                         }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Add a bowl, hookah, heat management device or charcoal type to the user's equipment inventory",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "equipment"
+                ],
+                "summary": "Create equipment",
+                "parameters": [
+                    {
+                        "description": "Equipment data",
+                        "name": "equipment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateEquipmentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created equipment",
+                        "schema": {
+                            "$ref": "#/definitions/models.Equipment"
+                        }
                     },
-                    "404": {
-                        "description": "Session not found",
+                    "400": {
+                        "description": "Invalid request body",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -1105,7 +1243,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Failed to delete session",
+                        "description": "Failed to create equipment",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -1118,30 +1256,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/stores/stats": {
+        "/equipment/stats": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Get store visit statistics for the authenticated user",
+                "description": "Get usage frequency and average session rating per piece of equipment",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "statistics"
                 ],
-                "summary": "Get store statistics",
+                "summary": "Get equipment statistics",
                 "responses": {
                     "200": {
-                        "description": "Store statistics",
+                        "description": "Equipment statistics",
                         "schema": {
-                            "$ref": "#/definitions/models.StoreStats"
+                            "$ref": "#/definitions/models.EquipmentStats"
                         }
                     },
                     "500": {
-                        "description": "Failed to get store statistics",
+                        "description": "Failed to get equipment statistics",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -1154,30 +1292,39 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/me": {
+        "/equipment/{id}": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Get the current authenticated user's information",
+                "description": "Get a specific piece of equipment by its ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "equipment"
+                ],
+                "summary": "Get equipment by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Equipment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get current user",
                 "responses": {
                     "200": {
-                        "description": "User information",
+                        "description": "Equipment details",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/models.Equipment"
                         }
                     },
-                    "400": {
-                        "description": "Invalid user ID",
+                    "403": {
+                        "description": "Access denied",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -1188,7 +1335,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "User not found",
+                        "description": "Equipment not found",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -1210,268 +1357,5847 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "models.CreateFlavorRequest": {
-            "type": "object",
-            "properties": {
-                "brand": {
-                    "type": "string"
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Update an existing piece of equipment",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "equipment"
+                ],
+                "summary": "Update equipment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Equipment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated equipment data",
+                        "name": "equipment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateEquipmentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated equipment",
+                        "schema": {
+                            "$ref": "#/definitions/models.Equipment"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Equipment not found",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update equipment",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete a piece of equipment. Sessions that used it are kept.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "equipment"
+                ],
+                "summary": "Delete equipment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Equipment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Equipment deleted successfully",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "message": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Equipment not found",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete equipment",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/exchange-rates": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get the locally maintained exchange rates, optionally for one currency pair",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "exchange-rates"
+                ],
+                "summary": "Get exchange rates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Base currency (ISO 4217)",
+                        "name": "base",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Quote currency (ISO 4217)",
+                        "name": "quote",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Exchange rates",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "rates": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/models.ExchangeRate"
+                                    }
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get exchange rates",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/export/journal": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Render the sessions in a date range as a printable journal grouped by month, with flavors, store, creator, amount and notes, preceded by a summary (sessions, active days, spend, average rating and top flavors, stores and creators). Markdown or PDF (A4, Japanese Mincho font).",
+                "produces": [
+                    "text/markdown",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "export"
+                ],
+                "summary": "Export session journal",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "markdown",
+                        "description": "markdown or pdf",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only sessions on or after this date (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only sessions on or before this date (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Timezone of the dates (default UTC)",
+                        "name": "timezone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the spend (ISO 4217, default is the user's default currency)",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Number of entries in the top lists",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Journal",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to export journal",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/export/sessions.csv": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Stream the user's sessions (newest session date first) as UTF-8 CSV. With layout=columns each session is one row with numbered flavor and brand columns; with layout=rows each flavor is one row.",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "export"
+                ],
+                "summary": "Export sessions as CSV",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "columns",
+                        "description": "columns or rows",
+                        "name": "layout",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Start the file with a UTF-8 byte order mark for Excel",
+                        "name": "bom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only sessions on or after this date (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only sessions on or before this date (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Timezone of the dates (default UTC)",
+                        "name": "timezone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Maximum number of sessions (0 for all)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of matching sessions to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Sessions CSV",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to export sessions",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/{token}/sessions.ics": {
+            "get": {
+                "description": "Public iCalendar feed with one event per session, authorized by the secret token in the URL. The summary is the mix name or the flavors, the location the store and the description the notes. Sessions with a duration get an end time.",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Calendar feed of sessions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feed token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "iCalendar feed",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "Feed not found",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get feed",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/flavors/pairs": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get flavor pairs used in the same session with co-occurrence counts, lift/PMI scores and the average session rating",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "statistics"
+                ],
+                "summary": "Get flavor pair statistics",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Minimum number of sessions with both flavors",
+                        "name": "min_count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "count",
+                        "description": "Sort order: count, lift, pmi or rating",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Maximum number of pairs (0 for all)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Flavor pair statistics",
+                        "schema": {
+                            "$ref": "#/definitions/models.FlavorPairStats"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get flavor pairs",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/flavors/stats": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get flavor usage statistics for the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "statistics"
+                ],
+                "summary": "Get flavor statistics",
+                "responses": {
+                    "200": {
+                        "description": "Flavor statistics",
+                        "schema": {
+                            "$ref": "#/definitions/models.FlavorStats"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get flavor statistics",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/flavors/{name}/partners": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get the flavors most often combined with a flavor, with confidence, lift/PMI scores and the average session rating",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "statistics"
+                ],
+                "summary": "Get flavor partners",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Flavor name (case-insensitive)",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Minimum number of sessions with both flavors",
+                        "name": "min_count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "count",
+                        "description": "Sort order: count, lift, pmi or rating",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Maximum number of partners (0 for all)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Flavor partners",
+                        "schema": {
+                            "$ref": "#/definitions/models.FlavorPartners"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Flavor not found",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get flavor partners",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/import/csv": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Import sessions from a CSV file using a column mapping. Runs as a dry run by default, reporting parsed rows, validation errors and suspected duplicates (same date and store). With dry_run=false all valid rows are created in one batch; the commit is rejected if any row is invalid. Without a mapping, columns named like the session fields (as in the CSV export) are used.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "import"
+                ],
+                "summary": "Import sessions from CSV",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON object mapping session fields (session_date, store_name, mix_name, creator, notes, order_details, amount, currency, duration_minutes, rating, flavors, flavor_N, brand_N) to column names",
+                        "name": "mapping",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Timezone of dates without an offset (default UTC)",
+                        "name": "timezone",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Go time layout of the dates, e.g. 02/01/2006 for day-first dates",
+                        "name": "date_format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Only report what would be imported",
+                        "name": "dry_run",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Also import rows that look like duplicates",
+                        "name": "include_duplicates",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dry run result",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportCSVResult"
+                        }
+                    },
+                    "201": {
+                        "description": "Import result",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportCSVResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid file, mapping or rows",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to import sessions",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get all inventory items with remaining stock, projected run-out dates and a low-stock list",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "inventory"
+                ],
+                "summary": "Get tobacco inventory",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "default": 50,
+                        "description": "Remaining grams at or below which an item is low on stock",
+                        "name": "low_stock_grams",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Timezone (default UTC)",
+                        "name": "timezone",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Inventory report",
+                        "schema": {
+                            "$ref": "#/definitions/models.InventoryReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get inventory",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Add a tobacco package (flavor, brand, weight, purchase date, price, opened date) to the user's inventory",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "inventory"
+                ],
+                "summary": "Add a tobacco package to the inventory",
+                "parameters": [
+                    {
+                        "description": "Inventory item data",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateInventoryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created inventory item",
+                        "schema": {
+                            "$ref": "#/definitions/models.InventoryItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create inventory item",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get a specific tobacco package by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "inventory"
+                ],
+                "summary": "Get an inventory item by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Inventory item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Inventory item details",
+                        "schema": {
+                            "$ref": "#/definitions/models.InventoryItem"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Inventory item not found",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Update an existing tobacco package",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "inventory"
+                ],
+                "summary": "Update an inventory item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Inventory item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated inventory item data",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateInventoryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated inventory item",
+                        "schema": {
+                            "$ref": "#/definitions/models.InventoryItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Inventory item not found",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update inventory item",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete a tobacco package. Session flavors drawn from it are kept.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "inventory"
+                ],
+                "summary": "Delete an inventory item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Inventory item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Inventory item deleted successfully",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "message": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Inventory item not found",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete inventory item",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/stats": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get order statistics for the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "statistics"
+                ],
+                "summary": "Get order statistics",
+                "responses": {
+                    "200": {
+                        "description": "Order statistics",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderStats"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get order statistics",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/recipes": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get all saved recipes of the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipes"
+                ],
+                "summary": "Get user's recipes",
+                "responses": {
+                    "200": {
+                        "description": "Recipe list",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "recipes": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/models.RecipeWithFlavors"
+                                    }
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get recipes",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Save a mix recipe (name plus an ordered flavor list with ratios)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipes"
+                ],
+                "summary": "Create a recipe",
+                "parameters": [
+                    {
+                        "description": "Recipe data",
+                        "name": "recipe",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateRecipeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created recipe with flavors",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecipeWithFlavors"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create recipe",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/recipes/stats": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get how often each recipe was used and its average session rating",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "statistics"
+                ],
+                "summary": "Get recipe statistics",
+                "responses": {
+                    "200": {
+                        "description": "Recipe statistics",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecipeStats"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get recipe statistics",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/recipes/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get a specific recipe by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipes"
+                ],
+                "summary": "Get a recipe by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Recipe ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Recipe details with flavors",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecipeWithFlavors"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Recipe not found",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Update an existing recipe. Providing flavors replaces the whole flavor list.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipes"
+                ],
+                "summary": "Update a recipe",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Recipe ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated recipe data",
+                        "name": "recipe",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateRecipeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated recipe",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecipeWithFlavors"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Recipe not found",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update recipe",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete a recipe. Sessions logged from it keep their flavors.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipes"
+                ],
+                "summary": "Delete a recipe",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Recipe ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Recipe deleted successfully",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "message": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Recipe not found",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete recipe",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/recommendations": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Suggest untried mixes, unsmoked inventory flavors and well rated flavors to revisit, based on the session history, flavor combinations and ratings. Each suggestion comes with an explanation.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recommendations"
+                ],
+                "summary": "Get recommendations",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "default": 0.3,
+                        "description": "0 favors well rated flavors, 1 favors unfamiliar ones",
+                        "name": "exploration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only return mix, flavor or revisit suggestions",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of recommendations",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Recommendations",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecommendationList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get recommendations",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/year/{year}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get a summary of a year: sessions, spend, top flavors/stores/creators, new flavors and stores, the longest streak and the busiest month. Use format=html for a shareable page.",
+                "produces": [
+                    "application/json",
+                    "text/html"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Get year-in-review report",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Year",
+                        "name": "year",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Timezone (default UTC)",
+                        "name": "timezone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the spend (ISO 4217, default is the user's default currency)",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Number of entries in the top lists",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "json",
+                        "description": "Response format: json or html",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Year report",
+                        "schema": {
+                            "$ref": "#/definitions/models.YearReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get year report",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get paginated list of sessions for the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Get user's sessions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Number of items per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Paginated sessions list",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "limit": {
+                                    "type": "integer"
+                                },
+                                "offset": {
+                                    "type": "integer"
+                                },
+                                "sessions": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/models.SessionWithFlavors"
+                                    }
+                                },
+                                "total": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get sessions",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Create a new shisha session for the authenticated user. When recipe_id is given, flavors and mix_name are pre-filled from the recipe unless provided, and bowl_grams is split across the recipe flavors by their ratios.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Create a new session",
+                "parameters": [
+                    {
+                        "description": "Session data",
+                        "name": "session",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateSessionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created session with flavors",
+                        "schema": {
+                            "$ref": "#/definitions/models.SessionWithFlavors"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create session",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/by-date": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get all sessions for a specific date",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Get sessions by date",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Date in YYYY-MM-DD format",
+                        "name": "date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Timezone (default UTC)",
+                        "name": "timezone",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Sessions for the specified date",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "date": {
+                                    "type": "string"
+                                },
+                                "sessions": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/models.SessionWithFlavors"
+                                    }
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid date format",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get sessions",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/calendar": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get session counts for each day in a specific month",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Get calendar data",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Year",
+                        "name": "year",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Month (1-12)",
+                        "name": "month",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Timezone (default UTC)",
+                        "name": "timezone",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Map of date strings to session counts",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get calendar data",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get a specific session by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Get a session by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Session details with flavors",
+                        "schema": {
+                            "$ref": "#/definitions/models.SessionWithFlavors"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Session not found",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Update an existing session",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Update a session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated session data",
+                        "name": "session",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateSessionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated session",
+                        "schema": {
+                            "$ref": "#/definitions/models.SessionWithFlavors"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Session not found",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update session",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete a session by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Delete a session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Session deleted successfully",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "message": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Session not found",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete session",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{id}/similar": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Rank the user's other sessions by similarity to a session using flavor overlap weighted by flavor order, the same store, creator and mix name",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Get similar sessions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Number of similar sessions",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Similar sessions ordered by score",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "sessions": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/models.SimilarSession"
+                                    }
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Session not found",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get similar sessions",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/spending/stats": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get totals and averages per day, week, month and year, spend per store and creator, cost per hour and the most/least expensive sessions. Amounts are converted into the target currency using the exchange rate of each session date.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "statistics"
+                ],
+                "summary": "Get spending statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Timezone (default UTC)",
+                        "name": "timezone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Number of most/least expensive sessions",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Target currency (ISO 4217, default is the user's default currency)",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Spending statistics",
+                        "schema": {
+                            "$ref": "#/definitions/models.SpendingStats"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get spending statistics",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/discoveries": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get when each flavor, brand, store and creator first appeared and the number of novel and repeat items per month",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "statistics"
+                ],
+                "summary": "Get discovery timeline",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Timezone (default UTC)",
+                        "name": "timezone",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Discovery timeline",
+                        "schema": {
+                            "$ref": "#/definitions/models.DiscoveryStats"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get discoveries",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/habits": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get current and longest daily/weekly streaks, days between sessions, the longest break, the weekly session trend and the first/last session dates. Days are computed in the timezone like the calendar.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "statistics"
+                ],
+                "summary": "Get habit statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Timezone (default UTC)",
+                        "name": "timezone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 12,
+                        "description": "Number of weeks in the weekly trend",
+                        "name": "weeks",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Habit statistics",
+                        "schema": {
+                            "$ref": "#/definitions/models.HabitStats"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get habit statistics",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/heatmap": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get a 7x24 matrix of session counts per weekday (rows start on Monday) and hour of session_date in the timezone, with the most common day, hour and day/hour slots",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "statistics"
+                ],
+                "summary": "Get weekday/hour heatmap",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Timezone (default UTC)",
+                        "name": "timezone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only count sessions at this store",
+                        "name": "store",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only count sessions containing this flavor",
+                        "name": "flavor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Number of top slots",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Heatmap",
+                        "schema": {
+                            "$ref": "#/definitions/models.Heatmap"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get heatmap",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/timeseries": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get session counts, spend and distinct flavors per day, week or month between two dates. Buckets without sessions are returned with zero values.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "statistics"
+                ],
+                "summary": "Get session activity time series",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date in YYYY-MM-DD format, inclusive (default one year before to)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date in YYYY-MM-DD format, inclusive (default today)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "day",
+                        "description": "Bucket size: day, week or month",
+                        "name": "bucket",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Timezone (default UTC)",
+                        "name": "timezone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the spend (ISO 4217, default is the user's default currency)",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Time series",
+                        "schema": {
+                            "$ref": "#/definitions/models.TimeSeries"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get time series",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stores/stats": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get store visit statistics for the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "statistics"
+                ],
+                "summary": "Get store statistics",
+                "responses": {
+                    "200": {
+                        "description": "Store statistics",
+                        "schema": {
+                            "$ref": "#/definitions/models.StoreStats"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get store statistics",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get the current authenticated user's information",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get current user",
+                "responses": {
+                    "200": {
+                        "description": "User information",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Update the current authenticated user's settings",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update current user",
+                "parameters": [
+                    {
+                        "description": "User settings",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated user",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/export": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Stream a versioned JSON archive of everything tied to the account: the user profile (without the password hash), equipment, tobacco inventory, recipes, budgets and all sessions with their flavors. Sessions are streamed newest first. The format is described in docs/ACCOUNT_ARCHIVE.md.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Export all account data",
+                "responses": {
+                    "200": {
+                        "description": "Account archive",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to export account",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/feed": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get the iCalendar feed URL of the current user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Get calendar feed",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FeedTokenResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Feed not enabled",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get feed",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Create a new secret iCalendar feed URL for the current user. Any previous URL stops working.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Create or regenerate calendar feed",
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.FeedTokenResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create feed",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Revoke the iCalendar feed URL of the current user",
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Revoke calendar feed",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Feed not enabled",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to revoke feed",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/import": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Import an account archive created by GET /users/me/export, e.g. from another environment. In merge mode the archive is added next to the existing data; in replace mode all existing data is deleted first. IDs are remapped to stable per-account IDs, so importing the same archive again skips what was already imported. Entities that match existing data (same equipment, recipe or budget name, same inventory purchase, session at the same time and store) are reported as conflicts and the existing entity is kept. The archive is validated before anything is written and the import runs in one transaction, so a failed import changes nothing.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Import account data",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "merge",
+                        "description": "merge or replace",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Account archive",
+                        "name": "archive",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AccountImportResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid archive, archive entity or mode",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to import account",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get all webhooks of the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get user's webhooks",
+                "responses": {
+                    "200": {
+                        "description": "Webhook list",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "webhooks": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/models.Webhook"
+                                    }
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get webhooks",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Register an endpoint that receives session.created, session.updated and session.deleted events. Deliveries are signed with the returned secret in the X-Shisha-Log-Signature header (t=\u003cunix\u003e,v1=\u003chex HMAC-SHA256 of \"\u003ct\u003e.\u003cbody\u003e\"\u003e). The secret is only returned here and on rotation.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Create a webhook",
+                "parameters": [
+                    {
+                        "description": "Webhook data",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created webhook with its signing secret",
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookWithSecret"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create webhook",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get a specific webhook including its failure count",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get a webhook by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Webhook details",
+                        "schema": {
+                            "$ref": "#/definitions/models.Webhook"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Update the URL, events or description of a webhook, or enable and disable it. Disabling a webhook marks its pending deliveries as failed; enabling it resets its failure count.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Update a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated webhook data",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated webhook",
+                        "schema": {
+                            "$ref": "#/definitions/models.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update webhook",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete a webhook and its delivery log",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Delete a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Webhook deleted successfully",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "message": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete webhook",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get the deliveries of a webhook, newest first. Deliveries are kept for 30 days.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get the delivery log",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of deliveries (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset for pagination",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Delivery log",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "deliveries": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/models.WebhookDelivery"
+                                    }
+                                },
+                                "limit": {
+                                    "type": "integer"
+                                },
+                                "offset": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get deliveries",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries/{delivery_id}/redeliver": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Send the payload of a previous delivery again. The new delivery keeps the event ID so receivers can deduplicate it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Redeliver an event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Delivery ID",
+                        "name": "delivery_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "New delivery after the first attempt",
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookDelivery"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook or delivery not found",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to redeliver",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/ping": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Send a signed ping event to the webhook and return the delivery with the response of the endpoint. A failed ping is retried like other deliveries.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Send a test event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Delivery after the first attempt",
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookDelivery"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to ping webhook",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/rotate-secret": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Replace the signing secret of a webhook. Deliveries made after the rotation are signed with the new secret.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Rotate the signing secret",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Webhook with its new signing secret",
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookWithSecret"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to rotate secret",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.AccountImportConflict": {
+            "type": "object",
+            "properties": {
+                "archive_id": {
+                    "description": "ID in the archive",
+                    "type": "string"
+                },
+                "existing_id": {
+                    "description": "ID of the existing entity that was kept and is used in its place",
+                    "type": "string"
+                },
+                "kind": {
+                    "description": "equipment, inventory, recipe, budget or session",
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AccountImportCounts": {
+            "type": "object",
+            "properties": {
+                "budgets": {
+                    "type": "integer"
+                },
+                "equipment": {
+                    "type": "integer"
+                },
+                "inventory": {
+                    "type": "integer"
+                },
+                "recipes": {
+                    "type": "integer"
+                },
+                "sessions": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AccountImportResult": {
+            "type": "object",
+            "properties": {
+                "archive_version": {
+                    "type": "integer"
+                },
+                "conflicts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AccountImportConflict"
+                    }
+                },
+                "created": {
+                    "$ref": "#/definitions/models.AccountImportCounts"
+                },
+                "deleted": {
+                    "description": "Existing entities removed in replace mode",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.AccountImportCounts"
+                        }
+                    ]
+                },
+                "ids_remapped": {
+                    "description": "False when the archive came from the same account",
+                    "type": "boolean"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "skipped": {
+                    "description": "Already present with the same ID, e.g. from an earlier import",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.AccountImportCounts"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.BrandDiversityBucket": {
+            "type": "object",
+            "properties": {
+                "distinct_brands": {
+                    "type": "integer"
+                },
+                "entropy": {
+                    "description": "Shannon entropy in bits of the brand distribution of flavor uses",
+                    "type": "number"
+                },
+                "period": {
+                    "description": "YYYY-MM-DD (week start), YYYY-MM or YYYY",
+                    "type": "string"
+                },
+                "session_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BrandStats": {
+            "type": "object",
+            "properties": {
+                "brands": {
+                    "description": "Sorted by session count",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BrandUsage"
+                    }
+                },
+                "bucket": {
+                    "type": "string"
+                },
+                "diversity": {
+                    "description": "Every period from the first to the last session",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BrandDiversityBucket"
+                    }
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "unbranded_uses": {
+                    "description": "Flavor uses without a brand",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BrandUsage": {
+            "type": "object",
+            "properties": {
+                "brand": {
+                    "type": "string"
+                },
+                "flavor_count": {
+                    "description": "Flavor uses of the brand",
+                    "type": "integer"
+                },
+                "main_flavor_count": {
+                    "description": "Uses as the main flavor (flavor_order = 1)",
+                    "type": "integer"
+                },
+                "main_flavor_share": {
+                    "description": "Share of all main flavors with a brand",
+                    "type": "number"
+                },
+                "session_count": {
+                    "description": "Sessions with at least one flavor of the brand",
+                    "type": "integer"
+                },
+                "top_flavors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FlavorCount"
+                    }
+                }
+            }
+        },
+        "models.Budget": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_breached_period": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "period": {
+                    "type": "string"
+                },
+                "store_name": {
+                    "description": "nil applies the budget to all stores",
+                    "type": "string"
+                },
+                "timezone": {
+                    "description": "Timezone the periods start in",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BudgetStatus": {
+            "type": "object",
+            "properties": {
+                "breached": {
+                    "type": "boolean"
+                },
+                "budget": {
+                    "$ref": "#/definitions/models.Budget"
+                },
+                "percent_used": {
+                    "type": "number"
+                },
+                "period": {
+                    "description": "Label of the current period (YYYY-MM-DD of the week start or YYYY-MM)",
+                    "type": "string"
+                },
+                "period_end": {
+                    "description": "Exclusive",
+                    "type": "string"
+                },
+                "period_start": {
+                    "description": "Inclusive",
+                    "type": "string"
+                },
+                "projected": {
+                    "description": "Linear projection of the spend at the end of the period",
+                    "type": "number"
+                },
+                "projected_breach": {
+                    "type": "boolean"
+                },
+                "remaining": {
+                    "description": "Negative when over budget",
+                    "type": "number"
+                },
+                "session_count": {
+                    "type": "integer"
+                },
+                "spent": {
+                    "type": "number"
+                },
+                "unconverted_session_count": {
+                    "description": "Sessions skipped because no exchange rate was available",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CreateBudgetRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "name",
+                "period"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "currency": {
+                    "description": "Defaults to the user's default currency",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "period": {
+                    "description": "weekly or monthly",
+                    "type": "string"
+                },
+                "store_name": {
+                    "type": "string"
+                },
+                "timezone": {
+                    "description": "Defaults to UTC",
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateEquipmentRequest": {
+            "type": "object",
+            "required": [
+                "category",
+                "name"
+            ],
+            "properties": {
+                "brand": {
+                    "type": "string"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateFlavorRequest": {
+            "type": "object",
+            "properties": {
+                "brand": {
+                    "type": "string"
+                },
+                "flavor_name": {
+                    "type": "string"
+                },
+                "grams": {
+                    "description": "Grams drawn from the inventory item",
+                    "type": "number"
+                },
+                "inventory_id": {
+                    "description": "Inventory item the tobacco was drawn from",
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateInventoryRequest": {
+            "type": "object",
+            "required": [
+                "flavor_name",
+                "weight_grams"
+            ],
+            "properties": {
+                "brand": {
+                    "type": "string"
+                },
+                "flavor_name": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "opened_date": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "purchase_date": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "weight_grams": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.CreateRecipeFlavorRequest": {
+            "type": "object",
+            "required": [
+                "flavor_name"
+            ],
+            "properties": {
+                "brand": {
+                    "type": "string"
+                },
+                "flavor_name": {
+                    "type": "string"
+                },
+                "ratio": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.CreateRecipeRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "flavors": {
+                    "description": "Ordered, the first flavor is the main flavor",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CreateRecipeFlavorRequest"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateSessionRequest": {
+            "type": "object",
+            "required": [
+                "session_date"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "bowl_grams": {
+                    "description": "With recipe_id, splits this weight across the recipe flavors by their ratios",
+                    "type": "number"
+                },
+                "creator": {
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "ISO 4217 code, defaults to the user's default currency",
+                    "type": "string"
+                },
+                "duration_minutes": {
+                    "type": "integer"
+                },
+                "equipment_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "flavors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CreateFlavorRequest"
+                    }
+                },
+                "mix_name": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "order_details": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "integer"
+                },
+                "recipe_id": {
+                    "type": "string"
+                },
+                "session_date": {
+                    "type": "string"
+                },
+                "store_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateWebhookRequest": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "events": {
+                    "description": "Defaults to all session events",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreatorCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "creator": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreatorStats": {
+            "type": "object",
+            "properties": {
+                "creators": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CreatorCount"
+                    }
+                }
+            }
+        },
+        "models.Discovery": {
+            "type": "object",
+            "properties": {
+                "first_seen_at": {
+                    "type": "string"
+                },
+                "first_seen_date": {
+                    "description": "YYYY-MM-DD in the timezone",
+                    "type": "string"
+                },
+                "first_session_id": {
+                    "type": "string"
+                },
+                "last_seen_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "session_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DiscoveryMonth": {
+            "type": "object",
+            "properties": {
+                "month": {
+                    "description": "YYYY-MM",
+                    "type": "string"
+                },
+                "new_brands": {
+                    "type": "integer"
+                },
+                "new_creators": {
+                    "type": "integer"
+                },
+                "new_flavors": {
+                    "description": "Flavors used for the first time",
+                    "type": "integer"
+                },
+                "new_stores": {
+                    "type": "integer"
+                },
+                "novelty_rate": {
+                    "description": "New flavors divided by distinct flavors of the month",
+                    "type": "number"
+                },
+                "repeat_brands": {
+                    "type": "integer"
+                },
+                "repeat_creators": {
+                    "type": "integer"
+                },
+                "repeat_flavors": {
+                    "description": "Distinct flavors used before this month",
+                    "type": "integer"
+                },
+                "repeat_stores": {
+                    "type": "integer"
+                },
+                "session_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DiscoveryStats": {
+            "type": "object",
+            "properties": {
+                "brands": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Discovery"
+                    }
+                },
+                "creators": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Discovery"
+                    }
+                },
+                "flavors": {
+                    "description": "Sorted by first appearance",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Discovery"
+                    }
+                },
+                "monthly": {
+                    "description": "Every month from the first to the last session",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DiscoveryMonth"
+                    }
+                },
+                "stores": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Discovery"
+                    }
+                },
+                "timezone": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Equipment": {
+            "type": "object",
+            "properties": {
+                "brand": {
+                    "type": "string"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.EquipmentStats": {
+            "type": "object",
+            "properties": {
+                "equipment": {
+                    "description": "Sorted by session count",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EquipmentUsage"
+                    }
+                }
+            }
+        },
+        "models.EquipmentUsage": {
+            "type": "object",
+            "properties": {
+                "average_rating": {
+                    "description": "nil when no session using it is rated",
+                    "type": "number"
+                },
+                "brand": {
+                    "type": "string"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "equipment_id": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rated_count": {
+                    "type": "integer"
+                },
+                "session_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ExchangeRate": {
+            "type": "object",
+            "properties": {
+                "base_currency": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "quote_currency": {
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "number"
+                },
+                "rate_date": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ExchangeRateImport": {
+            "type": "object",
+            "properties": {
+                "base": {
+                    "description": "ISO 4217 code",
+                    "type": "string"
+                },
+                "date": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "quote": {
+                    "description": "ISO 4217 code",
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.ExchangeRateImportResult": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "description": "Rows that were rejected",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "imported": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.FeedTokenResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "description": "Subscription URL including the secret token",
+                    "type": "string"
+                }
+            }
+        },
+        "models.FlavorCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "flavor_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FlavorPair": {
+            "type": "object",
+            "properties": {
+                "average_rating": {
+                    "description": "nil when no session with both flavors is rated",
+                    "type": "number"
+                },
+                "count": {
+                    "description": "Sessions containing both flavors",
+                    "type": "integer"
+                },
+                "flavor_a": {
+                    "description": "FlavorA sorts before FlavorB",
+                    "type": "string"
+                },
+                "flavor_b": {
+                    "type": "string"
+                },
+                "lift": {
+                    "description": "Greater than 1 when paired more often than by chance",
+                    "type": "number"
+                },
+                "pmi": {
+                    "description": "log2 of lift",
+                    "type": "number"
+                },
+                "rated_count": {
+                    "description": "Sessions with both flavors and a rating",
+                    "type": "integer"
+                },
+                "support": {
+                    "description": "Count divided by sessions with flavors",
+                    "type": "number"
+                }
+            }
+        },
+        "models.FlavorPairStats": {
+            "type": "object",
+            "properties": {
+                "pairs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FlavorPair"
+                    }
+                },
+                "session_count": {
+                    "description": "Sessions with at least one flavor",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.FlavorPartner": {
+            "type": "object",
+            "properties": {
+                "average_rating": {
+                    "type": "number"
+                },
+                "confidence": {
+                    "description": "Share of the given flavor's sessions that contain this partner",
+                    "type": "number"
+                },
+                "count": {
+                    "description": "Sessions containing both flavors",
+                    "type": "integer"
+                },
+                "flavor_name": {
+                    "type": "string"
+                },
+                "lift": {
+                    "type": "number"
+                },
+                "pmi": {
+                    "type": "number"
+                },
+                "rated_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.FlavorPartners": {
+            "type": "object",
+            "properties": {
+                "flavor_name": {
+                    "type": "string"
+                },
+                "partners": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FlavorPartner"
+                    }
+                },
+                "session_count": {
+                    "description": "Sessions containing the flavor",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.FlavorStats": {
+            "type": "object",
+            "properties": {
+                "all_flavors": {
+                    "description": "All flavors",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FlavorCount"
+                    }
+                },
+                "main_flavors": {
+                    "description": "First flavors only",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FlavorCount"
+                    }
+                }
+            }
+        },
+        "models.HabitBreak": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "description": "Days between both session days",
+                    "type": "integer"
+                },
+                "from": {
+                    "description": "Last session day before the break (YYYY-MM-DD)",
+                    "type": "string"
+                },
+                "to": {
+                    "description": "First session day after the break (YYYY-MM-DD)",
+                    "type": "string"
+                }
+            }
+        },
+        "models.HabitStats": {
+            "type": "object",
+            "properties": {
+                "active_days": {
+                    "description": "Days with at least one session",
+                    "type": "integer"
+                },
+                "average_days_between": {
+                    "description": "Between consecutive active days, nil with fewer than two",
+                    "type": "number"
+                },
+                "current_daily_streak": {
+                    "description": "Consecutive days ending today or yesterday",
+                    "type": "integer"
+                },
+                "current_weekly_streak": {
+                    "description": "Consecutive weeks ending this or last week",
+                    "type": "integer"
+                },
+                "first_session_at": {
+                    "type": "string"
+                },
+                "first_session_date": {
+                    "description": "YYYY-MM-DD in the timezone",
+                    "type": "string"
+                },
+                "last_session_at": {
+                    "type": "string"
+                },
+                "last_session_date": {
+                    "type": "string"
+                },
+                "longest_break": {
+                    "$ref": "#/definitions/models.HabitBreak"
+                },
+                "longest_daily_streak": {
+                    "type": "integer"
+                },
+                "longest_weekly_streak": {
+                    "type": "integer"
+                },
+                "median_days_between": {
+                    "type": "number"
+                },
+                "session_count": {
+                    "type": "integer"
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "trend_slope": {
+                    "description": "Least squares change in sessions per week per week over WeeklyTrend",
+                    "type": "number"
+                },
+                "weekly_trend": {
+                    "description": "Most recent weeks up to the current week, oldest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.HabitWeek"
+                    }
+                }
+            }
+        },
+        "models.HabitWeek": {
+            "type": "object",
+            "properties": {
+                "session_count": {
+                    "type": "integer"
+                },
+                "week": {
+                    "description": "YYYY-MM-DD of the Monday starting the week",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Heatmap": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "description": "Row labels, starting on Monday",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "flavor": {
+                    "description": "Flavor filter, nil when not filtered",
+                    "type": "string"
+                },
+                "matrix": {
+                    "description": "Matrix[day][hour], rows follow Days",
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        }
+                    }
+                },
+                "session_count": {
+                    "type": "integer"
+                },
+                "store": {
+                    "description": "Store filter, nil when not filtered",
+                    "type": "string"
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "top_day": {
+                    "description": "nil when there are no sessions",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.HeatmapDay"
+                        }
+                    ]
+                },
+                "top_hour": {
+                    "description": "nil when there are no sessions",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.HeatmapHour"
+                        }
+                    ]
+                },
+                "top_slots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.HeatmapSlot"
+                    }
+                }
+            }
+        },
+        "models.HeatmapDay": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "day": {
+                    "description": "Monday..Sunday",
+                    "type": "string"
+                }
+            }
+        },
+        "models.HeatmapHour": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "hour": {
+                    "description": "0-23",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HeatmapSlot": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "day": {
+                    "type": "string"
+                },
+                "hour": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ImportCSVResult": {
+            "type": "object",
+            "properties": {
+                "columns": {
+                    "description": "Header of the file",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created": {
+                    "type": "integer"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "duplicate_rows": {
+                    "type": "integer"
+                },
+                "invalid_rows": {
+                    "type": "integer"
+                },
+                "mapping": {
+                    "description": "Session field to column",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ImportRow"
+                    }
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "total_rows": {
+                    "type": "integer"
+                },
+                "valid_rows": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ImportExchangeRatesRequest": {
+            "type": "object",
+            "properties": {
+                "rates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ExchangeRateImport"
+                    }
+                },
+                "source": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ImportRow": {
+            "type": "object",
+            "properties": {
+                "duplicate_of": {
+                    "description": "Existing session on the same date at the same store",
+                    "type": "string"
+                },
+                "duplicate_of_line": {
+                    "description": "Earlier row on the same date at the same store",
+                    "type": "integer"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "line": {
+                    "description": "Line number in the file, the header is line 1",
+                    "type": "integer"
+                },
+                "session": {
+                    "$ref": "#/definitions/models.ImportedSession"
+                }
+            }
+        },
+        "models.ImportedSession": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "creator": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "duration_minutes": {
+                    "type": "integer"
+                },
+                "flavors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CreateFlavorRequest"
+                    }
+                },
+                "mix_name": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "order_details": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "integer"
+                },
+                "session_date": {
+                    "type": "string"
+                },
+                "store_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.InventoryItem": {
+            "type": "object",
+            "properties": {
+                "brand": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "flavor_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "opened_date": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "purchase_date": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "weight_grams": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.InventoryItemStatus": {
+            "type": "object",
+            "properties": {
+                "brand": {
+                    "type": "string"
+                },
+                "consumed_grams": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "daily_usage_grams": {
+                    "description": "nil when the item has not been used",
+                    "type": "number"
+                },
+                "flavor_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_used_date": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "low_stock": {
+                    "type": "boolean"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "opened_date": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "projected_run_out_date": {
+                    "description": "YYYY-MM-DD, nil when it cannot be projected",
+                    "type": "string"
+                },
+                "purchase_date": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "remaining_grams": {
+                    "type": "number"
+                },
+                "session_count": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "weight_grams": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.InventoryReport": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.InventoryItemStatus"
+                    }
+                },
+                "low_stock": {
+                    "description": "Items at or below the low stock threshold",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.InventoryItemStatus"
+                    }
+                },
+                "low_stock_grams": {
+                    "type": "number"
+                },
+                "total_remaining_grams": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.OrderCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "order_details": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.OrderStats": {
+            "type": "object",
+            "properties": {
+                "orders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OrderCount"
+                    }
+                }
+            }
+        },
+        "models.RecipeFlavor": {
+            "type": "object",
+            "properties": {
+                "brand": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "flavor_name": {
+                    "type": "string"
+                },
+                "flavor_order": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ratio": {
+                    "type": "number"
+                },
+                "recipe_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RecipeStats": {
+            "type": "object",
+            "properties": {
+                "recipes": {
+                    "description": "Sorted by session count",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RecipeUsage"
+                    }
+                }
+            }
+        },
+        "models.RecipeUsage": {
+            "type": "object",
+            "properties": {
+                "average_rating": {
+                    "description": "nil when no session using it is rated",
+                    "type": "number"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rated_count": {
+                    "type": "integer"
+                },
+                "recipe_id": {
+                    "type": "string"
+                },
+                "session_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RecipeWithFlavors": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "flavors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RecipeFlavor"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Recommendation": {
+            "type": "object",
+            "properties": {
+                "explanation": {
+                    "type": "string"
+                },
+                "flavors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "novelty": {
+                    "description": "0-1, how unfamiliar the suggestion is",
+                    "type": "number"
+                },
+                "predicted_rating": {
+                    "description": "Expected rating from similar sessions, 1-5",
+                    "type": "number"
+                },
+                "score": {
+                    "description": "0-1, higher is better",
+                    "type": "number"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RecommendationList": {
+            "type": "object",
+            "properties": {
+                "exploration": {
+                    "description": "Weight of novelty against predicted rating",
+                    "type": "number"
+                },
+                "recommendations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Recommendation"
+                    }
+                },
+                "session_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SessionFlavor": {
+            "type": "object",
+            "properties": {
+                "brand": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "flavor_name": {
+                    "type": "string"
+                },
+                "flavor_order": {
+                    "type": "integer"
+                },
+                "grams": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "inventory_id": {
+                    "type": "string"
+                },
+                "session_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SessionSpend": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "In the report currency",
+                    "type": "number"
+                },
+                "creator": {
+                    "type": "string"
+                },
+                "mix_name": {
+                    "type": "string"
+                },
+                "original_amount": {
+                    "type": "integer"
+                },
+                "original_currency": {
+                    "type": "string"
+                },
+                "session_date": {
+                    "type": "string"
+                },
+                "session_id": {
+                    "type": "string"
+                },
+                "store_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SessionWithFlavors": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "creator": {
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "ISO 4217 code of the amount",
+                    "type": "string"
+                },
+                "duration_minutes": {
+                    "type": "integer"
+                },
+                "equipment_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "flavors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SessionFlavor"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "mix_name": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "order_details": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "integer"
+                },
+                "recipe_id": {
+                    "type": "string"
+                },
+                "session_date": {
+                    "type": "string"
+                },
+                "store_name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SimilarSession": {
+            "type": "object",
+            "properties": {
+                "flavor_score": {
+                    "description": "Flavor overlap weighted by flavor order, 0-1",
+                    "type": "number"
+                },
+                "same_creator": {
+                    "type": "boolean"
+                },
+                "same_mix_name": {
+                    "type": "boolean"
+                },
+                "same_store": {
+                    "type": "boolean"
+                },
+                "score": {
+                    "description": "0-1, weighted sum of the components below",
+                    "type": "number"
+                },
+                "session": {
+                    "$ref": "#/definitions/models.SessionWithFlavors"
+                },
+                "shared_flavors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.SpendingBucket": {
+            "type": "object",
+            "properties": {
+                "average": {
+                    "description": "Average spend per session",
+                    "type": "number"
+                },
+                "period": {
+                    "description": "YYYY-MM-DD (day, week start), YYYY-MM or YYYY",
+                    "type": "string"
+                },
+                "session_count": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.SpendingGroup": {
+            "type": "object",
+            "properties": {
+                "average": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "session_count": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.SpendingPeriodStats": {
+            "type": "object",
+            "properties": {
+                "average_per_period": {
+                    "description": "Total divided by every period from the first to the last session",
+                    "type": "number"
+                },
+                "buckets": {
+                    "description": "Only periods with spending, sorted by period",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SpendingBucket"
+                    }
+                }
+            }
+        },
+        "models.SpendingStats": {
+            "type": "object",
+            "properties": {
+                "average_per_session": {
+                    "type": "number"
+                },
+                "by_creator": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SpendingGroup"
+                    }
+                },
+                "by_store": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SpendingGroup"
+                    }
+                },
+                "cost_per_hour": {
+                    "description": "nil when no session with an amount has a duration",
+                    "type": "number"
+                },
+                "currency": {
+                    "description": "Currency all amounts are reported in",
+                    "type": "string"
+                },
+                "daily": {
+                    "$ref": "#/definitions/models.SpendingPeriodStats"
+                },
+                "least_expensive": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SessionSpend"
+                    }
+                },
+                "missing_currencies": {
+                    "description": "Currencies without a rate to the report currency",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "monthly": {
+                    "$ref": "#/definitions/models.SpendingPeriodStats"
+                },
+                "most_expensive": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SessionSpend"
+                    }
+                },
+                "session_count": {
+                    "description": "Sessions with an amount",
+                    "type": "integer"
+                },
+                "sessions_with_duration": {
+                    "type": "integer"
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "number"
+                },
+                "unconverted_session_count": {
+                    "description": "Sessions skipped because no exchange rate was available",
+                    "type": "integer"
+                },
+                "weekly": {
+                    "description": "Weeks start on Monday",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SpendingPeriodStats"
+                        }
+                    ]
+                },
+                "yearly": {
+                    "$ref": "#/definitions/models.SpendingPeriodStats"
+                }
+            }
+        },
+        "models.StoreCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "store_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.StoreStats": {
+            "type": "object",
+            "properties": {
+                "stores": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StoreCount"
+                    }
+                }
+            }
+        },
+        "models.TimeSeries": {
+            "type": "object",
+            "properties": {
+                "bucket": {
+                    "type": "string"
+                },
+                "buckets": {
+                    "description": "Every bucket in the range, including empty ones",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TimeSeriesBucket"
+                    }
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "distinct_flavors": {
+                    "type": "integer"
+                },
+                "from": {
+                    "description": "YYYY-MM-DD, inclusive",
+                    "type": "string"
+                },
+                "session_count": {
+                    "type": "integer"
+                },
+                "spend": {
+                    "type": "number"
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "to": {
+                    "description": "YYYY-MM-DD, inclusive",
+                    "type": "string"
+                },
+                "unconverted_session_count": {
+                    "description": "Sessions whose amount could not be converted",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TimeSeriesBucket": {
+            "type": "object",
+            "properties": {
+                "distinct_flavors": {
+                    "type": "integer"
+                },
+                "period": {
+                    "description": "YYYY-MM-DD (day, week start) or YYYY-MM",
+                    "type": "string"
                 },
-                "flavor_name": {
+                "session_count": {
+                    "type": "integer"
+                },
+                "spend": {
+                    "type": "number"
+                },
+                "start": {
                     "type": "string"
                 }
             }
         },
-        "models.CreateSessionRequest": {
+        "models.UpdateBudgetRequest": {
             "type": "object",
-            "required": [
-                "session_date"
-            ],
             "properties": {
                 "amount": {
-                    "type": "integer"
+                    "type": "number"
                 },
-                "creator": {
+                "currency": {
                     "type": "string"
                 },
-                "flavors": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.CreateFlavorRequest"
-                    }
+                "name": {
+                    "type": "string"
                 },
-                "mix_name": {
+                "period": {
                     "type": "string"
                 },
-                "notes": {
+                "store_name": {
+                    "description": "An empty string applies the budget to all stores",
                     "type": "string"
                 },
-                "order_details": {
+                "timezone": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.UpdateEquipmentRequest": {
+            "type": "object",
+            "properties": {
+                "brand": {
                     "type": "string"
                 },
-                "session_date": {
+                "category": {
                     "type": "string"
                 },
-                "store_name": {
+                "name": {
+                    "type": "string"
+                },
+                "notes": {
                     "type": "string"
                 }
             }
         },
-        "models.CreatorCount": {
+        "models.UpdateInventoryRequest": {
             "type": "object",
             "properties": {
-                "count": {
+                "brand": {
+                    "type": "string"
+                },
+                "flavor_name": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "opened_date": {
+                    "description": "YYYY-MM-DD, empty string clears",
+                    "type": "string"
+                },
+                "price": {
                     "type": "integer"
                 },
-                "creator": {
+                "purchase_date": {
+                    "description": "YYYY-MM-DD, empty string clears",
                     "type": "string"
+                },
+                "weight_grams": {
+                    "type": "number"
                 }
             }
         },
-        "models.CreatorStats": {
+        "models.UpdateRecipeRequest": {
             "type": "object",
             "properties": {
-                "creators": {
+                "flavors": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.CreatorCount"
+                        "$ref": "#/definitions/models.CreateRecipeFlavorRequest"
                     }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
                 }
             }
         },
-        "models.FlavorCount": {
+        "models.UpdateSessionRequest": {
             "type": "object",
             "properties": {
-                "count": {
+                "amount": {
                     "type": "integer"
                 },
-                "flavor_name": {
+                "creator": {
                     "type": "string"
-                }
-            }
-        },
-        "models.FlavorStats": {
-            "type": "object",
-            "properties": {
-                "all_flavors": {
-                    "description": "All flavors",
+                },
+                "currency": {
+                    "description": "ISO 4217 code, defaults to the user's default currency",
+                    "type": "string"
+                },
+                "duration_minutes": {
+                    "type": "integer"
+                },
+                "equipment_ids": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.FlavorCount"
+                        "type": "string"
                     }
                 },
-                "main_flavors": {
-                    "description": "First flavors only",
+                "flavors": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.FlavorCount"
+                        "$ref": "#/definitions/models.CreateFlavorRequest"
                     }
+                },
+                "mix_name": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "order_details": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "integer"
+                },
+                "recipe_id": {
+                    "type": "string"
+                },
+                "session_date": {
+                    "type": "string"
+                },
+                "store_name": {
+                    "type": "string"
                 }
             }
         },
-        "models.OrderCount": {
+        "models.UpdateUserRequest": {
             "type": "object",
             "properties": {
-                "count": {
-                    "type": "integer"
-                },
-                "order_details": {
+                "default_currency": {
                     "type": "string"
                 }
             }
         },
-        "models.OrderStats": {
+        "models.UpdateWebhookRequest": {
             "type": "object",
             "properties": {
-                "orders": {
+                "description": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "description": "Enabling resets the failure count",
+                    "type": "boolean"
+                },
+                "events": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.OrderCount"
+                        "type": "string"
                     }
+                },
+                "url": {
+                    "type": "string"
                 }
             }
         },
-        "models.SessionFlavor": {
+        "models.User": {
             "type": "object",
             "properties": {
-                "brand": {
-                    "type": "string"
-                },
                 "created_at": {
                     "type": "string"
                 },
-                "flavor_name": {
+                "default_currency": {
+                    "description": "ISO 4217 code used when a session has no currency",
                     "type": "string"
                 },
-                "flavor_order": {
-                    "type": "integer"
-                },
                 "id": {
                     "type": "string"
                 },
-                "session_id": {
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "models.SessionWithFlavors": {
+        "models.Webhook": {
             "type": "object",
             "properties": {
-                "amount": {
+                "consecutive_failures": {
+                    "description": "Failed attempts since the last success",
                     "type": "integer"
                 },
                 "created_at": {
                     "type": "string"
                 },
-                "created_by": {
+                "description": {
                     "type": "string"
                 },
-                "creator": {
+                "disabled_at": {
                     "type": "string"
                 },
-                "flavors": {
+                "disabled_reason": {
+                    "description": "Set when the webhook was disabled automatically",
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "events": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.SessionFlavor"
+                        "type": "string"
                     }
                 },
                 "id": {
                     "type": "string"
                 },
-                "mix_name": {
+                "updated_at": {
                     "type": "string"
                 },
-                "notes": {
+                "url": {
                     "type": "string"
                 },
-                "order_details": {
+                "user_id": {
                     "type": "string"
+                }
+            }
+        },
+        "models.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
                 },
-                "session_date": {
+                "created_at": {
                     "type": "string"
                 },
-                "store_name": {
+                "delivered_at": {
                     "type": "string"
                 },
-                "updated_at": {
+                "error": {
+                    "description": "Why the last attempt failed",
                     "type": "string"
                 },
-                "user_id": {
+                "event_id": {
+                    "description": "Same for every delivery of an event, use it to deduplicate",
                     "type": "string"
-                }
-            }
-        },
-        "models.StoreCount": {
-            "type": "object",
-            "properties": {
-                "count": {
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "response_body": {
+                    "description": "Truncated response of the last attempt",
+                    "type": "string"
+                },
+                "response_status": {
                     "type": "integer"
                 },
-                "store_name": {
+                "status": {
+                    "description": "pending, succeeded or failed",
+                    "type": "string"
+                },
+                "webhook_id": {
                     "type": "string"
                 }
             }
         },
-        "models.StoreStats": {
+        "models.WebhookWithSecret": {
             "type": "object",
             "properties": {
-                "stores": {
+                "consecutive_failures": {
+                    "description": "Failed attempts since the last success",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "disabled_at": {
+                    "type": "string"
+                },
+                "disabled_reason": {
+                    "description": "Set when the webhook was disabled automatically",
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "events": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.StoreCount"
+                        "type": "string"
                     }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
                 }
             }
         },
-        "models.UpdateSessionRequest": {
+        "models.YearReport": {
             "type": "object",
             "properties": {
-                "amount": {
+                "active_days": {
                     "type": "integer"
                 },
-                "creator": {
+                "average_rating": {
+                    "description": "nil when no session of the year is rated",
+                    "type": "number"
+                },
+                "busiest_month": {
+                    "description": "nil when there are no sessions",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.YearReportMonth"
+                        }
+                    ]
+                },
+                "currency": {
                     "type": "string"
                 },
-                "flavors": {
+                "longest_streak": {
+                    "type": "integer"
+                },
+                "months": {
+                    "description": "All twelve months",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.CreateFlavorRequest"
+                        "$ref": "#/definitions/models.YearReportMonth"
                     }
                 },
-                "mix_name": {
-                    "type": "string"
+                "new_flavors": {
+                    "description": "Flavors first used this year",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "notes": {
-                    "type": "string"
+                "new_stores": {
+                    "description": "Stores first visited this year",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "order_details": {
-                    "type": "string"
+                "session_count": {
+                    "type": "integer"
                 },
-                "session_date": {
-                    "type": "string"
+                "spend": {
+                    "type": "number"
                 },
-                "store_name": {
+                "timezone": {
                     "type": "string"
+                },
+                "top_creators": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CreatorCount"
+                    }
+                },
+                "top_flavors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FlavorCount"
+                    }
+                },
+                "top_stores": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StoreCount"
+                    }
+                },
+                "unconverted_session_count": {
+                    "description": "Sessions whose amount could not be converted",
+                    "type": "integer"
+                },
+                "year": {
+                    "type": "integer"
                 }
             }
         },
-        "models.User": {
+        "models.YearReportMonth": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "month": {
+                    "description": "YYYY-MM",
                     "type": "string"
                 },
-                "id": {
-                    "type": "string"
-                },
-                "updated_at": {
-                    "type": "string"
+                "session_count": {
+                    "type": "integer"
                 },
-                "user_id": {
-                    "type": "string"
+                "spend": {
+                    "type": "number"
                 }
             }
         }
@@ -0,0 +1,181 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/toof-jp/shisha-log/backend/internal/archive"
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+	"github.com/toof-jp/shisha-log/backend/internal/repository"
+	"github.com/toof-jp/shisha-log/backend/internal/service"
+)
+
+type AccountHandler struct {
+	sessionRepo    *repository.SessionRepository
+	userRepo       *repository.UserRepository
+	equipmentRepo  *repository.EquipmentRepository
+	inventoryRepo  *repository.InventoryRepository
+	recipeRepo     *repository.RecipeRepository
+	budgetRepo     *repository.BudgetRepository
+	accountService *service.AccountService
+}
+
+// maxAccountArchiveSize limits the size of an uploaded account archive
+const maxAccountArchiveSize = 100 << 20
+
+func NewAccountHandler(
+	sessionRepo *repository.SessionRepository,
+	userRepo *repository.UserRepository,
+	equipmentRepo *repository.EquipmentRepository,
+	inventoryRepo *repository.InventoryRepository,
+	recipeRepo *repository.RecipeRepository,
+	budgetRepo *repository.BudgetRepository,
+	accountService *service.AccountService,
+) *AccountHandler {
+	return &AccountHandler{
+		sessionRepo:    sessionRepo,
+		userRepo:       userRepo,
+		equipmentRepo:  equipmentRepo,
+		inventoryRepo:  inventoryRepo,
+		recipeRepo:     recipeRepo,
+		budgetRepo:     budgetRepo,
+		accountService: accountService,
+	}
+}
+
+// ExportAccount godoc
+// @Summary Export all account data
+// @Description Stream a versioned JSON archive of everything tied to the account: the user profile (without the password hash), equipment, tobacco inventory, recipes, budgets and all sessions with their flavors. Sessions are streamed newest first. The format is described in docs/ACCOUNT_ARCHIVE.md.
+// @Tags users
+// @Produce json
+// @Security Bearer
+// @Success 200 {file} file "Account archive"
+// @Failure 500 {object} object{error=string} "Failed to export account"
+// @Router /users/me/export [get]
+func (h *AccountHandler) ExportAccount(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	ctx := c.Request().Context()
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid user ID"})
+	}
+
+	header, err := h.archiveHeader(ctx, userUUID, userID)
+	if err != nil {
+		log.Printf("ExportAccount error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to export account"})
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+	res.Header().Set(echo.HeaderContentDisposition, `attachment; filename="shisha-log-`+header.ExportedAt.Format("20060102")+`.json"`)
+	res.WriteHeader(http.StatusOK)
+
+	writer := archive.NewWriter(res)
+	if err := writer.Begin(*header); err != nil {
+		return err
+	}
+
+	err = h.sessionRepo.ForEachSession(ctx, userID, func(session models.SessionWithFlavors) error {
+		if err := writer.WriteSession(session); err != nil {
+			return err
+		}
+		res.Flush()
+		return nil
+	})
+	if err != nil {
+		// The status is already sent, so leave the archive truncated; readers
+		// detect it by the missing session count
+		log.Printf("ExportAccount error for user %s: %v", userID, err)
+		return nil
+	}
+
+	return writer.Close()
+}
+
+// ImportAccount godoc
+// @Summary Import account data
+// @Description Import an account archive created by GET /users/me/export, e.g. from another environment. In merge mode the archive is added next to the existing data; in replace mode all existing data is deleted first. IDs are remapped to stable per-account IDs, so importing the same archive again skips what was already imported. Entities that match existing data (same equipment, recipe or budget name, same inventory purchase, session at the same time and store) are reported as conflicts and the existing entity is kept. The archive is validated before anything is written and the import runs in one transaction, so a failed import changes nothing.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param mode query string false "merge or replace" default(merge)
+// @Param archive body object true "Account archive"
+// @Success 200 {object} models.AccountImportResult
+// @Failure 400 {object} object{error=string} "Invalid archive, archive entity or mode"
+// @Failure 500 {object} object{error=string} "Failed to import account"
+// @Router /users/me/import [post]
+func (h *AccountHandler) ImportAccount(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	mode := c.QueryParam("mode")
+	if mode == "" {
+		mode = models.AccountImportMerge
+	}
+	if !models.IsValidAccountImportMode(mode) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid mode parameter. Use merge or replace"})
+	}
+
+	body := http.MaxBytesReader(c.Response(), c.Request().Body, maxAccountArchiveSize)
+
+	var sessions []models.SessionWithFlavors
+	header, err := archive.Read(body, func(session models.SessionWithFlavors) error {
+		sessions = append(sessions, session)
+		return nil
+	})
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid archive: " + err.Error()})
+	}
+
+	result, err := h.accountService.Import(c.Request().Context(), userID, header, sessions, mode)
+	var archiveErr *service.ArchiveError
+	if errors.As(err, &archiveErr) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid archive: " + err.Error()})
+	}
+	if err != nil {
+		log.Printf("ImportAccount error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to import account"})
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// archiveHeader loads everything in the archive except the sessions
+func (h *AccountHandler) archiveHeader(ctx context.Context, userUUID uuid.UUID, userID string) (*archive.Header, error) {
+	user, err := h.userRepo.GetByID(userUUID)
+	if err != nil {
+		return nil, err
+	}
+	equipment, err := h.equipmentRepo.GetByUserID(ctx, userID, "")
+	if err != nil {
+		return nil, err
+	}
+	inventory, err := h.inventoryRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	recipes, err := h.recipeRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	budgets, err := h.budgetRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &archive.Header{
+		ExportedAt: time.Now().UTC(),
+		User:       *user,
+		Equipment:  equipment,
+		Inventory:  inventory,
+		Recipes:    recipes,
+		Budgets:    budgets,
+	}, nil
+}
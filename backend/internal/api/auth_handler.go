@@ -7,10 +7,10 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"github.com/toof-jp/shisha-log/backend/internal/currency"
+	"github.com/toof-jp/shisha-log/backend/internal/models"
 	"github.com/toof-jp/shisha-log/backend/internal/repository"
 	"github.com/toof-jp/shisha-log/backend/internal/service"
-
-	_ "github.com/toof-jp/shisha-log/backend/internal/models"
 )
 
 type AuthHandler struct {
@@ -372,6 +372,52 @@ func (h *AuthHandler) GetCurrentUser(c echo.Context) error {
 	return c.JSON(http.StatusOK, user)
 }
 
+// UpdateCurrentUser godoc
+// @Summary Update current user
+// @Description Update the current authenticated user's settings
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param user body models.UpdateUserRequest true "User settings"
+// @Success 200 {object} models.User "Updated user"
+// @Failure 400 {object} object{error=string} "Invalid request body"
+// @Failure 404 {object} object{error=string} "User not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /users/me [put]
+func (h *AuthHandler) UpdateCurrentUser(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid user ID"})
+	}
+
+	var req models.UpdateUserRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	if req.DefaultCurrency != nil {
+		code := currency.Normalize(*req.DefaultCurrency)
+		if !currency.IsValidCode(code) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid currency code"})
+		}
+		if err := h.userRepo.UpdateDefaultCurrency(userUUID, code); err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update user"})
+		}
+	}
+
+	user, err := h.userRepo.GetByID(userUUID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "User not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve user"})
+	}
+
+	return c.JSON(http.StatusOK, user)
+}
+
 // Refresh godoc
 // @Summary Refresh access token
 // @Description Get a new access token using refresh token from cookie
@@ -0,0 +1,291 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/toof-jp/shisha-log/backend/internal/currency"
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+	"github.com/toof-jp/shisha-log/backend/internal/repository"
+	"github.com/toof-jp/shisha-log/backend/internal/service"
+)
+
+type BudgetHandler struct {
+	repo          *repository.BudgetRepository
+	userRepo      *repository.UserRepository
+	budgetService *service.BudgetService
+}
+
+func NewBudgetHandler(
+	repo *repository.BudgetRepository,
+	userRepo *repository.UserRepository,
+	budgetService *service.BudgetService,
+) *BudgetHandler {
+	return &BudgetHandler{
+		repo:          repo,
+		userRepo:      userRepo,
+		budgetService: budgetService,
+	}
+}
+
+// CreateBudget godoc
+// @Summary Create a budget
+// @Description Create a weekly or monthly spending cap, optionally limited to one store
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param budget body models.CreateBudgetRequest true "Budget data"
+// @Success 201 {object} models.Budget "Created budget"
+// @Failure 400 {object} object{error=string} "Invalid request body"
+// @Failure 500 {object} object{error=string} "Failed to create budget"
+// @Router /budgets [post]
+func (h *BudgetHandler) CreateBudget(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	var req models.CreateBudgetRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	if strings.TrimSpace(req.Name) == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Name is required"})
+	}
+
+	budget := &models.Budget{
+		UserID:    userID,
+		Name:      req.Name,
+		Period:    req.Period,
+		Amount:    req.Amount,
+		StoreName: nilIfEmpty(req.StoreName),
+		Timezone:  "UTC",
+	}
+	if req.Timezone != nil && *req.Timezone != "" {
+		budget.Timezone = *req.Timezone
+	}
+
+	if req.Currency != nil && *req.Currency != "" {
+		budget.Currency = currency.Normalize(*req.Currency)
+	} else {
+		userUUID, err := uuid.Parse(userID)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid user ID"})
+		}
+		user, err := h.userRepo.GetByID(userUUID)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create budget"})
+		}
+		budget.Currency = user.DefaultCurrency
+	}
+
+	if err := validateBudget(budget.Period, budget.Amount, budget.Currency, budget.Timezone); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	created, err := h.repo.Create(c.Request().Context(), budget)
+	if err != nil {
+		log.Printf("CreateBudget error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create budget"})
+	}
+
+	return c.JSON(http.StatusCreated, created)
+}
+
+// GetUserBudgets godoc
+// @Summary Get user's budgets
+// @Description Get all budgets of the authenticated user
+// @Tags budgets
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} object{budgets=[]models.Budget} "Budget list"
+// @Failure 500 {object} object{error=string} "Failed to get budgets"
+// @Router /budgets [get]
+func (h *BudgetHandler) GetUserBudgets(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	budgets, err := h.repo.GetByUserID(c.Request().Context(), userID)
+	if err != nil {
+		log.Printf("GetUserBudgets error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get budgets"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"budgets": budgets,
+	})
+}
+
+// GetBudgetStatus godoc
+// @Summary Get budget status
+// @Description Get the spend of the current period against each budget and the projected spend at the end of the period
+// @Tags budgets
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} object{budgets=[]models.BudgetStatus} "Budget statuses"
+// @Failure 500 {object} object{error=string} "Failed to get budget status"
+// @Router /budgets/status [get]
+func (h *BudgetHandler) GetBudgetStatus(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	statuses, err := h.budgetService.GetStatuses(c.Request().Context(), userID)
+	if err != nil {
+		log.Printf("GetBudgetStatus error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get budget status"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"budgets": statuses,
+	})
+}
+
+// GetBudget godoc
+// @Summary Get a budget by ID
+// @Description Get a specific budget by its ID
+// @Tags budgets
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Budget ID"
+// @Success 200 {object} models.Budget "Budget details"
+// @Failure 403 {object} object{error=string} "Access denied"
+// @Failure 404 {object} object{error=string} "Budget not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /budgets/{id} [get]
+func (h *BudgetHandler) GetBudget(c echo.Context) error {
+	budget, err := h.getOwnedBudget(c)
+	if err != nil || budget == nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, budget)
+}
+
+// UpdateBudget godoc
+// @Summary Update a budget
+// @Description Update an existing budget. Changing a budget allows a new breach event in the current period.
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Budget ID"
+// @Param budget body models.UpdateBudgetRequest true "Updated budget data"
+// @Success 200 {object} models.Budget "Updated budget"
+// @Failure 400 {object} object{error=string} "Invalid request body"
+// @Failure 403 {object} object{error=string} "Access denied"
+// @Failure 404 {object} object{error=string} "Budget not found"
+// @Failure 500 {object} object{error=string} "Failed to update budget"
+// @Router /budgets/{id} [put]
+func (h *BudgetHandler) UpdateBudget(c echo.Context) error {
+	budget, err := h.getOwnedBudget(c)
+	if err != nil || budget == nil {
+		return err
+	}
+
+	var req models.UpdateBudgetRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	if req.Name != nil && strings.TrimSpace(*req.Name) == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Name cannot be empty"})
+	}
+	if req.Currency != nil {
+		code := currency.Normalize(*req.Currency)
+		req.Currency = &code
+	}
+
+	// Validate the budget as it will be after the update
+	period, amount, code, timezone := budget.Period, budget.Amount, budget.Currency, budget.Timezone
+	if req.Period != nil {
+		period = *req.Period
+	}
+	if req.Amount != nil {
+		amount = *req.Amount
+	}
+	if req.Currency != nil {
+		code = *req.Currency
+	}
+	if req.Timezone != nil {
+		timezone = *req.Timezone
+	}
+	if err := validateBudget(period, amount, code, timezone); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if err := h.repo.Update(c.Request().Context(), budget.ID, &req); err != nil {
+		c.Logger().Errorf("Failed to update budget %s: %v", budget.ID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update budget"})
+	}
+
+	updated, err := h.repo.GetByID(c.Request().Context(), budget.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get updated budget"})
+	}
+
+	return c.JSON(http.StatusOK, updated)
+}
+
+// DeleteBudget godoc
+// @Summary Delete a budget
+// @Description Delete a budget
+// @Tags budgets
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Budget ID"
+// @Success 200 {object} object{message=string} "Budget deleted successfully"
+// @Failure 403 {object} object{error=string} "Access denied"
+// @Failure 404 {object} object{error=string} "Budget not found"
+// @Failure 500 {object} object{error=string} "Failed to delete budget"
+// @Router /budgets/{id} [delete]
+func (h *BudgetHandler) DeleteBudget(c echo.Context) error {
+	budget, err := h.getOwnedBudget(c)
+	if err != nil || budget == nil {
+		return err
+	}
+
+	if err := h.repo.Delete(c.Request().Context(), budget.ID); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to delete budget"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "Budget deleted successfully"})
+}
+
+// getOwnedBudget loads the budget from the path and checks that it belongs to
+// the authenticated user. When it returns a nil budget the response has already been written.
+func (h *BudgetHandler) getOwnedBudget(c echo.Context) (*models.Budget, error) {
+	budgetID := c.Param("id")
+	userID := c.Get("user_id").(string)
+
+	budget, err := h.repo.GetByID(c.Request().Context(), budgetID)
+	if err != nil {
+		if err.Error() == "budget not found" {
+			return nil, c.JSON(http.StatusNotFound, map[string]string{"error": "Budget not found"})
+		}
+		return nil, c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get budget"})
+	}
+
+	if budget.UserID != userID {
+		return nil, c.JSON(http.StatusForbidden, map[string]string{"error": "Access denied"})
+	}
+
+	return budget, nil
+}
+
+func validateBudget(period string, amount float64, code string, timezone string) error {
+	if !models.IsValidBudgetPeriod(period) {
+		return fmt.Errorf("period must be weekly or monthly")
+	}
+	if amount <= 0 {
+		return fmt.Errorf("amount must be greater than 0")
+	}
+	if !currency.IsValidCode(code) {
+		return fmt.Errorf("invalid currency code")
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return fmt.Errorf("invalid timezone")
+	}
+	return nil
+}
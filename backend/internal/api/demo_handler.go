@@ -0,0 +1,60 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/toof-jp/shisha-log/backend/internal/demo"
+	"github.com/toof-jp/shisha-log/backend/internal/service"
+)
+
+type DemoHandler struct {
+	account       *demo.Account
+	jwtService    *service.JWTService
+	tokenDuration time.Duration
+}
+
+func NewDemoHandler(account *demo.Account, jwtService *service.JWTService, tokenDuration time.Duration) *DemoHandler {
+	return &DemoHandler{
+		account:       account,
+		jwtService:    jwtService,
+		tokenDuration: tokenDuration,
+	}
+}
+
+// CreateDemoSession godoc
+// @Summary Start a demo session
+// @Description Issue a short-lived token for the shared read-only demo account. Its data is regenerated periodically and write requests are rejected with 403.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} object{user=models.User,token=string,expires_at=string} "Demo token issued"
+// @Failure 403 {object} object{error=string} "Demo mode is disabled"
+// @Failure 503 {object} object{error=string} "Demo account is not ready yet"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /auth/demo [post]
+func (h *DemoHandler) CreateDemoSession(c echo.Context) error {
+	if h.account == nil {
+		return c.JSON(http.StatusForbidden, map[string]string{"error": "Demo mode is disabled"})
+	}
+
+	user := h.account.User()
+	if user == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "Demo account is not ready yet"})
+	}
+
+	// No refresh token is issued, the client starts a new demo session instead
+	expiresAt := time.Now().Add(h.tokenDuration)
+	token, err := h.jwtService.GenerateDemoToken(user.ID.String(), h.tokenDuration)
+	if err != nil {
+		log.Printf("CreateDemoSession error: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to generate token"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"user":       user,
+		"token":      token,
+		"expires_at": expiresAt.UTC().Format(time.RFC3339),
+	})
+}
@@ -0,0 +1,237 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+	"github.com/toof-jp/shisha-log/backend/internal/repository"
+)
+
+type EquipmentHandler struct {
+	repo        *repository.EquipmentRepository
+	sessionRepo *repository.SessionRepository
+}
+
+func NewEquipmentHandler(repo *repository.EquipmentRepository, sessionRepo *repository.SessionRepository) *EquipmentHandler {
+	return &EquipmentHandler{
+		repo:        repo,
+		sessionRepo: sessionRepo,
+	}
+}
+
+// CreateEquipment godoc
+// @Summary Create equipment
+// @Description Add a bowl, hookah, heat management device or charcoal type to the user's equipment inventory
+// @Tags equipment
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param equipment body models.CreateEquipmentRequest true "Equipment data"
+// @Success 201 {object} models.Equipment "Created equipment"
+// @Failure 400 {object} object{error=string} "Invalid request body"
+// @Failure 500 {object} object{error=string} "Failed to create equipment"
+// @Router /equipment [post]
+func (h *EquipmentHandler) CreateEquipment(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	var req models.CreateEquipmentRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	if !models.IsValidEquipmentCategory(req.Category) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid category. Use bowl, hookah, heat_management or charcoal"})
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Name is required"})
+	}
+
+	equipment := &models.Equipment{
+		UserID:   userID,
+		Category: req.Category,
+		Name:     req.Name,
+		Brand:    req.Brand,
+		Notes:    req.Notes,
+	}
+
+	created, err := h.repo.Create(c.Request().Context(), equipment)
+	if err != nil {
+		log.Printf("CreateEquipment error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create equipment"})
+	}
+
+	return c.JSON(http.StatusCreated, created)
+}
+
+// GetUserEquipment godoc
+// @Summary Get user's equipment
+// @Description Get the equipment inventory of the authenticated user
+// @Tags equipment
+// @Produce json
+// @Security Bearer
+// @Param category query string false "Filter by category (bowl, hookah, heat_management, charcoal)"
+// @Success 200 {object} object{equipment=[]models.Equipment} "Equipment list"
+// @Failure 400 {object} object{error=string} "Invalid category"
+// @Failure 500 {object} object{error=string} "Failed to get equipment"
+// @Router /equipment [get]
+func (h *EquipmentHandler) GetUserEquipment(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	category := c.QueryParam("category")
+
+	if category != "" && !models.IsValidEquipmentCategory(category) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid category. Use bowl, hookah, heat_management or charcoal"})
+	}
+
+	equipment, err := h.repo.GetByUserID(c.Request().Context(), userID, category)
+	if err != nil {
+		log.Printf("GetUserEquipment error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get equipment"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"equipment": equipment,
+	})
+}
+
+// GetEquipment godoc
+// @Summary Get equipment by ID
+// @Description Get a specific piece of equipment by its ID
+// @Tags equipment
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Equipment ID"
+// @Success 200 {object} models.Equipment "Equipment details"
+// @Failure 403 {object} object{error=string} "Access denied"
+// @Failure 404 {object} object{error=string} "Equipment not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /equipment/{id} [get]
+func (h *EquipmentHandler) GetEquipment(c echo.Context) error {
+	equipment, err := h.getOwnedEquipment(c)
+	if err != nil || equipment == nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, equipment)
+}
+
+// UpdateEquipment godoc
+// @Summary Update equipment
+// @Description Update an existing piece of equipment
+// @Tags equipment
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Equipment ID"
+// @Param equipment body models.UpdateEquipmentRequest true "Updated equipment data"
+// @Success 200 {object} models.Equipment "Updated equipment"
+// @Failure 400 {object} object{error=string} "Invalid request body"
+// @Failure 403 {object} object{error=string} "Access denied"
+// @Failure 404 {object} object{error=string} "Equipment not found"
+// @Failure 500 {object} object{error=string} "Failed to update equipment"
+// @Router /equipment/{id} [put]
+func (h *EquipmentHandler) UpdateEquipment(c echo.Context) error {
+	equipment, err := h.getOwnedEquipment(c)
+	if err != nil || equipment == nil {
+		return err
+	}
+
+	var req models.UpdateEquipmentRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	if req.Category != nil && !models.IsValidEquipmentCategory(*req.Category) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid category. Use bowl, hookah, heat_management or charcoal"})
+	}
+	if req.Name != nil && strings.TrimSpace(*req.Name) == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Name cannot be empty"})
+	}
+
+	if err := h.repo.Update(c.Request().Context(), equipment.ID, &req); err != nil {
+		c.Logger().Errorf("Failed to update equipment %s: %v", equipment.ID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update equipment"})
+	}
+
+	updated, err := h.repo.GetByID(c.Request().Context(), equipment.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get updated equipment"})
+	}
+
+	return c.JSON(http.StatusOK, updated)
+}
+
+// DeleteEquipment godoc
+// @Summary Delete equipment
+// @Description Delete a piece of equipment. Sessions that used it are kept.
+// @Tags equipment
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Equipment ID"
+// @Success 200 {object} object{message=string} "Equipment deleted successfully"
+// @Failure 403 {object} object{error=string} "Access denied"
+// @Failure 404 {object} object{error=string} "Equipment not found"
+// @Failure 500 {object} object{error=string} "Failed to delete equipment"
+// @Router /equipment/{id} [delete]
+func (h *EquipmentHandler) DeleteEquipment(c echo.Context) error {
+	equipment, err := h.getOwnedEquipment(c)
+	if err != nil || equipment == nil {
+		return err
+	}
+
+	if err := h.repo.Delete(c.Request().Context(), equipment.ID); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to delete equipment"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "Equipment deleted successfully"})
+}
+
+// GetEquipmentStats godoc
+// @Summary Get equipment statistics
+// @Description Get usage frequency and average session rating per piece of equipment
+// @Tags statistics
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.EquipmentStats "Equipment statistics"
+// @Failure 500 {object} object{error=string} "Failed to get equipment statistics"
+// @Router /equipment/stats [get]
+func (h *EquipmentHandler) GetEquipmentStats(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	equipment, err := h.repo.GetByUserID(c.Request().Context(), userID, "")
+	if err != nil {
+		log.Printf("GetEquipmentStats error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get equipment statistics"})
+	}
+
+	stats, err := h.sessionRepo.GetEquipmentStats(c.Request().Context(), userID, equipment)
+	if err != nil {
+		log.Printf("GetEquipmentStats error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get equipment statistics"})
+	}
+
+	return c.JSON(http.StatusOK, stats)
+}
+
+// getOwnedEquipment loads the equipment from the path and checks that it belongs to
+// the authenticated user. When it returns nil equipment the response has already been written.
+func (h *EquipmentHandler) getOwnedEquipment(c echo.Context) (*models.Equipment, error) {
+	equipmentID := c.Param("id")
+	userID := c.Get("user_id").(string)
+
+	equipment, err := h.repo.GetByID(c.Request().Context(), equipmentID)
+	if err != nil {
+		if err.Error() == "equipment not found" {
+			return nil, c.JSON(http.StatusNotFound, map[string]string{"error": "Equipment not found"})
+		}
+		return nil, c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get equipment"})
+	}
+
+	if equipment.UserID != userID {
+		return nil, c.JSON(http.StatusForbidden, map[string]string{"error": "Access denied"})
+	}
+
+	return equipment, nil
+}
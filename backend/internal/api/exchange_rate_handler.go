@@ -0,0 +1,186 @@
+package api
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/toof-jp/shisha-log/backend/internal/currency"
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+	"github.com/toof-jp/shisha-log/backend/internal/repository"
+)
+
+type ExchangeRateHandler struct {
+	repo *repository.ExchangeRateRepository
+}
+
+func NewExchangeRateHandler(repo *repository.ExchangeRateRepository) *ExchangeRateHandler {
+	return &ExchangeRateHandler{repo: repo}
+}
+
+// ImportExchangeRates godoc
+// @Summary Import exchange rates
+// @Description Import exchange rates into the local rate table (admin only). Accepts JSON or CSV with the columns date,base,quote,rate. Existing rates for the same date and currency pair are replaced.
+// @Tags admin
+// @Accept json
+// @Accept text/csv
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param source query string false "Source of the rates (CSV only)"
+// @Param rates body models.ImportExchangeRatesRequest true "Exchange rates"
+// @Success 200 {object} models.ExchangeRateImportResult "Import result"
+// @Failure 400 {object} object{error=string} "Invalid request body"
+// @Failure 401 {object} object{error=string} "Invalid admin token"
+// @Failure 500 {object} object{error=string} "Failed to import exchange rates"
+// @Router /admin/exchange-rates/import [post]
+func (h *ExchangeRateHandler) ImportExchangeRates(c echo.Context) error {
+	var req models.ImportExchangeRatesRequest
+
+	contentType := c.Request().Header.Get(echo.HeaderContentType)
+	if strings.HasPrefix(contentType, "text/csv") {
+		rates, err := parseExchangeRateCSV(c.Request().Body)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		req.Rates = rates
+		if source := c.QueryParam("source"); source != "" {
+			req.Source = &source
+		}
+	} else if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	result := models.ExchangeRateImportResult{Errors: []string{}}
+	inserts := make([]models.ExchangeRateInsert, 0, len(req.Rates))
+	positions := make(map[string]int) // Later rows for the same date and pair replace earlier ones
+	for i, rate := range req.Rates {
+		insert, err := validateExchangeRate(rate)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("row %d: %v", i+1, err))
+			continue
+		}
+		insert.Source = req.Source
+
+		key := insert.RateDate + insert.BaseCurrency + insert.QuoteCurrency
+		if pos, ok := positions[key]; ok {
+			inserts[pos] = insert
+			continue
+		}
+		positions[key] = len(inserts)
+		inserts = append(inserts, insert)
+	}
+
+	if err := h.repo.Upsert(c.Request().Context(), inserts); err != nil {
+		log.Printf("ImportExchangeRates error: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to import exchange rates"})
+	}
+	result.Imported = len(inserts)
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// GetExchangeRates godoc
+// @Summary Get exchange rates
+// @Description Get the locally maintained exchange rates, optionally for one currency pair
+// @Tags exchange-rates
+// @Produce json
+// @Security Bearer
+// @Param base query string false "Base currency (ISO 4217)"
+// @Param quote query string false "Quote currency (ISO 4217)"
+// @Success 200 {object} object{rates=[]models.ExchangeRate} "Exchange rates"
+// @Failure 500 {object} object{error=string} "Failed to get exchange rates"
+// @Router /exchange-rates [get]
+func (h *ExchangeRateHandler) GetExchangeRates(c echo.Context) error {
+	base := currency.Normalize(c.QueryParam("base"))
+	quote := currency.Normalize(c.QueryParam("quote"))
+
+	rates, err := h.repo.GetAll(c.Request().Context(), base, quote)
+	if err != nil {
+		log.Printf("GetExchangeRates error: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get exchange rates"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"rates": rates,
+	})
+}
+
+func validateExchangeRate(rate models.ExchangeRateImport) (models.ExchangeRateInsert, error) {
+	base := currency.Normalize(rate.Base)
+	quote := currency.Normalize(rate.Quote)
+
+	if _, err := time.Parse("2006-01-02", rate.Date); err != nil {
+		return models.ExchangeRateInsert{}, fmt.Errorf("invalid date %q, use YYYY-MM-DD", rate.Date)
+	}
+	if !currency.IsValidCode(base) {
+		return models.ExchangeRateInsert{}, fmt.Errorf("invalid base currency %q", rate.Base)
+	}
+	if !currency.IsValidCode(quote) {
+		return models.ExchangeRateInsert{}, fmt.Errorf("invalid quote currency %q", rate.Quote)
+	}
+	if base == quote {
+		return models.ExchangeRateInsert{}, fmt.Errorf("base and quote currency must differ")
+	}
+	if rate.Rate <= 0 {
+		return models.ExchangeRateInsert{}, fmt.Errorf("rate must be greater than 0")
+	}
+
+	return models.ExchangeRateInsert{
+		RateDate:      rate.Date,
+		BaseCurrency:  base,
+		QuoteCurrency: quote,
+		Rate:          rate.Rate,
+	}, nil
+}
+
+// parseExchangeRateCSV parses rates from CSV with a date,base,quote,rate header
+func parseExchangeRateCSV(r io.Reader) ([]models.ExchangeRateImport, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: missing header")
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, name := range []string{"date", "base", "quote", "rate"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("invalid CSV: missing %s column", name)
+		}
+	}
+
+	var rates []models.ExchangeRateImport
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %v", err)
+		}
+
+		rate, err := strconv.ParseFloat(strings.TrimSpace(record[columns["rate"]]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: line %d: invalid rate", line)
+		}
+
+		rates = append(rates, models.ExchangeRateImport{
+			Date:  strings.TrimSpace(record[columns["date"]]),
+			Base:  record[columns["base"]],
+			Quote: record[columns["quote"]],
+			Rate:  rate,
+		})
+	}
+
+	return rates, nil
+}
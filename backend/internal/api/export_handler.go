@@ -0,0 +1,314 @@
+package api
+
+import (
+	"bytes"
+	"encoding/csv"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/toof-jp/shisha-log/backend/internal/currency"
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+	"github.com/toof-jp/shisha-log/backend/internal/report"
+	"github.com/toof-jp/shisha-log/backend/internal/repository"
+)
+
+// CSV layouts of the session export
+const (
+	csvLayoutColumns = "columns" // One row per session with numbered flavor columns
+	csvLayoutRows    = "rows"    // One row per flavor, repeating the session fields
+)
+
+// Journal export formats
+const (
+	journalFormatMarkdown = "markdown"
+	journalFormatPDF      = "pdf"
+)
+
+// sessionCSVHeader lists the session columns written before the flavor columns
+var sessionCSVHeader = []string{
+	"id", "session_date", "local_date", "store_name", "mix_name", "creator",
+	"amount", "currency", "duration_minutes", "rating", "notes", "order_details", "created_at",
+}
+
+type ExportHandler struct {
+	sessionRepo *repository.SessionRepository
+	userRepo    *repository.UserRepository
+	rateRepo    *repository.ExchangeRateRepository
+}
+
+func NewExportHandler(sessionRepo *repository.SessionRepository, userRepo *repository.UserRepository, rateRepo *repository.ExchangeRateRepository) *ExportHandler {
+	return &ExportHandler{
+		sessionRepo: sessionRepo,
+		userRepo:    userRepo,
+		rateRepo:    rateRepo,
+	}
+}
+
+// ExportSessionsCSV godoc
+// @Summary Export sessions as CSV
+// @Description Stream the user's sessions (newest session date first) as UTF-8 CSV. With layout=columns each session is one row with numbered flavor and brand columns; with layout=rows each flavor is one row.
+// @Tags export
+// @Produce text/csv
+// @Security Bearer
+// @Param layout query string false "columns or rows" default(columns)
+// @Param bom query bool false "Start the file with a UTF-8 byte order mark for Excel" default(false)
+// @Param from query string false "Only sessions on or after this date (YYYY-MM-DD)"
+// @Param to query string false "Only sessions on or before this date (YYYY-MM-DD)"
+// @Param timezone query string false "Timezone of the dates (default UTC)"
+// @Param limit query int false "Maximum number of sessions (0 for all)" default(0)
+// @Param offset query int false "Number of matching sessions to skip" default(0)
+// @Success 200 {file} file "Sessions CSV"
+// @Failure 400 {object} object{error=string} "Invalid parameters"
+// @Failure 500 {object} object{error=string} "Failed to export sessions"
+// @Router /export/sessions.csv [get]
+func (h *ExportHandler) ExportSessionsCSV(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	timezone := c.QueryParam("timezone")
+
+	// Default to UTC if no timezone provided
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		// Fallback to UTC if timezone is invalid
+		loc = time.UTC
+	}
+
+	layout := c.QueryParam("layout")
+	if layout == "" {
+		layout = csvLayoutColumns
+	}
+	if layout != csvLayoutColumns && layout != csvLayoutRows {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid layout parameter. Use columns or rows"})
+	}
+
+	bom := c.QueryParam("bom") == "true" || c.QueryParam("bom") == "1"
+
+	var from, to time.Time
+	if fromStr := c.QueryParam("from"); fromStr != "" {
+		if from, err = time.ParseInLocation("2006-01-02", fromStr, loc); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid from parameter. Use YYYY-MM-DD"})
+		}
+	}
+	if toStr := c.QueryParam("to"); toStr != "" {
+		if to, err = time.ParseInLocation("2006-01-02", toStr, loc); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid to parameter. Use YYYY-MM-DD"})
+		}
+		to = to.AddDate(0, 0, 1)
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	offset, _ := strconv.Atoi(c.QueryParam("offset"))
+	if limit < 0 || offset < 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid limit or offset parameter"})
+	}
+
+	header := append([]string{}, sessionCSVHeader...)
+	flavorColumns := 0
+	if layout == csvLayoutColumns {
+		flavorColumns, err = h.sessionRepo.GetMaxFlavorCount(c.Request().Context(), userID)
+		if err != nil {
+			log.Printf("ExportSessionsCSV error for user %s: %v", userID, err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to export sessions"})
+		}
+		for i := 1; i <= flavorColumns; i++ {
+			n := strconv.Itoa(i)
+			header = append(header, "flavor_"+n, "brand_"+n)
+		}
+	} else {
+		header = append(header, "flavor_order", "flavor_name", "brand")
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	res.Header().Set(echo.HeaderContentDisposition, `attachment; filename="sessions.csv"`)
+	res.WriteHeader(http.StatusOK)
+
+	if bom {
+		if _, err := res.Write([]byte("\ufeff")); err != nil {
+			return err
+		}
+	}
+
+	writer := csv.NewWriter(res)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	err = h.sessionRepo.ForEachSessionBetween(c.Request().Context(), userID, from, to, offset, limit, func(session models.SessionWithFlavors) error {
+		fields := sessionCSVFields(session, loc)
+		if layout == csvLayoutColumns {
+			for i := 0; i < flavorColumns; i++ {
+				if i < len(session.Flavors) {
+					fields = append(fields, stringValue(session.Flavors[i].FlavorName), stringValue(session.Flavors[i].Brand))
+				} else {
+					fields = append(fields, "", "")
+				}
+			}
+			if err := writer.Write(fields); err != nil {
+				return err
+			}
+		} else {
+			// Sessions without flavors still get one row
+			if len(session.Flavors) == 0 {
+				if err := writer.Write(append(fields, "", "", "")); err != nil {
+					return err
+				}
+			}
+			for _, flavor := range session.Flavors {
+				row := append(append([]string{}, fields...), strconv.Itoa(flavor.FlavorOrder), stringValue(flavor.FlavorName), stringValue(flavor.Brand))
+				if err := writer.Write(row); err != nil {
+					return err
+				}
+			}
+		}
+
+		// Flush each session so the client receives the file progressively
+		writer.Flush()
+		res.Flush()
+		return writer.Error()
+	})
+
+	writer.Flush()
+	if err != nil {
+		// The status has already been sent, so the error can only be logged
+		log.Printf("ExportSessionsCSV error for user %s: %v", userID, err)
+	}
+
+	return nil
+}
+
+// sessionCSVFields formats the session columns of a CSV row
+func sessionCSVFields(session models.SessionWithFlavors, loc *time.Location) []string {
+	localTime := session.SessionDate.In(loc)
+	return []string{
+		session.ID,
+		localTime.Format(time.RFC3339),
+		localTime.Format("2006-01-02"),
+		stringValue(session.StoreName),
+		stringValue(session.MixName),
+		stringValue(session.Creator),
+		intValue(session.Amount),
+		stringValue(session.Currency),
+		intValue(session.DurationMinutes),
+		intValue(session.Rating),
+		stringValue(session.Notes),
+		stringValue(session.OrderDetails),
+		session.CreatedAt.In(loc).Format(time.RFC3339),
+	}
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func intValue(value *int) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.Itoa(*value)
+}
+
+// ExportJournal godoc
+// @Summary Export session journal
+// @Description Render the sessions in a date range as a printable journal grouped by month, with flavors, store, creator, amount and notes, preceded by a summary (sessions, active days, spend, average rating and top flavors, stores and creators). Markdown or PDF (A4, Japanese Mincho font).
+// @Tags export
+// @Produce text/markdown
+// @Produce application/pdf
+// @Security Bearer
+// @Param format query string false "markdown or pdf" default(markdown)
+// @Param from query string false "Only sessions on or after this date (YYYY-MM-DD)"
+// @Param to query string false "Only sessions on or before this date (YYYY-MM-DD)"
+// @Param timezone query string false "Timezone of the dates (default UTC)"
+// @Param currency query string false "Currency of the spend (ISO 4217, default is the user's default currency)"
+// @Param limit query int false "Number of entries in the top lists" default(5)
+// @Success 200 {file} file "Journal"
+// @Failure 400 {object} object{error=string} "Invalid parameters"
+// @Failure 500 {object} object{error=string} "Failed to export journal"
+// @Router /export/journal [get]
+func (h *ExportHandler) ExportJournal(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	timezone := c.QueryParam("timezone")
+
+	// Default to UTC if no timezone provided
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid timezone"})
+	}
+
+	format := c.QueryParam("format")
+	if format == "" {
+		format = journalFormatMarkdown
+	}
+	if format != journalFormatMarkdown && format != journalFormatPDF {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid format parameter. Use markdown or pdf"})
+	}
+
+	var from, to time.Time
+	if fromStr := c.QueryParam("from"); fromStr != "" {
+		if from, err = time.ParseInLocation("2006-01-02", fromStr, loc); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid from parameter. Use YYYY-MM-DD"})
+		}
+	}
+	if toStr := c.QueryParam("to"); toStr != "" {
+		if to, err = time.ParseInLocation("2006-01-02", toStr, loc); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid to parameter. Use YYYY-MM-DD"})
+		}
+		to = to.AddDate(0, 0, 1)
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "from must not be after to"})
+	}
+
+	limit := 5
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid limit parameter"})
+		}
+		limit = parsed
+	}
+
+	target := currency.Normalize(c.QueryParam("currency"))
+	if target != "" && !currency.IsValidCode(target) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid currency parameter"})
+	}
+
+	converter, err := loadAmountConverter(c.Request().Context(), h.userRepo, h.rateRepo, userID, target)
+	if err != nil {
+		log.Printf("ExportJournal error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to export journal"})
+	}
+
+	journal, err := h.sessionRepo.GetJournal(c.Request().Context(), userID, from, to, timezone, limit, converter)
+	if err != nil {
+		log.Printf("ExportJournal error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to export journal"})
+	}
+
+	var document bytes.Buffer
+	contentType, filename := "text/markdown; charset=utf-8", "journal.md"
+	if format == journalFormatPDF {
+		contentType, filename = "application/pdf", "journal.pdf"
+		err = report.RenderJournalPDF(&document, journal)
+	} else {
+		err = report.RenderJournalMarkdown(&document, journal)
+	}
+	if err != nil {
+		log.Printf("ExportJournal render error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to render journal"})
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+	return c.Blob(http.StatusOK, contentType, document.Bytes())
+}
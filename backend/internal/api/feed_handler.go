@@ -0,0 +1,245 @@
+package api
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/toof-jp/shisha-log/backend/internal/ical"
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+	"github.com/toof-jp/shisha-log/backend/internal/repository"
+)
+
+const (
+	// feedProductID identifies the calendar feed producer
+	feedProductID = "-//Shisha Log//Sessions//JA"
+	// feedUIDDomain makes event UIDs globally unique. It is fixed so UIDs stay
+	// the same whichever host the feed is fetched through.
+	feedUIDDomain = "shisha.toof.jp"
+)
+
+type FeedHandler struct {
+	feedTokenRepo *repository.FeedTokenRepository
+	sessionRepo   *repository.SessionRepository
+	publicURL     string // Base URL of the API, empty to use the request
+}
+
+func NewFeedHandler(feedTokenRepo *repository.FeedTokenRepository, sessionRepo *repository.SessionRepository, publicURL string) *FeedHandler {
+	return &FeedHandler{
+		feedTokenRepo: feedTokenRepo,
+		sessionRepo:   sessionRepo,
+		publicURL:     publicURL,
+	}
+}
+
+// GetFeed godoc
+// @Summary Get calendar feed
+// @Description Get the iCalendar feed URL of the current user
+// @Tags feeds
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.FeedTokenResponse
+// @Failure 404 {object} object{error=string} "Feed not enabled"
+// @Failure 500 {object} object{error=string} "Failed to get feed"
+// @Router /users/me/feed [get]
+func (h *FeedHandler) GetFeed(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid user ID"})
+	}
+
+	feedToken, err := h.feedTokenRepo.GetActiveByUserID(userUUID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Feed not enabled"})
+	}
+	if err != nil {
+		log.Printf("GetFeed error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get feed"})
+	}
+
+	return c.JSON(http.StatusOK, h.feedResponse(c, feedToken))
+}
+
+// RegenerateFeed godoc
+// @Summary Create or regenerate calendar feed
+// @Description Create a new secret iCalendar feed URL for the current user. Any previous URL stops working.
+// @Tags feeds
+// @Produce json
+// @Security Bearer
+// @Success 201 {object} models.FeedTokenResponse
+// @Failure 500 {object} object{error=string} "Failed to create feed"
+// @Router /users/me/feed [post]
+func (h *FeedHandler) RegenerateFeed(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid user ID"})
+	}
+
+	token, err := generateFeedToken()
+	if err != nil {
+		log.Printf("RegenerateFeed error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create feed"})
+	}
+
+	feedToken, err := h.feedTokenRepo.Replace(userUUID, token)
+	if err != nil {
+		log.Printf("RegenerateFeed error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create feed"})
+	}
+
+	return c.JSON(http.StatusCreated, h.feedResponse(c, feedToken))
+}
+
+// RevokeFeed godoc
+// @Summary Revoke calendar feed
+// @Description Revoke the iCalendar feed URL of the current user
+// @Tags feeds
+// @Security Bearer
+// @Success 204 "No Content"
+// @Failure 404 {object} object{error=string} "Feed not enabled"
+// @Failure 500 {object} object{error=string} "Failed to revoke feed"
+// @Router /users/me/feed [delete]
+func (h *FeedHandler) RevokeFeed(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid user ID"})
+	}
+
+	revoked, err := h.feedTokenRepo.RevokeAllByUserID(userUUID)
+	if err != nil {
+		log.Printf("RevokeFeed error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to revoke feed"})
+	}
+	if !revoked {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Feed not enabled"})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetSessionsICS godoc
+// @Summary Calendar feed of sessions
+// @Description Public iCalendar feed with one event per session, authorized by the secret token in the URL. The summary is the mix name or the flavors, the location the store and the description the notes. Sessions with a duration get an end time.
+// @Tags feeds
+// @Produce text/calendar
+// @Param token path string true "Feed token"
+// @Success 200 {file} file "iCalendar feed"
+// @Failure 404 {object} object{error=string} "Feed not found"
+// @Failure 500 {object} object{error=string} "Failed to get feed"
+// @Router /feeds/{token}/sessions.ics [get]
+func (h *FeedHandler) GetSessionsICS(c echo.Context) error {
+	feedToken, err := h.feedTokenRepo.GetActiveByToken(c.Param("token"))
+	if errors.Is(err, sql.ErrNoRows) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Feed not found"})
+	}
+	if err != nil {
+		log.Printf("GetSessionsICS error: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get feed"})
+	}
+	userID := feedToken.UserID.String()
+
+	if err := h.feedTokenRepo.UpdateLastUsedAt(feedToken.ID); err != nil {
+		log.Printf("GetSessionsICS error for user %s: %v", userID, err)
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/calendar; charset=utf-8")
+	res.Header().Set(echo.HeaderContentDisposition, `inline; filename="sessions.ics"`)
+	res.Header().Set("Cache-Control", "private, max-age=900")
+	res.WriteHeader(http.StatusOK)
+
+	writer, err := ical.NewWriter(res, feedProductID, "Shisha Log")
+	if err != nil {
+		return err
+	}
+
+	err = h.sessionRepo.ForEachSession(c.Request().Context(), userID, func(session models.SessionWithFlavors) error {
+		return writer.WriteEvent(sessionEvent(session))
+	})
+	if err != nil {
+		// The status is already sent, calendar apps discard the incomplete feed
+		log.Printf("GetSessionsICS error for user %s: %v", userID, err)
+		return nil
+	}
+
+	return writer.Close()
+}
+
+// sessionEvent converts a session to a calendar event
+func sessionEvent(session models.SessionWithFlavors) ical.Event {
+	var flavors []string
+	for _, flavor := range session.Flavors {
+		if flavor.FlavorName != nil && *flavor.FlavorName != "" {
+			flavors = append(flavors, *flavor.FlavorName)
+		}
+	}
+
+	summary := "シーシャ"
+	switch {
+	case session.MixName != nil && *session.MixName != "" && len(flavors) > 0:
+		summary = fmt.Sprintf("%s (%s)", *session.MixName, strings.Join(flavors, " / "))
+	case session.MixName != nil && *session.MixName != "":
+		summary = *session.MixName
+	case len(flavors) > 0:
+		summary = strings.Join(flavors, " / ")
+	}
+
+	event := ical.Event{
+		UID:          session.ID + "@" + feedUIDDomain,
+		Created:      session.CreatedAt,
+		LastModified: session.UpdatedAt,
+		Start:        session.SessionDate,
+		Summary:      summary,
+		Categories:   []string{"Shisha"},
+	}
+	if session.DurationMinutes != nil && *session.DurationMinutes > 0 {
+		event.End = session.SessionDate.Add(time.Duration(*session.DurationMinutes) * time.Minute)
+	}
+	if session.StoreName != nil {
+		event.Location = *session.StoreName
+	}
+	if session.Notes != nil {
+		event.Description = *session.Notes
+	}
+
+	return event
+}
+
+// feedResponse builds the subscription URL of a feed token. Without a
+// configured public URL the request is used, and the scheme follows the
+// X-Forwarded-Proto header of a TLS terminating proxy.
+func (h *FeedHandler) feedResponse(c echo.Context, feedToken *models.FeedToken) models.FeedTokenResponse {
+	baseURL := h.publicURL
+	if baseURL == "" {
+		baseURL = c.Scheme() + "://" + c.Request().Host
+	}
+
+	response := models.FeedTokenResponse{
+		URL:       fmt.Sprintf("%s/v1/feeds/%s/sessions.ics", baseURL, feedToken.Token),
+		CreatedAt: feedToken.CreatedAt,
+	}
+	if feedToken.LastUsedAt.Valid {
+		response.LastUsedAt = &feedToken.LastUsedAt.Time
+	}
+	return response
+}
+
+// generateFeedToken generates a secure random feed token
+func generateFeedToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
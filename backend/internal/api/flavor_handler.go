@@ -0,0 +1,181 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+	"github.com/toof-jp/shisha-log/backend/internal/repository"
+)
+
+type FlavorHandler struct {
+	sessionRepo *repository.SessionRepository
+}
+
+func NewFlavorHandler(sessionRepo *repository.SessionRepository) *FlavorHandler {
+	return &FlavorHandler{sessionRepo: sessionRepo}
+}
+
+// GetFlavorPairs godoc
+// @Summary Get flavor pair statistics
+// @Description Get flavor pairs used in the same session with co-occurrence counts, lift/PMI scores and the average session rating
+// @Tags statistics
+// @Produce json
+// @Security Bearer
+// @Param min_count query int false "Minimum number of sessions with both flavors" default(1)
+// @Param sort query string false "Sort order: count, lift, pmi or rating" default(count)
+// @Param limit query int false "Maximum number of pairs (0 for all)" default(50)
+// @Success 200 {object} models.FlavorPairStats "Flavor pair statistics"
+// @Failure 400 {object} object{error=string} "Invalid parameters"
+// @Failure 500 {object} object{error=string} "Failed to get flavor pairs"
+// @Router /flavors/pairs [get]
+func (h *FlavorHandler) GetFlavorPairs(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	minCount, sortBy, limit, err := parsePairParams(c, 50)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	stats, err := h.sessionRepo.GetFlavorPairStats(c.Request().Context(), userID, minCount, sortBy, limit)
+	if err != nil {
+		log.Printf("GetFlavorPairs error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get flavor pairs"})
+	}
+
+	return c.JSON(http.StatusOK, stats)
+}
+
+// GetFlavorPartners godoc
+// @Summary Get flavor partners
+// @Description Get the flavors most often combined with a flavor, with confidence, lift/PMI scores and the average session rating
+// @Tags statistics
+// @Produce json
+// @Security Bearer
+// @Param name path string true "Flavor name (case-insensitive)"
+// @Param min_count query int false "Minimum number of sessions with both flavors" default(1)
+// @Param sort query string false "Sort order: count, lift, pmi or rating" default(count)
+// @Param limit query int false "Maximum number of partners (0 for all)" default(10)
+// @Success 200 {object} models.FlavorPartners "Flavor partners"
+// @Failure 400 {object} object{error=string} "Invalid parameters"
+// @Failure 404 {object} object{error=string} "Flavor not found"
+// @Failure 500 {object} object{error=string} "Failed to get flavor partners"
+// @Router /flavors/{name}/partners [get]
+func (h *FlavorHandler) GetFlavorPartners(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	// Echo routes on the raw path when the URL has one, e.g. for an escaped "/",
+	// and then leaves the parameter escaped; otherwise it is already decoded
+	name := c.Param("name")
+	if c.Request().URL.RawPath != "" {
+		unescaped, err := url.PathUnescape(name)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid flavor name"})
+		}
+		name = unescaped
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid flavor name"})
+	}
+
+	minCount, sortBy, limit, err := parsePairParams(c, 10)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	partners, err := h.sessionRepo.GetFlavorPartners(c.Request().Context(), userID, name, minCount, sortBy, limit)
+	if err != nil {
+		log.Printf("GetFlavorPartners error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get flavor partners"})
+	}
+	if partners == nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Flavor not found"})
+	}
+
+	return c.JSON(http.StatusOK, partners)
+}
+
+// GetBrandStats godoc
+// @Summary Get brand statistics
+// @Description Get session counts, main flavor share and top flavors per brand, and the brand diversity per week, month or year
+// @Tags statistics
+// @Produce json
+// @Security Bearer
+// @Param bucket query string false "Diversity bucket size: week, month or year" default(month)
+// @Param timezone query string false "Timezone (default UTC)"
+// @Param flavor_limit query int false "Number of top flavors per brand" default(5)
+// @Success 200 {object} models.BrandStats "Brand statistics"
+// @Failure 400 {object} object{error=string} "Invalid parameters"
+// @Failure 500 {object} object{error=string} "Failed to get brand statistics"
+// @Router /brands/stats [get]
+func (h *FlavorHandler) GetBrandStats(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	timezone := c.QueryParam("timezone")
+
+	// Default to UTC if no timezone provided
+	if timezone == "" {
+		timezone = "UTC"
+	}
+
+	bucket := c.QueryParam("bucket")
+	if bucket == "" {
+		bucket = repository.BucketMonth
+	}
+	if bucket != repository.BucketWeek && bucket != repository.BucketMonth && bucket != repository.BucketYear {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid bucket parameter. Use week, month or year"})
+	}
+
+	flavorLimit := 5
+	if limitStr := c.QueryParam("flavor_limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid flavor_limit parameter"})
+		}
+		flavorLimit = parsed
+	}
+
+	stats, err := h.sessionRepo.GetBrandStats(c.Request().Context(), userID, bucket, timezone, flavorLimit)
+	if err != nil {
+		log.Printf("GetBrandStats error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get brand statistics"})
+	}
+
+	return c.JSON(http.StatusOK, stats)
+}
+
+// parsePairParams parses the min_count, sort and limit parameters
+func parsePairParams(c echo.Context, defaultLimit int) (int, string, int, error) {
+	minCount := 1
+	if minCountStr := c.QueryParam("min_count"); minCountStr != "" {
+		parsed, err := strconv.Atoi(minCountStr)
+		if err != nil || parsed < 1 {
+			return 0, "", 0, fmt.Errorf("invalid min_count parameter")
+		}
+		minCount = parsed
+	}
+
+	sortBy := c.QueryParam("sort")
+	if sortBy == "" {
+		sortBy = models.PairSortCount
+	}
+	if !models.IsValidPairSort(sortBy) {
+		return 0, "", 0, fmt.Errorf("invalid sort parameter, use count, lift, pmi or rating")
+	}
+
+	limit := defaultLimit
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 0 {
+			return 0, "", 0, fmt.Errorf("invalid limit parameter")
+		}
+		limit = parsed
+	}
+
+	return minCount, sortBy, limit, nil
+}
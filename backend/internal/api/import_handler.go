@@ -0,0 +1,219 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/toof-jp/shisha-log/backend/internal/importer"
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+	"github.com/toof-jp/shisha-log/backend/internal/repository"
+)
+
+const (
+	// maxImportFileSize limits the size of an uploaded import file
+	maxImportFileSize = 10 << 20
+	// maxImportRows limits the number of rows in an import file
+	maxImportRows = 10000
+)
+
+type ImportHandler struct {
+	sessionRepo *repository.SessionRepository
+	userRepo    *repository.UserRepository
+}
+
+func NewImportHandler(sessionRepo *repository.SessionRepository, userRepo *repository.UserRepository) *ImportHandler {
+	return &ImportHandler{
+		sessionRepo: sessionRepo,
+		userRepo:    userRepo,
+	}
+}
+
+// ImportCSV godoc
+// @Summary Import sessions from CSV
+// @Description Import sessions from a CSV file using a column mapping. Runs as a dry run by default, reporting parsed rows, validation errors and suspected duplicates (same date and store). With dry_run=false all valid rows are created in one batch; the commit is rejected if any row is invalid. Without a mapping, columns named like the session fields (as in the CSV export) are used.
+// @Tags import
+// @Accept multipart/form-data
+// @Produce json
+// @Security Bearer
+// @Param file formData file true "CSV file"
+// @Param mapping formData string false "JSON object mapping session fields (session_date, store_name, mix_name, creator, notes, order_details, amount, currency, duration_minutes, rating, flavors, flavor_N, brand_N) to column names"
+// @Param timezone formData string false "Timezone of dates without an offset (default UTC)"
+// @Param date_format formData string false "Go time layout of the dates, e.g. 02/01/2006 for day-first dates"
+// @Param dry_run formData bool false "Only report what would be imported" default(true)
+// @Param include_duplicates formData bool false "Also import rows that look like duplicates" default(false)
+// @Success 200 {object} models.ImportCSVResult "Dry run result"
+// @Success 201 {object} models.ImportCSVResult "Import result"
+// @Failure 400 {object} object{error=string} "Invalid file, mapping or rows"
+// @Failure 500 {object} object{error=string} "Failed to import sessions"
+// @Router /import/csv [post]
+func (h *ImportHandler) ImportCSV(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	timezone := c.FormValue("timezone")
+	// Default to UTC if no timezone provided
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid timezone"})
+	}
+
+	dryRun := c.FormValue("dry_run") != "false" && c.FormValue("dry_run") != "0"
+	includeDuplicates := c.FormValue("include_duplicates") == "true" || c.FormValue("include_duplicates") == "1"
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "File is required"})
+	}
+	if fileHeader.Size > maxImportFileSize {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "File is too large"})
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Failed to read file"})
+	}
+	defer file.Close()
+
+	csvFile, err := importer.ReadCSV(file)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if len(csvFile.Records) > maxImportRows {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "File has too many rows"})
+	}
+
+	mapping := importer.AutoMapping(csvFile.Header)
+	if mappingStr := c.FormValue("mapping"); mappingStr != "" {
+		mapping = make(map[string]string)
+		if err := json.Unmarshal([]byte(mappingStr), &mapping); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid mapping, use a JSON object of field to column name"})
+		}
+	}
+
+	parser, err := importer.NewParser(csvFile.Header, mapping, loc, c.FormValue("date_format"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	// Index existing sessions by local date and store to detect duplicates
+	existing, err := h.sessionRepo.GetByUserID(c.Request().Context(), userID, 10000, 0)
+	if err != nil {
+		log.Printf("ImportCSV error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to import sessions"})
+	}
+	existingKeys := make(map[string]string, len(existing))
+	for _, session := range existing {
+		existingKeys[duplicateKey(session.SessionDate, session.StoreName, loc)] = session.ID
+	}
+
+	result := models.ImportCSVResult{
+		DryRun:    dryRun,
+		Timezone:  loc.String(),
+		Columns:   csvFile.Header,
+		Mapping:   mapping,
+		TotalRows: len(csvFile.Records),
+		Rows:      make([]models.ImportRow, 0, len(csvFile.Records)),
+	}
+	fileKeys := make(map[string]int)
+
+	for i, record := range csvFile.Records {
+		row := models.ImportRow{Line: i + 2, Errors: []string{}}
+
+		session, errs := parser.Parse(record)
+		row.Session = session
+		row.Errors = append(row.Errors, errs...)
+
+		if len(row.Errors) > 0 {
+			result.InvalidRows++
+			result.Rows = append(result.Rows, row)
+			continue
+		}
+		result.ValidRows++
+
+		key := duplicateKey(session.SessionDate, session.StoreName, loc)
+		if id, ok := existingKeys[key]; ok {
+			row.DuplicateOf = &id
+		}
+		if line, ok := fileKeys[key]; ok {
+			row.DuplicateOfLine = &line
+		} else {
+			fileKeys[key] = row.Line
+		}
+		if row.DuplicateOf != nil || row.DuplicateOfLine != nil {
+			result.DuplicateRows++
+		}
+
+		result.Rows = append(result.Rows, row)
+	}
+
+	if dryRun {
+		return c.JSON(http.StatusOK, result)
+	}
+
+	if result.InvalidRows > 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "The file has invalid rows, run a dry run to see the errors"})
+	}
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid user ID"})
+	}
+	user, err := h.userRepo.GetByID(userUUID)
+	if err != nil {
+		log.Printf("ImportCSV error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to import sessions"})
+	}
+
+	var sessions []models.ShishaSession
+	var flavors [][]models.CreateFlavorRequest
+	for _, row := range result.Rows {
+		if !includeDuplicates && (row.DuplicateOf != nil || row.DuplicateOfLine != nil) {
+			continue
+		}
+
+		imported := row.Session
+		sessionCurrency := user.DefaultCurrency
+		if imported.Currency != nil {
+			sessionCurrency = *imported.Currency
+		}
+
+		sessions = append(sessions, models.ShishaSession{
+			UserID:          userID,
+			CreatedBy:       userID,
+			SessionDate:     imported.SessionDate,
+			StoreName:       imported.StoreName,
+			Notes:           imported.Notes,
+			OrderDetails:    imported.OrderDetails,
+			MixName:         imported.MixName,
+			Creator:         imported.Creator,
+			Amount:          imported.Amount,
+			Currency:        &sessionCurrency,
+			DurationMinutes: imported.DurationMinutes,
+			Rating:          imported.Rating,
+		})
+		flavors = append(flavors, imported.Flavors)
+	}
+
+	if _, err := h.sessionRepo.CreateBatch(c.Request().Context(), sessions, flavors); err != nil {
+		log.Printf("ImportCSV error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to import sessions"})
+	}
+	result.Created = len(sessions)
+
+	return c.JSON(http.StatusCreated, result)
+}
+
+// duplicateKey identifies sessions on the same local date at the same store
+func duplicateKey(sessionDate time.Time, storeName *string, loc *time.Location) string {
+	store := ""
+	if storeName != nil {
+		store = strings.ToLower(strings.TrimSpace(*storeName))
+	}
+	return sessionDate.In(loc).Format("2006-01-02") + "\x00" + store
+}
@@ -0,0 +1,246 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+	"github.com/toof-jp/shisha-log/backend/internal/repository"
+)
+
+// defaultLowStockGrams is the remaining weight at or below which an item is reported as low stock
+const defaultLowStockGrams = 50.0
+
+type InventoryHandler struct {
+	repo *repository.InventoryRepository
+}
+
+func NewInventoryHandler(repo *repository.InventoryRepository) *InventoryHandler {
+	return &InventoryHandler{repo: repo}
+}
+
+// CreateInventoryItem godoc
+// @Summary Add a tobacco package to the inventory
+// @Description Add a tobacco package (flavor, brand, weight, purchase date, price, opened date) to the user's inventory
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param item body models.CreateInventoryRequest true "Inventory item data"
+// @Success 201 {object} models.InventoryItem "Created inventory item"
+// @Failure 400 {object} object{error=string} "Invalid request body"
+// @Failure 500 {object} object{error=string} "Failed to create inventory item"
+// @Router /inventory [post]
+func (h *InventoryHandler) CreateInventoryItem(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	var req models.CreateInventoryRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	if strings.TrimSpace(req.FlavorName) == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Flavor name is required"})
+	}
+	if req.WeightGrams <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Weight must be greater than 0"})
+	}
+	if !isValidDate(req.PurchaseDate) || !isValidDate(req.OpenedDate) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid date format. Use YYYY-MM-DD"})
+	}
+
+	item := &models.InventoryItem{
+		UserID:       userID,
+		FlavorName:   req.FlavorName,
+		Brand:        req.Brand,
+		WeightGrams:  req.WeightGrams,
+		PurchaseDate: nilIfEmpty(req.PurchaseDate),
+		Price:        req.Price,
+		OpenedDate:   nilIfEmpty(req.OpenedDate),
+		Notes:        req.Notes,
+	}
+
+	created, err := h.repo.Create(c.Request().Context(), item)
+	if err != nil {
+		log.Printf("CreateInventoryItem error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create inventory item"})
+	}
+
+	return c.JSON(http.StatusCreated, created)
+}
+
+// GetInventory godoc
+// @Summary Get tobacco inventory
+// @Description Get all inventory items with remaining stock, projected run-out dates and a low-stock list
+// @Tags inventory
+// @Produce json
+// @Security Bearer
+// @Param low_stock_grams query number false "Remaining grams at or below which an item is low on stock" default(50)
+// @Param timezone query string false "Timezone (default UTC)"
+// @Success 200 {object} models.InventoryReport "Inventory report"
+// @Failure 400 {object} object{error=string} "Invalid parameters"
+// @Failure 500 {object} object{error=string} "Failed to get inventory"
+// @Router /inventory [get]
+func (h *InventoryHandler) GetInventory(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	timezone := c.QueryParam("timezone")
+
+	// Default to UTC if no timezone provided
+	if timezone == "" {
+		timezone = "UTC"
+	}
+
+	lowStockGrams := defaultLowStockGrams
+	if lowStockStr := c.QueryParam("low_stock_grams"); lowStockStr != "" {
+		parsed, err := strconv.ParseFloat(lowStockStr, 64)
+		if err != nil || parsed < 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid low_stock_grams parameter"})
+		}
+		lowStockGrams = parsed
+	}
+
+	report, err := h.repo.GetReport(c.Request().Context(), userID, lowStockGrams, timezone)
+	if err != nil {
+		log.Printf("GetInventory error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get inventory"})
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
+
+// GetInventoryItem godoc
+// @Summary Get an inventory item by ID
+// @Description Get a specific tobacco package by its ID
+// @Tags inventory
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Inventory item ID"
+// @Success 200 {object} models.InventoryItem "Inventory item details"
+// @Failure 403 {object} object{error=string} "Access denied"
+// @Failure 404 {object} object{error=string} "Inventory item not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /inventory/{id} [get]
+func (h *InventoryHandler) GetInventoryItem(c echo.Context) error {
+	item, err := h.getOwnedItem(c)
+	if err != nil || item == nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, item)
+}
+
+// UpdateInventoryItem godoc
+// @Summary Update an inventory item
+// @Description Update an existing tobacco package
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Inventory item ID"
+// @Param item body models.UpdateInventoryRequest true "Updated inventory item data"
+// @Success 200 {object} models.InventoryItem "Updated inventory item"
+// @Failure 400 {object} object{error=string} "Invalid request body"
+// @Failure 403 {object} object{error=string} "Access denied"
+// @Failure 404 {object} object{error=string} "Inventory item not found"
+// @Failure 500 {object} object{error=string} "Failed to update inventory item"
+// @Router /inventory/{id} [put]
+func (h *InventoryHandler) UpdateInventoryItem(c echo.Context) error {
+	item, err := h.getOwnedItem(c)
+	if err != nil || item == nil {
+		return err
+	}
+
+	var req models.UpdateInventoryRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	if req.FlavorName != nil && strings.TrimSpace(*req.FlavorName) == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Flavor name cannot be empty"})
+	}
+	if req.WeightGrams != nil && *req.WeightGrams <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Weight must be greater than 0"})
+	}
+	if !isValidDate(req.PurchaseDate) || !isValidDate(req.OpenedDate) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid date format. Use YYYY-MM-DD"})
+	}
+
+	if err := h.repo.Update(c.Request().Context(), item.ID, &req); err != nil {
+		c.Logger().Errorf("Failed to update inventory item %s: %v", item.ID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update inventory item"})
+	}
+
+	updated, err := h.repo.GetByID(c.Request().Context(), item.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get updated inventory item"})
+	}
+
+	return c.JSON(http.StatusOK, updated)
+}
+
+// DeleteInventoryItem godoc
+// @Summary Delete an inventory item
+// @Description Delete a tobacco package. Session flavors drawn from it are kept.
+// @Tags inventory
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Inventory item ID"
+// @Success 200 {object} object{message=string} "Inventory item deleted successfully"
+// @Failure 403 {object} object{error=string} "Access denied"
+// @Failure 404 {object} object{error=string} "Inventory item not found"
+// @Failure 500 {object} object{error=string} "Failed to delete inventory item"
+// @Router /inventory/{id} [delete]
+func (h *InventoryHandler) DeleteInventoryItem(c echo.Context) error {
+	item, err := h.getOwnedItem(c)
+	if err != nil || item == nil {
+		return err
+	}
+
+	if err := h.repo.Delete(c.Request().Context(), item.ID); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to delete inventory item"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "Inventory item deleted successfully"})
+}
+
+// getOwnedItem loads the inventory item from the path and checks that it belongs to
+// the authenticated user. When it returns a nil item the response has already been written.
+func (h *InventoryHandler) getOwnedItem(c echo.Context) (*models.InventoryItem, error) {
+	itemID := c.Param("id")
+	userID := c.Get("user_id").(string)
+
+	item, err := h.repo.GetByID(c.Request().Context(), itemID)
+	if err != nil {
+		if err.Error() == "inventory item not found" {
+			return nil, c.JSON(http.StatusNotFound, map[string]string{"error": "Inventory item not found"})
+		}
+		return nil, c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get inventory item"})
+	}
+
+	if item.UserID != userID {
+		return nil, c.JSON(http.StatusForbidden, map[string]string{"error": "Access denied"})
+	}
+
+	return item, nil
+}
+
+// isValidDate reports whether an optional date is empty or in YYYY-MM-DD format
+func isValidDate(date *string) bool {
+	if date == nil || *date == "" {
+		return true
+	}
+	_, err := time.Parse("2006-01-02", *date)
+	return err == nil
+}
+
+// nilIfEmpty returns nil for an empty optional string
+func nilIfEmpty(s *string) *string {
+	if s == nil || *s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,243 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+	"github.com/toof-jp/shisha-log/backend/internal/repository"
+)
+
+type RecipeHandler struct {
+	repo        *repository.RecipeRepository
+	sessionRepo *repository.SessionRepository
+}
+
+func NewRecipeHandler(repo *repository.RecipeRepository, sessionRepo *repository.SessionRepository) *RecipeHandler {
+	return &RecipeHandler{
+		repo:        repo,
+		sessionRepo: sessionRepo,
+	}
+}
+
+// CreateRecipe godoc
+// @Summary Create a recipe
+// @Description Save a mix recipe (name plus an ordered flavor list with ratios)
+// @Tags recipes
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param recipe body models.CreateRecipeRequest true "Recipe data"
+// @Success 201 {object} models.RecipeWithFlavors "Created recipe with flavors"
+// @Failure 400 {object} object{error=string} "Invalid request body"
+// @Failure 500 {object} object{error=string} "Failed to create recipe"
+// @Router /recipes [post]
+func (h *RecipeHandler) CreateRecipe(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	var req models.CreateRecipeRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	if strings.TrimSpace(req.Name) == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Name is required"})
+	}
+	if err := validateRecipeFlavors(req.Flavors); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	recipe := &models.Recipe{
+		UserID: userID,
+		Name:   req.Name,
+		Notes:  req.Notes,
+	}
+
+	created, err := h.repo.Create(c.Request().Context(), recipe, req.Flavors)
+	if err != nil {
+		log.Printf("CreateRecipe error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create recipe"})
+	}
+
+	return c.JSON(http.StatusCreated, created)
+}
+
+// GetUserRecipes godoc
+// @Summary Get user's recipes
+// @Description Get all saved recipes of the authenticated user
+// @Tags recipes
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} object{recipes=[]models.RecipeWithFlavors} "Recipe list"
+// @Failure 500 {object} object{error=string} "Failed to get recipes"
+// @Router /recipes [get]
+func (h *RecipeHandler) GetUserRecipes(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	recipes, err := h.repo.GetByUserID(c.Request().Context(), userID)
+	if err != nil {
+		log.Printf("GetUserRecipes error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get recipes"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"recipes": recipes,
+	})
+}
+
+// GetRecipe godoc
+// @Summary Get a recipe by ID
+// @Description Get a specific recipe by its ID
+// @Tags recipes
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Recipe ID"
+// @Success 200 {object} models.RecipeWithFlavors "Recipe details with flavors"
+// @Failure 403 {object} object{error=string} "Access denied"
+// @Failure 404 {object} object{error=string} "Recipe not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /recipes/{id} [get]
+func (h *RecipeHandler) GetRecipe(c echo.Context) error {
+	recipe, err := h.getOwnedRecipe(c)
+	if err != nil || recipe == nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, recipe)
+}
+
+// UpdateRecipe godoc
+// @Summary Update a recipe
+// @Description Update an existing recipe. Providing flavors replaces the whole flavor list.
+// @Tags recipes
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Recipe ID"
+// @Param recipe body models.UpdateRecipeRequest true "Updated recipe data"
+// @Success 200 {object} models.RecipeWithFlavors "Updated recipe"
+// @Failure 400 {object} object{error=string} "Invalid request body"
+// @Failure 403 {object} object{error=string} "Access denied"
+// @Failure 404 {object} object{error=string} "Recipe not found"
+// @Failure 500 {object} object{error=string} "Failed to update recipe"
+// @Router /recipes/{id} [put]
+func (h *RecipeHandler) UpdateRecipe(c echo.Context) error {
+	recipe, err := h.getOwnedRecipe(c)
+	if err != nil || recipe == nil {
+		return err
+	}
+
+	var req models.UpdateRecipeRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	if req.Name != nil && strings.TrimSpace(*req.Name) == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Name cannot be empty"})
+	}
+	if req.Flavors != nil {
+		if err := validateRecipeFlavors(*req.Flavors); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+	}
+
+	if err := h.repo.Update(c.Request().Context(), recipe.ID, &req); err != nil {
+		c.Logger().Errorf("Failed to update recipe %s: %v", recipe.ID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update recipe"})
+	}
+
+	updated, err := h.repo.GetByID(c.Request().Context(), recipe.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get updated recipe"})
+	}
+
+	return c.JSON(http.StatusOK, updated)
+}
+
+// DeleteRecipe godoc
+// @Summary Delete a recipe
+// @Description Delete a recipe. Sessions logged from it keep their flavors.
+// @Tags recipes
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Recipe ID"
+// @Success 200 {object} object{message=string} "Recipe deleted successfully"
+// @Failure 403 {object} object{error=string} "Access denied"
+// @Failure 404 {object} object{error=string} "Recipe not found"
+// @Failure 500 {object} object{error=string} "Failed to delete recipe"
+// @Router /recipes/{id} [delete]
+func (h *RecipeHandler) DeleteRecipe(c echo.Context) error {
+	recipe, err := h.getOwnedRecipe(c)
+	if err != nil || recipe == nil {
+		return err
+	}
+
+	if err := h.repo.Delete(c.Request().Context(), recipe.ID); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to delete recipe"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "Recipe deleted successfully"})
+}
+
+// GetRecipeStats godoc
+// @Summary Get recipe statistics
+// @Description Get how often each recipe was used and its average session rating
+// @Tags statistics
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.RecipeStats "Recipe statistics"
+// @Failure 500 {object} object{error=string} "Failed to get recipe statistics"
+// @Router /recipes/stats [get]
+func (h *RecipeHandler) GetRecipeStats(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	recipes, err := h.repo.GetByUserID(c.Request().Context(), userID)
+	if err != nil {
+		log.Printf("GetRecipeStats error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get recipe statistics"})
+	}
+
+	stats, err := h.sessionRepo.GetRecipeStats(c.Request().Context(), userID, recipes)
+	if err != nil {
+		log.Printf("GetRecipeStats error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get recipe statistics"})
+	}
+
+	return c.JSON(http.StatusOK, stats)
+}
+
+// getOwnedRecipe loads the recipe from the path and checks that it belongs to
+// the authenticated user. When it returns a nil recipe the response has already been written.
+func (h *RecipeHandler) getOwnedRecipe(c echo.Context) (*models.RecipeWithFlavors, error) {
+	recipeID := c.Param("id")
+	userID := c.Get("user_id").(string)
+
+	recipe, err := h.repo.GetByID(c.Request().Context(), recipeID)
+	if err != nil {
+		if err.Error() == "recipe not found" {
+			return nil, c.JSON(http.StatusNotFound, map[string]string{"error": "Recipe not found"})
+		}
+		return nil, c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get recipe"})
+	}
+
+	if recipe.UserID != userID {
+		return nil, c.JSON(http.StatusForbidden, map[string]string{"error": "Access denied"})
+	}
+
+	return recipe, nil
+}
+
+func validateRecipeFlavors(flavors []models.CreateRecipeFlavorRequest) error {
+	for i, flavor := range flavors {
+		if strings.TrimSpace(flavor.FlavorName) == "" {
+			return fmt.Errorf("flavor %d: flavor name is required", i+1)
+		}
+		if flavor.Ratio != nil && *flavor.Ratio <= 0 {
+			return fmt.Errorf("flavor %d: ratio must be greater than 0", i+1)
+		}
+	}
+	return nil
+}
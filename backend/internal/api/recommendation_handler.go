@@ -0,0 +1,85 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+	"github.com/toof-jp/shisha-log/backend/internal/recommend"
+	"github.com/toof-jp/shisha-log/backend/internal/repository"
+)
+
+type RecommendationHandler struct {
+	sessionRepo   *repository.SessionRepository
+	inventoryRepo *repository.InventoryRepository
+}
+
+func NewRecommendationHandler(sessionRepo *repository.SessionRepository, inventoryRepo *repository.InventoryRepository) *RecommendationHandler {
+	return &RecommendationHandler{
+		sessionRepo:   sessionRepo,
+		inventoryRepo: inventoryRepo,
+	}
+}
+
+// GetRecommendations godoc
+// @Summary Get recommendations
+// @Description Suggest untried mixes, unsmoked inventory flavors and well rated flavors to revisit, based on the session history, flavor combinations and ratings. Each suggestion comes with an explanation.
+// @Tags recommendations
+// @Produce json
+// @Security Bearer
+// @Param exploration query number false "0 favors well rated flavors, 1 favors unfamiliar ones" default(0.3)
+// @Param type query string false "Only return mix, flavor or revisit suggestions"
+// @Param limit query int false "Number of recommendations" default(10)
+// @Success 200 {object} models.RecommendationList "Recommendations"
+// @Failure 400 {object} object{error=string} "Invalid parameters"
+// @Failure 500 {object} object{error=string} "Failed to get recommendations"
+// @Router /recommendations [get]
+func (h *RecommendationHandler) GetRecommendations(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	opts := recommend.Options{
+		Exploration: 0.3,
+		Type:        c.QueryParam("type"),
+		Limit:       10,
+		Now:         time.Now(),
+	}
+
+	if explorationStr := c.QueryParam("exploration"); explorationStr != "" {
+		parsed, err := strconv.ParseFloat(explorationStr, 64)
+		if err != nil || parsed < 0 || parsed > 1 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Exploration must be between 0 and 1"})
+		}
+		opts.Exploration = parsed
+	}
+
+	switch opts.Type {
+	case "", models.RecommendationMix, models.RecommendationFlavor, models.RecommendationRevisit:
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid type parameter. Use mix, flavor or revisit"})
+	}
+
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 1 || parsed > 100 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid limit parameter"})
+		}
+		opts.Limit = parsed
+	}
+
+	sessions, err := h.sessionRepo.GetByUserID(c.Request().Context(), userID, 10000, 0)
+	if err != nil {
+		log.Printf("GetRecommendations error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get recommendations"})
+	}
+
+	inventory, err := h.inventoryRepo.GetByUserID(c.Request().Context(), userID)
+	if err != nil {
+		log.Printf("GetRecommendations error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get recommendations"})
+	}
+
+	return c.JSON(http.StatusOK, recommend.Recommend(sessions, inventory, opts))
+}
@@ -1,28 +1,63 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"math"
 	"net/http"
 	"strconv"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"github.com/toof-jp/shisha-log/backend/internal/currency"
+	"github.com/toof-jp/shisha-log/backend/internal/events"
 	"github.com/toof-jp/shisha-log/backend/internal/models"
 	"github.com/toof-jp/shisha-log/backend/internal/repository"
+	"github.com/toof-jp/shisha-log/backend/internal/service"
 )
 
+// budgetCheckTimeout bounds the background budget check after a session change
+const budgetCheckTimeout = 30 * time.Second
+
+// errInvalidCurrency is returned for a session currency that is not an ISO 4217 code
+var errInvalidCurrency = errors.New("invalid currency code")
+
 type SessionHandler struct {
-	repo *repository.SessionRepository
+	repo          *repository.SessionRepository
+	equipmentRepo *repository.EquipmentRepository
+	inventoryRepo *repository.InventoryRepository
+	recipeRepo    *repository.RecipeRepository
+	userRepo      *repository.UserRepository
+	budgetService *service.BudgetService
+	bus           *events.Bus
 }
 
-func NewSessionHandler(repo *repository.SessionRepository) *SessionHandler {
-	return &SessionHandler{repo: repo}
+func NewSessionHandler(
+	repo *repository.SessionRepository,
+	equipmentRepo *repository.EquipmentRepository,
+	inventoryRepo *repository.InventoryRepository,
+	recipeRepo *repository.RecipeRepository,
+	userRepo *repository.UserRepository,
+	budgetService *service.BudgetService,
+	bus *events.Bus,
+) *SessionHandler {
+	return &SessionHandler{
+		repo:          repo,
+		equipmentRepo: equipmentRepo,
+		inventoryRepo: inventoryRepo,
+		recipeRepo:    recipeRepo,
+		userRepo:      userRepo,
+		budgetService: budgetService,
+		bus:           bus,
+	}
 }
 
 // CreateSession godoc
 // @Summary Create a new session
-// @Description Create a new shisha session for the authenticated user
+// @Description Create a new shisha session for the authenticated user. When recipe_id is given, flavors and mix_name are pre-filled from the recipe unless provided, and bowl_grams is split across the recipe flavors by their ratios.
 // @Tags sessions
 // @Accept json
 // @Produce json
@@ -40,17 +75,61 @@ func (h *SessionHandler) CreateSession(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
 	}
 
+	if req.Rating != nil && (*req.Rating < 1 || *req.Rating > 5) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Rating must be between 1 and 5"})
+	}
+	if req.DurationMinutes != nil && *req.DurationMinutes <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Duration must be greater than 0"})
+	}
+
+	// Store the currency explicitly so changing the default later keeps old amounts intact
+	sessionCurrency, err := h.sessionCurrency(userID, req.Currency)
+	if errors.Is(err, errInvalidCurrency) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if err != nil {
+		log.Printf("CreateSession error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create session"})
+	}
+
+	// Pre-fill flavors and mix name from the recipe when one is given
+	if req.RecipeID != nil && *req.RecipeID != "" {
+		recipe, err := h.getOwnedRecipe(c, userID, *req.RecipeID)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		if req.BowlGrams != nil && *req.BowlGrams <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "bowl_grams must be greater than 0"})
+		}
+		applyRecipe(&req, recipe)
+	} else {
+		req.RecipeID = nil
+	}
+
+	// Handle optional equipment
+	var equipmentIDs []string
+	if req.EquipmentIDs != nil {
+		equipmentIDs = *req.EquipmentIDs
+	}
+	if err := h.validateEquipmentOwnership(c, userID, equipmentIDs); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
 	// Always use the authenticated user's ID
 	session := &models.ShishaSession{
-		UserID:       userID,
-		CreatedBy:    userID,
-		SessionDate:  req.SessionDate,
-		StoreName:    req.StoreName,
-		Notes:        req.Notes,
-		OrderDetails: req.OrderDetails,
-		MixName:      req.MixName,
-		Creator:      req.Creator,
-		Amount:       req.Amount,
+		UserID:          userID,
+		CreatedBy:       userID,
+		SessionDate:     req.SessionDate,
+		StoreName:       req.StoreName,
+		Notes:           req.Notes,
+		OrderDetails:    req.OrderDetails,
+		MixName:         req.MixName,
+		Creator:         req.Creator,
+		Amount:          req.Amount,
+		Currency:        &sessionCurrency,
+		DurationMinutes: req.DurationMinutes,
+		Rating:          req.Rating,
+		RecipeID:        req.RecipeID,
 	}
 
 	// Handle optional flavors
@@ -58,12 +137,19 @@ func (h *SessionHandler) CreateSession(c echo.Context) error {
 	if req.Flavors != nil {
 		flavors = *req.Flavors
 	}
+	if err := h.prepareInventoryFlavors(c, userID, flavors); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
 
-	createdSession, err := h.repo.Create(c.Request().Context(), session, flavors)
+	createdSession, err := h.repo.Create(c.Request().Context(), session, flavors, equipmentIDs)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create session"})
 	}
 
+	h.checkBudgets(userID)
+
+	h.bus.Publish(events.Event{Type: events.SessionCreated, UserID: userID, Data: createdSession})
+
 	return c.JSON(http.StatusCreated, createdSession)
 }
 
@@ -104,6 +190,57 @@ func (h *SessionHandler) GetSession(c echo.Context) error {
 	return c.JSON(http.StatusOK, session)
 }
 
+// GetSimilarSessions godoc
+// @Summary Get similar sessions
+// @Description Rank the user's other sessions by similarity to a session using flavor overlap weighted by flavor order, the same store, creator and mix name
+// @Tags sessions
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Session ID"
+// @Param limit query int false "Number of similar sessions" default(5)
+// @Success 200 {object} object{sessions=[]models.SimilarSession} "Similar sessions ordered by score"
+// @Failure 400 {object} object{error=string} "Invalid parameters"
+// @Failure 403 {object} object{error=string} "Access denied"
+// @Failure 404 {object} object{error=string} "Session not found"
+// @Failure 500 {object} object{error=string} "Failed to get similar sessions"
+// @Router /sessions/{id}/similar [get]
+func (h *SessionHandler) GetSimilarSessions(c echo.Context) error {
+	sessionID := c.Param("id")
+	userID := c.Get("user_id").(string)
+
+	limit := 5
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 1 || parsed > 100 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid limit parameter"})
+		}
+		limit = parsed
+	}
+
+	session, err := h.repo.GetByID(c.Request().Context(), sessionID)
+	if err != nil {
+		if err.Error() == "session not found" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Session not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get session"})
+	}
+
+	// Check if user has access to this session
+	if session.UserID != userID {
+		return c.JSON(http.StatusForbidden, map[string]string{"error": "Access denied"})
+	}
+
+	similar, err := h.repo.GetSimilarSessions(c.Request().Context(), userID, session, limit)
+	if err != nil {
+		log.Printf("GetSimilarSessions error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get similar sessions"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"sessions": similar,
+	})
+}
+
 // GetUserSessions godoc
 // @Summary Get user's sessions
 // @Description Get paginated list of sessions for the authenticated user
@@ -185,6 +322,43 @@ func (h *SessionHandler) UpdateSession(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
 	}
 
+	// A rating of 0 clears the rating
+	if req.Rating != nil && (*req.Rating < 0 || *req.Rating > 5) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Rating must be between 0 and 5 (0 clears the rating)"})
+	}
+
+	// A duration of 0 clears the duration
+	if req.DurationMinutes != nil && *req.DurationMinutes < 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Duration must be greater than 0"})
+	}
+
+	// An empty currency falls back to the user's default currency
+	if req.Currency != nil && *req.Currency != "" {
+		code := currency.Normalize(*req.Currency)
+		if !currency.IsValidCode(code) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid currency code"})
+		}
+		req.Currency = &code
+	}
+
+	if req.EquipmentIDs != nil {
+		if err := h.validateEquipmentOwnership(c, userID, *req.EquipmentIDs); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+	}
+
+	if req.RecipeID != nil && *req.RecipeID != "" {
+		if _, err := h.getOwnedRecipe(c, userID, *req.RecipeID); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+	}
+
+	if req.Flavors != nil {
+		if err := h.prepareInventoryFlavors(c, userID, *req.Flavors); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+	}
+
 	// Debug log
 	c.Logger().Infof("UpdateSession request for ID %s: %+v", sessionID, req)
 
@@ -199,6 +373,10 @@ func (h *SessionHandler) UpdateSession(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get updated session"})
 	}
 
+	h.checkBudgets(userID)
+
+	h.bus.Publish(events.Event{Type: events.SessionUpdated, UserID: userID, Data: updatedSession})
+
 	return c.JSON(http.StatusOK, updatedSession)
 }
 
@@ -235,6 +413,9 @@ func (h *SessionHandler) DeleteSession(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to delete session"})
 	}
 
+	// The event carries the session as it was before the deletion
+	h.bus.Publish(events.Event{Type: events.SessionDeleted, UserID: userID, Data: session})
+
 	return c.JSON(http.StatusOK, map[string]string{"message": "Session deleted successfully"})
 }
 
@@ -429,3 +610,136 @@ func (h *SessionHandler) GetOrderStats(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, stats)
 }
+
+// validateEquipmentOwnership checks that every equipment ID belongs to the user
+func (h *SessionHandler) validateEquipmentOwnership(c echo.Context, userID string, equipmentIDs []string) error {
+	if len(equipmentIDs) == 0 {
+		return nil
+	}
+
+	equipment, err := h.equipmentRepo.GetByUserID(c.Request().Context(), userID, "")
+	if err != nil {
+		return fmt.Errorf("failed to verify equipment")
+	}
+
+	owned := make(map[string]bool, len(equipment))
+	for _, item := range equipment {
+		owned[item.ID] = true
+	}
+
+	for _, equipmentID := range equipmentIDs {
+		if !owned[equipmentID] {
+			return fmt.Errorf("unknown equipment: %s", equipmentID)
+		}
+	}
+
+	return nil
+}
+
+// prepareInventoryFlavors validates flavors drawn from the inventory and fills in
+// the flavor name and brand from the inventory item when they are omitted
+func (h *SessionHandler) prepareInventoryFlavors(c echo.Context, userID string, flavors []models.CreateFlavorRequest) error {
+	for i := range flavors {
+		flavor := &flavors[i]
+
+		if flavor.Grams != nil && *flavor.Grams <= 0 {
+			return fmt.Errorf("grams must be greater than 0")
+		}
+		if flavor.InventoryID == nil || *flavor.InventoryID == "" {
+			flavor.InventoryID = nil
+			continue
+		}
+
+		item, err := h.inventoryRepo.GetByID(c.Request().Context(), *flavor.InventoryID)
+		if err != nil || item.UserID != userID {
+			return fmt.Errorf("unknown inventory item: %s", *flavor.InventoryID)
+		}
+
+		if flavor.FlavorName == nil || *flavor.FlavorName == "" {
+			flavor.FlavorName = &item.FlavorName
+		}
+		if flavor.Brand == nil || *flavor.Brand == "" {
+			flavor.Brand = item.Brand
+		}
+	}
+
+	return nil
+}
+
+// checkBudgets checks the budgets of the user in the background so the
+// response is not delayed. The check is abandoned after budgetCheckTimeout.
+func (h *SessionHandler) checkBudgets(userID string) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), budgetCheckTimeout)
+		defer cancel()
+		h.budgetService.CheckBreaches(ctx, userID)
+	}()
+}
+
+// getOwnedRecipe loads a recipe and checks that it belongs to the user
+func (h *SessionHandler) getOwnedRecipe(c echo.Context, userID string, recipeID string) (*models.RecipeWithFlavors, error) {
+	recipe, err := h.recipeRepo.GetByID(c.Request().Context(), recipeID)
+	if err != nil || recipe.UserID != userID {
+		return nil, fmt.Errorf("unknown recipe: %s", recipeID)
+	}
+	return recipe, nil
+}
+
+// applyRecipe fills the flavors and mix name of a session request from a recipe.
+// Values given explicitly in the request take precedence. When the request has
+// a bowl weight, each flavor with a ratio gets its share of it as grams; the
+// ratios are relative to each other and do not need to add up to 100.
+func applyRecipe(req *models.CreateSessionRequest, recipe *models.RecipeWithFlavors) {
+	if req.Flavors == nil {
+		var ratioSum float64
+		for _, flavor := range recipe.Flavors {
+			if flavor.Ratio != nil && *flavor.Ratio > 0 {
+				ratioSum += *flavor.Ratio
+			}
+		}
+
+		flavors := make([]models.CreateFlavorRequest, len(recipe.Flavors))
+		for i, flavor := range recipe.Flavors {
+			flavorName := flavor.FlavorName
+			flavors[i] = models.CreateFlavorRequest{
+				FlavorName: &flavorName,
+				Brand:      flavor.Brand,
+			}
+			if req.BowlGrams != nil && ratioSum > 0 && flavor.Ratio != nil && *flavor.Ratio > 0 {
+				share := *flavor.Ratio / ratioSum
+				grams := math.Round(*req.BowlGrams*share*100) / 100
+				if grams > 0 {
+					flavors[i].Grams = &grams
+				}
+			}
+		}
+		req.Flavors = &flavors
+	}
+
+	if req.MixName == nil || *req.MixName == "" {
+		mixName := recipe.Name
+		req.MixName = &mixName
+	}
+}
+
+// sessionCurrency validates a requested currency code, defaulting to the user's default currency.
+// An invalid code returns errInvalidCurrency, any other error is a server-side failure.
+func (h *SessionHandler) sessionCurrency(userID string, requested *string) (string, error) {
+	if requested != nil && *requested != "" {
+		code := currency.Normalize(*requested)
+		if !currency.IsValidCode(code) {
+			return "", errInvalidCurrency
+		}
+		return code, nil
+	}
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return "", err
+	}
+	user, err := h.userRepo.GetByID(userUUID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get default currency: %w", err)
+	}
+	return user.DefaultCurrency, nil
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/toof-jp/shisha-log/backend/internal/currency"
+	"github.com/toof-jp/shisha-log/backend/internal/repository"
+)
+
+type SpendingHandler struct {
+	sessionRepo *repository.SessionRepository
+	userRepo    *repository.UserRepository
+	rateRepo    *repository.ExchangeRateRepository
+}
+
+func NewSpendingHandler(
+	sessionRepo *repository.SessionRepository,
+	userRepo *repository.UserRepository,
+	rateRepo *repository.ExchangeRateRepository,
+) *SpendingHandler {
+	return &SpendingHandler{
+		sessionRepo: sessionRepo,
+		userRepo:    userRepo,
+		rateRepo:    rateRepo,
+	}
+}
+
+// GetSpendingStats godoc
+// @Summary Get spending statistics
+// @Description Get totals and averages per day, week, month and year, spend per store and creator, cost per hour and the most/least expensive sessions. Amounts are converted into the target currency using the exchange rate of each session date.
+// @Tags statistics
+// @Produce json
+// @Security Bearer
+// @Param timezone query string false "Timezone (default UTC)"
+// @Param limit query int false "Number of most/least expensive sessions" default(5)
+// @Param currency query string false "Target currency (ISO 4217, default is the user's default currency)"
+// @Success 200 {object} models.SpendingStats "Spending statistics"
+// @Failure 400 {object} object{error=string} "Invalid parameters"
+// @Failure 500 {object} object{error=string} "Failed to get spending statistics"
+// @Router /spending/stats [get]
+func (h *SpendingHandler) GetSpendingStats(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	timezone := c.QueryParam("timezone")
+
+	// Default to UTC if no timezone provided
+	if timezone == "" {
+		timezone = "UTC"
+	}
+
+	limit := 5
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid limit parameter"})
+		}
+		limit = parsed
+	}
+
+	target := currency.Normalize(c.QueryParam("currency"))
+	if target != "" && !currency.IsValidCode(target) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid currency parameter"})
+	}
+
+	converter, err := loadAmountConverter(c.Request().Context(), h.userRepo, h.rateRepo, userID, target)
+	if err != nil {
+		log.Printf("GetSpendingStats error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get spending statistics"})
+	}
+
+	stats, err := h.sessionRepo.GetSpendingStats(c.Request().Context(), userID, timezone, limit, converter)
+	if err != nil {
+		log.Printf("GetSpendingStats error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get spending statistics"})
+	}
+
+	return c.JSON(http.StatusOK, stats)
+}
+
+// loadAmountConverter builds a converter into target using the local exchange rates.
+// An empty target uses the user's default currency.
+func loadAmountConverter(ctx context.Context, userRepo *repository.UserRepository, rateRepo *repository.ExchangeRateRepository, userID string, target string) (*currency.AmountConverter, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := userRepo.GetByID(userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	rates, err := rateRepo.GetAll(ctx, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	return currency.NewAmountConverter(currency.NewConverter(rates), user.DefaultCurrency, target), nil
+}
@@ -0,0 +1,300 @@
+package api
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/toof-jp/shisha-log/backend/internal/currency"
+	"github.com/toof-jp/shisha-log/backend/internal/report"
+	"github.com/toof-jp/shisha-log/backend/internal/repository"
+)
+
+// maxTimeSeriesDays limits the date range of a time series request
+const maxTimeSeriesDays = 3660
+
+type StatsHandler struct {
+	sessionRepo *repository.SessionRepository
+	userRepo    *repository.UserRepository
+	rateRepo    *repository.ExchangeRateRepository
+}
+
+func NewStatsHandler(
+	sessionRepo *repository.SessionRepository,
+	userRepo *repository.UserRepository,
+	rateRepo *repository.ExchangeRateRepository,
+) *StatsHandler {
+	return &StatsHandler{
+		sessionRepo: sessionRepo,
+		userRepo:    userRepo,
+		rateRepo:    rateRepo,
+	}
+}
+
+// GetTimeSeries godoc
+// @Summary Get session activity time series
+// @Description Get session counts, spend and distinct flavors per day, week or month between two dates. Buckets without sessions are returned with zero values.
+// @Tags statistics
+// @Produce json
+// @Security Bearer
+// @Param from query string false "Start date in YYYY-MM-DD format, inclusive (default one year before to)"
+// @Param to query string false "End date in YYYY-MM-DD format, inclusive (default today)"
+// @Param bucket query string false "Bucket size: day, week or month" default(day)
+// @Param timezone query string false "Timezone (default UTC)"
+// @Param currency query string false "Currency of the spend (ISO 4217, default is the user's default currency)"
+// @Success 200 {object} models.TimeSeries "Time series"
+// @Failure 400 {object} object{error=string} "Invalid parameters"
+// @Failure 500 {object} object{error=string} "Failed to get time series"
+// @Router /stats/timeseries [get]
+func (h *StatsHandler) GetTimeSeries(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	timezone := c.QueryParam("timezone")
+
+	// Default to UTC if no timezone provided
+	if timezone == "" {
+		timezone = "UTC"
+	}
+
+	bucket := c.QueryParam("bucket")
+	if bucket == "" {
+		bucket = repository.BucketDay
+	}
+	if bucket != repository.BucketDay && bucket != repository.BucketWeek && bucket != repository.BucketMonth {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid bucket parameter. Use day, week or month"})
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	to := time.Now().In(loc)
+	if toStr := c.QueryParam("to"); toStr != "" {
+		to, err = time.Parse("2006-01-02", toStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid to parameter. Use YYYY-MM-DD"})
+		}
+	}
+	from := to.AddDate(-1, 0, 1)
+	if fromStr := c.QueryParam("from"); fromStr != "" {
+		from, err = time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid from parameter. Use YYYY-MM-DD"})
+		}
+	}
+
+	fromStr, toStr := from.Format("2006-01-02"), to.Format("2006-01-02")
+	if fromStr > toStr {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "from must not be after to"})
+	}
+	if to.Sub(from) > maxTimeSeriesDays*24*time.Hour {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Date range is too long"})
+	}
+
+	target := currency.Normalize(c.QueryParam("currency"))
+	if target != "" && !currency.IsValidCode(target) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid currency parameter"})
+	}
+
+	converter, err := loadAmountConverter(c.Request().Context(), h.userRepo, h.rateRepo, userID, target)
+	if err != nil {
+		log.Printf("GetTimeSeries error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get time series"})
+	}
+
+	series, err := h.sessionRepo.GetTimeSeries(c.Request().Context(), userID, fromStr, toStr, bucket, timezone, converter)
+	if err != nil {
+		log.Printf("GetTimeSeries error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get time series"})
+	}
+
+	return c.JSON(http.StatusOK, series)
+}
+
+// GetHeatmap godoc
+// @Summary Get weekday/hour heatmap
+// @Description Get a 7x24 matrix of session counts per weekday (rows start on Monday) and hour of session_date in the timezone, with the most common day, hour and day/hour slots
+// @Tags statistics
+// @Produce json
+// @Security Bearer
+// @Param timezone query string false "Timezone (default UTC)"
+// @Param store query string false "Only count sessions at this store"
+// @Param flavor query string false "Only count sessions containing this flavor"
+// @Param limit query int false "Number of top slots" default(5)
+// @Success 200 {object} models.Heatmap "Heatmap"
+// @Failure 400 {object} object{error=string} "Invalid parameters"
+// @Failure 500 {object} object{error=string} "Failed to get heatmap"
+// @Router /stats/heatmap [get]
+func (h *StatsHandler) GetHeatmap(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	timezone := c.QueryParam("timezone")
+
+	// Default to UTC if no timezone provided
+	if timezone == "" {
+		timezone = "UTC"
+	}
+
+	limit := 5
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid limit parameter"})
+		}
+		limit = parsed
+	}
+
+	store := strings.TrimSpace(c.QueryParam("store"))
+	flavor := strings.TrimSpace(c.QueryParam("flavor"))
+
+	heatmap, err := h.sessionRepo.GetHeatmap(c.Request().Context(), userID, timezone, store, flavor, limit)
+	if err != nil {
+		log.Printf("GetHeatmap error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get heatmap"})
+	}
+
+	return c.JSON(http.StatusOK, heatmap)
+}
+
+// GetHabitStats godoc
+// @Summary Get habit statistics
+// @Description Get current and longest daily/weekly streaks, days between sessions, the longest break, the weekly session trend and the first/last session dates. Days are computed in the timezone like the calendar.
+// @Tags statistics
+// @Produce json
+// @Security Bearer
+// @Param timezone query string false "Timezone (default UTC)"
+// @Param weeks query int false "Number of weeks in the weekly trend" default(12)
+// @Success 200 {object} models.HabitStats "Habit statistics"
+// @Failure 400 {object} object{error=string} "Invalid parameters"
+// @Failure 500 {object} object{error=string} "Failed to get habit statistics"
+// @Router /stats/habits [get]
+func (h *StatsHandler) GetHabitStats(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	timezone := c.QueryParam("timezone")
+
+	// Default to UTC if no timezone provided
+	if timezone == "" {
+		timezone = "UTC"
+	}
+
+	weeks := 12
+	if weeksStr := c.QueryParam("weeks"); weeksStr != "" {
+		parsed, err := strconv.Atoi(weeksStr)
+		if err != nil || parsed < 1 || parsed > 520 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid weeks parameter"})
+		}
+		weeks = parsed
+	}
+
+	stats, err := h.sessionRepo.GetHabitStats(c.Request().Context(), userID, timezone, time.Now(), weeks)
+	if err != nil {
+		log.Printf("GetHabitStats error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get habit statistics"})
+	}
+
+	return c.JSON(http.StatusOK, stats)
+}
+
+// GetYearReport godoc
+// @Summary Get year-in-review report
+// @Description Get a summary of a year: sessions, spend, top flavors/stores/creators, new flavors and stores, the longest streak and the busiest month. Use format=html for a shareable page.
+// @Tags reports
+// @Produce json
+// @Produce html
+// @Security Bearer
+// @Param year path int true "Year"
+// @Param timezone query string false "Timezone (default UTC)"
+// @Param currency query string false "Currency of the spend (ISO 4217, default is the user's default currency)"
+// @Param limit query int false "Number of entries in the top lists" default(5)
+// @Param format query string false "Response format: json or html" default(json)
+// @Success 200 {object} models.YearReport "Year report"
+// @Failure 400 {object} object{error=string} "Invalid parameters"
+// @Failure 500 {object} object{error=string} "Failed to get year report"
+// @Router /reports/year/{year} [get]
+func (h *StatsHandler) GetYearReport(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	timezone := c.QueryParam("timezone")
+
+	// Default to UTC if no timezone provided
+	if timezone == "" {
+		timezone = "UTC"
+	}
+
+	year, err := strconv.Atoi(c.Param("year"))
+	if err != nil || year < 1900 || year > 9999 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid year parameter"})
+	}
+
+	limit := 5
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid limit parameter"})
+		}
+		limit = parsed
+	}
+
+	format := c.QueryParam("format")
+	if format != "" && format != "json" && format != "html" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid format parameter. Use json or html"})
+	}
+
+	target := currency.Normalize(c.QueryParam("currency"))
+	if target != "" && !currency.IsValidCode(target) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid currency parameter"})
+	}
+
+	converter, err := loadAmountConverter(c.Request().Context(), h.userRepo, h.rateRepo, userID, target)
+	if err != nil {
+		log.Printf("GetYearReport error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get year report"})
+	}
+
+	yearReport, err := h.sessionRepo.GetYearReport(c.Request().Context(), userID, year, timezone, limit, converter)
+	if err != nil {
+		log.Printf("GetYearReport error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get year report"})
+	}
+
+	if format == "html" {
+		var page bytes.Buffer
+		if err := report.RenderYearHTML(&page, yearReport); err != nil {
+			log.Printf("GetYearReport render error for user %s: %v", userID, err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to render year report"})
+		}
+		return c.HTMLBlob(http.StatusOK, page.Bytes())
+	}
+
+	return c.JSON(http.StatusOK, yearReport)
+}
+
+// GetDiscoveryStats godoc
+// @Summary Get discovery timeline
+// @Description Get when each flavor, brand, store and creator first appeared and the number of novel and repeat items per month
+// @Tags statistics
+// @Produce json
+// @Security Bearer
+// @Param timezone query string false "Timezone (default UTC)"
+// @Success 200 {object} models.DiscoveryStats "Discovery timeline"
+// @Failure 500 {object} object{error=string} "Failed to get discoveries"
+// @Router /stats/discoveries [get]
+func (h *StatsHandler) GetDiscoveryStats(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	timezone := c.QueryParam("timezone")
+
+	// Default to UTC if no timezone provided
+	if timezone == "" {
+		timezone = "UTC"
+	}
+
+	stats, err := h.sessionRepo.GetDiscoveryStats(c.Request().Context(), userID, timezone)
+	if err != nil {
+		log.Printf("GetDiscoveryStats error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get discoveries"})
+	}
+
+	return c.JSON(http.StatusOK, stats)
+}
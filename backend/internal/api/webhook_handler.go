@@ -0,0 +1,419 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/toof-jp/shisha-log/backend/internal/events"
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+	"github.com/toof-jp/shisha-log/backend/internal/repository"
+	"github.com/toof-jp/shisha-log/backend/internal/service"
+)
+
+// maxWebhooksPerUser limits the webhooks a user can register
+const maxWebhooksPerUser = 10
+
+type WebhookHandler struct {
+	repo           *repository.WebhookRepository
+	webhookService *service.WebhookService
+}
+
+func NewWebhookHandler(repo *repository.WebhookRepository, webhookService *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		repo:           repo,
+		webhookService: webhookService,
+	}
+}
+
+// CreateWebhook godoc
+// @Summary Create a webhook
+// @Description Register an endpoint that receives session.created, session.updated and session.deleted events. Deliveries are signed with the returned secret in the X-Shisha-Log-Signature header (t=<unix>,v1=<hex HMAC-SHA256 of "<t>.<body>">). The secret is only returned here and on rotation.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param webhook body models.CreateWebhookRequest true "Webhook data"
+// @Success 201 {object} models.WebhookWithSecret "Created webhook with its signing secret"
+// @Failure 400 {object} object{error=string} "Invalid request body"
+// @Failure 500 {object} object{error=string} "Failed to create webhook"
+// @Router /webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid user ID"})
+	}
+
+	var req models.CreateWebhookRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	if err := h.webhookService.ValidateURL(req.URL); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	eventTypes, err := normalizeWebhookEvents(req.Events)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	count, err := h.repo.CountByUserID(userUUID)
+	if err != nil {
+		log.Printf("CreateWebhook error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create webhook"})
+	}
+	if count >= maxWebhooksPerUser {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("A user can have at most %d webhooks", maxWebhooksPerUser)})
+	}
+
+	secret, err := h.webhookService.GenerateSecret()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create webhook"})
+	}
+
+	var description *string
+	if req.Description != nil && *req.Description != "" {
+		description = req.Description
+	}
+
+	created, err := h.repo.Create(&models.Webhook{
+		UserID:      userUUID,
+		URL:         req.URL,
+		Secret:      secret,
+		Events:      eventTypes,
+		Description: description,
+	})
+	if err != nil {
+		log.Printf("CreateWebhook error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create webhook"})
+	}
+
+	return c.JSON(http.StatusCreated, models.WebhookWithSecret{Webhook: *created, Secret: created.Secret})
+}
+
+// GetWebhooks godoc
+// @Summary Get user's webhooks
+// @Description Get all webhooks of the authenticated user
+// @Tags webhooks
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} object{webhooks=[]models.Webhook} "Webhook list"
+// @Failure 500 {object} object{error=string} "Failed to get webhooks"
+// @Router /webhooks [get]
+func (h *WebhookHandler) GetWebhooks(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid user ID"})
+	}
+
+	webhooks, err := h.repo.GetByUserID(userUUID)
+	if err != nil {
+		log.Printf("GetWebhooks error for user %s: %v", userID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get webhooks"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"webhooks": webhooks,
+	})
+}
+
+// GetWebhook godoc
+// @Summary Get a webhook by ID
+// @Description Get a specific webhook including its failure count
+// @Tags webhooks
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} models.Webhook "Webhook details"
+// @Failure 403 {object} object{error=string} "Access denied"
+// @Failure 404 {object} object{error=string} "Webhook not found"
+// @Failure 500 {object} object{error=string} "Internal server error"
+// @Router /webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(c echo.Context) error {
+	webhook, err := h.getOwnedWebhook(c)
+	if err != nil || webhook == nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, webhook)
+}
+
+// UpdateWebhook godoc
+// @Summary Update a webhook
+// @Description Update the URL, events or description of a webhook, or enable and disable it. Disabling a webhook marks its pending deliveries as failed; enabling it resets its failure count.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Webhook ID"
+// @Param webhook body models.UpdateWebhookRequest true "Updated webhook data"
+// @Success 200 {object} models.Webhook "Updated webhook"
+// @Failure 400 {object} object{error=string} "Invalid request body"
+// @Failure 403 {object} object{error=string} "Access denied"
+// @Failure 404 {object} object{error=string} "Webhook not found"
+// @Failure 500 {object} object{error=string} "Failed to update webhook"
+// @Router /webhooks/{id} [put]
+func (h *WebhookHandler) UpdateWebhook(c echo.Context) error {
+	webhook, err := h.getOwnedWebhook(c)
+	if err != nil || webhook == nil {
+		return err
+	}
+
+	var req models.UpdateWebhookRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	if req.URL != nil {
+		if err := h.webhookService.ValidateURL(*req.URL); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+	}
+	if req.Events != nil {
+		eventTypes, err := normalizeWebhookEvents(*req.Events)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		req.Events = &eventTypes
+	}
+
+	if err := h.repo.Update(webhook.ID, &req); err != nil {
+		c.Logger().Errorf("Failed to update webhook %s: %v", webhook.ID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update webhook"})
+	}
+
+	updated, err := h.repo.GetByID(webhook.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get updated webhook"})
+	}
+
+	return c.JSON(http.StatusOK, updated)
+}
+
+// DeleteWebhook godoc
+// @Summary Delete a webhook
+// @Description Delete a webhook and its delivery log
+// @Tags webhooks
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} object{message=string} "Webhook deleted successfully"
+// @Failure 403 {object} object{error=string} "Access denied"
+// @Failure 404 {object} object{error=string} "Webhook not found"
+// @Failure 500 {object} object{error=string} "Failed to delete webhook"
+// @Router /webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c echo.Context) error {
+	webhook, err := h.getOwnedWebhook(c)
+	if err != nil || webhook == nil {
+		return err
+	}
+
+	if err := h.repo.Delete(webhook.ID); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to delete webhook"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "Webhook deleted successfully"})
+}
+
+// RotateWebhookSecret godoc
+// @Summary Rotate the signing secret
+// @Description Replace the signing secret of a webhook. Deliveries made after the rotation are signed with the new secret.
+// @Tags webhooks
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} models.WebhookWithSecret "Webhook with its new signing secret"
+// @Failure 403 {object} object{error=string} "Access denied"
+// @Failure 404 {object} object{error=string} "Webhook not found"
+// @Failure 500 {object} object{error=string} "Failed to rotate secret"
+// @Router /webhooks/{id}/rotate-secret [post]
+func (h *WebhookHandler) RotateWebhookSecret(c echo.Context) error {
+	webhook, err := h.getOwnedWebhook(c)
+	if err != nil || webhook == nil {
+		return err
+	}
+
+	secret, err := h.webhookService.GenerateSecret()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to rotate secret"})
+	}
+	if err := h.repo.UpdateSecret(webhook.ID, secret); err != nil {
+		log.Printf("RotateWebhookSecret error for webhook %s: %v", webhook.ID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to rotate secret"})
+	}
+
+	return c.JSON(http.StatusOK, models.WebhookWithSecret{Webhook: *webhook, Secret: secret})
+}
+
+// PingWebhook godoc
+// @Summary Send a test event
+// @Description Send a signed ping event to the webhook and return the delivery with the response of the endpoint. A failed ping is retried like other deliveries.
+// @Tags webhooks
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} models.WebhookDelivery "Delivery after the first attempt"
+// @Failure 403 {object} object{error=string} "Access denied"
+// @Failure 404 {object} object{error=string} "Webhook not found"
+// @Failure 500 {object} object{error=string} "Failed to ping webhook"
+// @Router /webhooks/{id}/ping [post]
+func (h *WebhookHandler) PingWebhook(c echo.Context) error {
+	webhook, err := h.getOwnedWebhook(c)
+	if err != nil || webhook == nil {
+		return err
+	}
+
+	delivery, err := h.webhookService.Ping(c.Request().Context(), webhook)
+	if err != nil {
+		log.Printf("PingWebhook error for webhook %s: %v", webhook.ID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to ping webhook"})
+	}
+
+	return c.JSON(http.StatusOK, delivery)
+}
+
+// GetWebhookDeliveries godoc
+// @Summary Get the delivery log
+// @Description Get the deliveries of a webhook, newest first. Deliveries are kept for 30 days.
+// @Tags webhooks
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Webhook ID"
+// @Param limit query int false "Number of deliveries (default 20, max 100)"
+// @Param offset query int false "Offset for pagination"
+// @Success 200 {object} object{deliveries=[]models.WebhookDelivery,limit=int,offset=int} "Delivery log"
+// @Failure 403 {object} object{error=string} "Access denied"
+// @Failure 404 {object} object{error=string} "Webhook not found"
+// @Failure 500 {object} object{error=string} "Failed to get deliveries"
+// @Router /webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) GetWebhookDeliveries(c echo.Context) error {
+	webhook, err := h.getOwnedWebhook(c)
+	if err != nil || webhook == nil {
+		return err
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	offset, _ := strconv.Atoi(c.QueryParam("offset"))
+
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	deliveries, err := h.repo.GetDeliveriesByWebhookID(webhook.ID, limit, offset)
+	if err != nil {
+		log.Printf("GetWebhookDeliveries error for webhook %s: %v", webhook.ID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get deliveries"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"deliveries": deliveries,
+		"limit":      limit,
+		"offset":     offset,
+	})
+}
+
+// RedeliverWebhookDelivery godoc
+// @Summary Redeliver an event
+// @Description Send the payload of a previous delivery again. The new delivery keeps the event ID so receivers can deduplicate it.
+// @Tags webhooks
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Webhook ID"
+// @Param delivery_id path string true "Delivery ID"
+// @Success 200 {object} models.WebhookDelivery "New delivery after the first attempt"
+// @Failure 403 {object} object{error=string} "Access denied"
+// @Failure 404 {object} object{error=string} "Webhook or delivery not found"
+// @Failure 500 {object} object{error=string} "Failed to redeliver"
+// @Router /webhooks/{id}/deliveries/{delivery_id}/redeliver [post]
+func (h *WebhookHandler) RedeliverWebhookDelivery(c echo.Context) error {
+	webhook, err := h.getOwnedWebhook(c)
+	if err != nil || webhook == nil {
+		return err
+	}
+
+	deliveryID, err := uuid.Parse(c.Param("delivery_id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Delivery not found"})
+	}
+	previous, err := h.repo.GetDeliveryByID(deliveryID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && previous.WebhookID != webhook.ID) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Delivery not found"})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get delivery"})
+	}
+
+	delivery, err := h.webhookService.Redeliver(c.Request().Context(), webhook, previous)
+	if err != nil {
+		log.Printf("RedeliverWebhookDelivery error for delivery %s: %v", deliveryID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to redeliver"})
+	}
+
+	return c.JSON(http.StatusOK, delivery)
+}
+
+// getOwnedWebhook loads the webhook from the path and checks that it belongs to
+// the authenticated user. When it returns a nil webhook the response has already been written.
+func (h *WebhookHandler) getOwnedWebhook(c echo.Context) (*models.Webhook, error) {
+	userID := c.Get("user_id").(string)
+
+	webhookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return nil, c.JSON(http.StatusNotFound, map[string]string{"error": "Webhook not found"})
+	}
+
+	webhook, err := h.repo.GetByID(webhookID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, c.JSON(http.StatusNotFound, map[string]string{"error": "Webhook not found"})
+		}
+		return nil, c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get webhook"})
+	}
+
+	if webhook.UserID.String() != userID {
+		return nil, c.JSON(http.StatusForbidden, map[string]string{"error": "Access denied"})
+	}
+
+	return webhook, nil
+}
+
+// normalizeWebhookEvents validates and deduplicates event types, defaulting to all session events
+func normalizeWebhookEvents(eventTypes []string) ([]string, error) {
+	if len(eventTypes) == 0 {
+		return append([]string{}, events.SessionEvents...), nil
+	}
+
+	seen := make(map[string]bool)
+	var normalized []string
+	for _, eventType := range eventTypes {
+		valid := false
+		for _, known := range events.SessionEvents {
+			if eventType == known {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, fmt.Errorf("unknown event type %q", eventType)
+		}
+		if !seen[eventType] {
+			seen[eventType] = true
+			normalized = append(normalized, eventType)
+		}
+	}
+
+	return normalized, nil
+}
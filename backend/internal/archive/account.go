@@ -0,0 +1,241 @@
+// Package archive implements the versioned JSON archive of an account's data
+// used to export an account and import it into another environment.
+package archive
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+)
+
+const (
+	// Format identifies an account archive document
+	Format = "shisha-log.account-archive"
+	// Version is the archive format version written by this server. It is
+	// incremented on incompatible changes; added fields do not change it.
+	Version = 1
+)
+
+// Header holds everything in an archive except the sessions, which are
+// streamed after it
+type Header struct {
+	Format     string                     `json:"format"`
+	Version    int                        `json:"version"`
+	ExportedAt time.Time                  `json:"exported_at"`
+	User       models.User                `json:"user"` // The password hash is never included
+	Equipment  []models.Equipment         `json:"equipment"`
+	Inventory  []models.InventoryItem     `json:"inventory"`
+	Recipes    []models.RecipeWithFlavors `json:"recipes"`
+	Budgets    []models.Budget            `json:"budgets"`
+}
+
+// Writer streams an archive as a single JSON object. The header fields are
+// written first, then each session as an element of the sessions array, so a
+// large history never has to be held in memory.
+type Writer struct {
+	w        io.Writer
+	sessions int
+	begun    bool
+	closed   bool
+}
+
+// NewWriter returns a Writer writing the archive to w
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Begin writes the header and opens the sessions array
+func (aw *Writer) Begin(header Header) error {
+	if aw.begun {
+		return errors.New("archive header already written")
+	}
+	aw.begun = true
+
+	header.Format = Format
+	header.Version = Version
+	if header.Equipment == nil {
+		header.Equipment = []models.Equipment{}
+	}
+	if header.Inventory == nil {
+		header.Inventory = []models.InventoryItem{}
+	}
+	if header.Recipes == nil {
+		header.Recipes = []models.RecipeWithFlavors{}
+	}
+	if header.Budgets == nil {
+		header.Budgets = []models.Budget{}
+	}
+
+	data, err := json.Marshal(header)
+	if err != nil {
+		return err
+	}
+
+	// Reopen the marshalled object to append the sessions array
+	if _, err := aw.w.Write(data[:len(data)-1]); err != nil {
+		return err
+	}
+	_, err = io.WriteString(aw.w, `,"sessions":[`)
+	return err
+}
+
+// WriteSession appends a session with its flavors to the sessions array
+func (aw *Writer) WriteSession(session models.SessionWithFlavors) error {
+	if !aw.begun || aw.closed {
+		return errors.New("archive is not open for sessions")
+	}
+
+	if session.Flavors == nil {
+		session.Flavors = []models.SessionFlavor{}
+	}
+	if session.EquipmentIDs == nil {
+		session.EquipmentIDs = []string{}
+	}
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	if aw.sessions > 0 {
+		if _, err := io.WriteString(aw.w, ","); err != nil {
+			return err
+		}
+	}
+	if _, err := aw.w.Write(data); err != nil {
+		return err
+	}
+	aw.sessions++
+
+	return nil
+}
+
+// Close closes the sessions array and writes the session count, which lets
+// readers detect a truncated archive
+func (aw *Writer) Close() error {
+	if !aw.begun || aw.closed {
+		return errors.New("archive is not open")
+	}
+	aw.closed = true
+
+	_, err := fmt.Fprintf(aw.w, `],"session_count":%d}`, aw.sessions)
+	return err
+}
+
+// Read decodes an archive from r, calling fn for every session as it is
+// decoded so the sessions array is never held in memory by the reader. Object
+// keys may come in any order and unknown keys are ignored. It returns an error
+// for other formats, newer versions and truncated archives.
+func Read(r io.Reader, fn func(session models.SessionWithFlavors) error) (*Header, error) {
+	dec := json.NewDecoder(r)
+
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, err
+	}
+
+	var header Header
+	sessions := 0
+	sessionCount := -1
+
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := token.(string)
+
+		switch key {
+		case "format":
+			err = dec.Decode(&header.Format)
+		case "version":
+			err = dec.Decode(&header.Version)
+		case "exported_at":
+			err = dec.Decode(&header.ExportedAt)
+		case "user":
+			err = dec.Decode(&header.User)
+		case "equipment":
+			err = dec.Decode(&header.Equipment)
+		case "inventory":
+			err = dec.Decode(&header.Inventory)
+		case "recipes":
+			err = dec.Decode(&header.Recipes)
+		case "budgets":
+			err = dec.Decode(&header.Budgets)
+		case "session_count":
+			err = dec.Decode(&sessionCount)
+		case "sessions":
+			if err := checkHeader(&header, false); err != nil {
+				return nil, err
+			}
+			if err := expectDelim(dec, '['); err != nil {
+				return nil, err
+			}
+			for dec.More() {
+				var session models.SessionWithFlavors
+				if err := dec.Decode(&session); err != nil {
+					return nil, fmt.Errorf("invalid session %d: %w", sessions+1, err)
+				}
+				sessions++
+				if err := fn(session); err != nil {
+					return nil, err
+				}
+			}
+			err = expectDelim(dec, ']')
+		default:
+			var skip json.RawMessage
+			err = dec.Decode(&skip)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", key, err)
+		}
+	}
+
+	if err := expectDelim(dec, '}'); err != nil {
+		return nil, err
+	}
+	if err := checkHeader(&header, true); err != nil {
+		return nil, err
+	}
+	if sessionCount != sessions {
+		return nil, errors.New("archive is incomplete: session count does not match")
+	}
+
+	return &header, nil
+}
+
+// checkHeader validates the format and version of an archive. Before the end
+// of the document only fields that were already read are checked.
+func checkHeader(header *Header, complete bool) error {
+	if header.Format != "" && header.Format != Format {
+		return fmt.Errorf("unsupported archive format %q", header.Format)
+	}
+	if header.Version > Version {
+		return fmt.Errorf("unsupported archive version %d, this server supports up to %d", header.Version, Version)
+	}
+	if complete {
+		if header.Format == "" || header.Version < 1 {
+			return errors.New("not an account archive")
+		}
+		if header.User.ID == uuid.Nil {
+			return errors.New("archive has no user")
+		}
+	}
+	return nil
+}
+
+// expectDelim reads the next token and checks that it is the given delimiter
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("invalid archive: expected %v", delim)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AdminMiddleware protects admin endpoints with a shared admin token
+type AdminMiddleware struct {
+	adminToken string
+}
+
+func NewAdminMiddleware(adminToken string) *AdminMiddleware {
+	return &AdminMiddleware{
+		adminToken: adminToken,
+	}
+}
+
+// Authenticate requires the X-Admin-Token header to match the configured admin token.
+// Admin endpoints are disabled when no admin token is configured.
+func (m *AdminMiddleware) Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if m.adminToken == "" {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "Admin endpoints are disabled"})
+		}
+
+		token := c.Request().Header.Get("X-Admin-Token")
+		if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(m.adminToken)) != 1 {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Invalid admin token"})
+		}
+
+		return next(c)
+	}
+}
@@ -0,0 +1,24 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// DemoReadOnly rejects every request but GET and HEAD made with a demo token.
+// It must run after Authenticate.
+func DemoReadOnly(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		method := c.Request().Method
+		if method == http.MethodGet || method == http.MethodHead {
+			return next(c)
+		}
+
+		if demo, _ := c.Get("demo").(bool); demo {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "The demo account is read-only"})
+		}
+
+		return next(c)
+	}
+}
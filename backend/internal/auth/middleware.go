@@ -39,6 +39,7 @@ func (m *AuthMiddleware) Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
 		// Set user ID and username in context
 		c.Set("user_id", claims.UserID.String())
 		c.Set("username", claims.Username)
+		c.Set("demo", claims.Demo)
 
 		return next(c)
 	}
@@ -0,0 +1,252 @@
+// Package backup dumps the database tables into a portable archive and
+// restores them into an empty database.
+//
+// An archive is a gzip compressed tar file with one NDJSON file per table,
+// each line holding a row as a JSON object, and a manifest.json listing the
+// tables in restore order with their columns, row counts and SHA-256
+// checksums, the schema version and the application version.
+package backup
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/toof-jp/shisha-log/backend/internal/version"
+)
+
+const (
+	// Format identifies a backup archive
+	Format = "shisha-log.backup"
+	// FormatVersion is the archive layout version written by this binary
+	FormatVersion = 1
+	// manifestName is the name of the manifest in the archive
+	manifestName = "manifest.json"
+)
+
+// Tables lists the backed up tables in restore order, parents before the
+// tables referencing them. Tables missing from the database are skipped.
+var Tables = []string{
+	"users",
+	"password_reset_tokens",
+	"refresh_tokens",
+	"feed_tokens",
+	"equipment",
+	"tobacco_inventory",
+	"recipes",
+	"recipe_flavors",
+	"budgets",
+	"exchange_rates",
+	"shisha_sessions",
+	"session_flavors",
+	"session_equipment",
+	"webhooks",
+	"webhook_deliveries",
+}
+
+// Manifest describes the contents of an archive
+type Manifest struct {
+	Format        string          `json:"format"`
+	FormatVersion int             `json:"format_version"`
+	CreatedAt     time.Time       `json:"created_at"`
+	AppVersion    string          `json:"app_version"`
+	SchemaVersion string          `json:"schema_version"` // Latest applied migration, empty when unknown
+	Tables        []TableManifest `json:"tables"`
+}
+
+// TableManifest describes the dump of a table
+type TableManifest struct {
+	Name    string   `json:"name"`
+	File    string   `json:"file"`
+	Rows    int      `json:"rows"`
+	SHA256  string   `json:"sha256"`
+	Columns []Column `json:"columns"`
+}
+
+// Column is a table column and its SQL data type
+type Column struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Backup writes an archive of the tables to w. All tables are read in one
+// repeatable read transaction so the archive is a consistent snapshot.
+func Backup(ctx context.Context, db *sql.DB, w io.Writer) (*Manifest, error) {
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	manifest := &Manifest{
+		Format:        Format,
+		FormatVersion: FormatVersion,
+		CreatedAt:     time.Now().UTC(),
+		AppVersion:    version.Version,
+	}
+	if manifest.SchemaVersion, err = schemaVersion(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	for _, table := range Tables {
+		columns, err := tableColumns(ctx, tx, table)
+		if err != nil {
+			return nil, err
+		}
+		if len(columns) == 0 {
+			continue
+		}
+
+		entry, err := dumpTable(ctx, tx, tw, table)
+		if err != nil {
+			return nil, fmt.Errorf("dump %s: %w", table, err)
+		}
+		entry.Columns = columns
+		manifest.Tables = append(manifest.Tables, *entry)
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeEntry(tw, manifestName, manifest.CreatedAt, int64(len(data)), bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// dumpTable writes the rows of a table as NDJSON to the archive. The rows are
+// spooled to a temporary file because tar needs the size before the content.
+func dumpTable(ctx context.Context, tx *sql.Tx, tw *tar.Writer, table string) (*TableManifest, error) {
+	spool, err := os.CreateTemp("", "shisha-log-backup-*.ndjson")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf("SELECT row_to_json(t)::text FROM public.%s t", pq.QuoteIdentifier(table)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hash := sha256.New()
+	out := bufio.NewWriter(io.MultiWriter(spool, hash))
+	entry := &TableManifest{Name: table, File: table + ".ndjson"}
+
+	for rows.Next() {
+		var row string
+		if err := rows.Scan(&row); err != nil {
+			return nil, err
+		}
+		if _, err := out.WriteString(row + "\n"); err != nil {
+			return nil, err
+		}
+		entry.Rows++
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := out.Flush(); err != nil {
+		return nil, err
+	}
+	entry.SHA256 = hex.EncodeToString(hash.Sum(nil))
+
+	size, err := spool.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if err := writeEntry(tw, entry.File, time.Now(), size, spool); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+func writeEntry(tw *tar.Writer, name string, modTime time.Time, size int64, r io.Reader) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0o600,
+		Size:    size,
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := io.Copy(tw, r)
+	return err
+}
+
+// queryer is implemented by *sql.DB and *sql.Tx
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// schemaVersion returns the latest migration applied by the Supabase CLI, or
+// an empty string when migrations are not tracked in the database
+func schemaVersion(ctx context.Context, q queryer) (string, error) {
+	var tracked bool
+	err := q.QueryRowContext(ctx, "SELECT to_regclass('supabase_migrations.schema_migrations') IS NOT NULL").Scan(&tracked)
+	if err != nil || !tracked {
+		return "", err
+	}
+
+	var latest sql.NullString
+	err = q.QueryRowContext(ctx, "SELECT MAX(version) FROM supabase_migrations.schema_migrations").Scan(&latest)
+	if err != nil {
+		return "", err
+	}
+	return latest.String, nil
+}
+
+// tableColumns returns the columns of a public table in order, or none when
+// the table does not exist
+func tableColumns(ctx context.Context, q queryer, table string) ([]Column, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT column_name, data_type
+		FROM information_schema.columns
+		WHERE table_schema = 'public' AND table_name = $1
+		ORDER BY ordinal_position
+	`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []Column
+	for rows.Next() {
+		var column Column
+		if err := rows.Scan(&column.Name, &column.Type); err != nil {
+			return nil, err
+		}
+		columns = append(columns, column)
+	}
+	return columns, rows.Err()
+}
@@ -0,0 +1,283 @@
+package backup
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// restoreBatchSize is the number of rows inserted per statement
+const restoreBatchSize = 500
+
+// RestoreOptions controls the checks of Restore
+type RestoreOptions struct {
+	// AllowSchemaMismatch restores even when the schema version of the archive
+	// differs from the database, as long as every archived column exists with
+	// the same type
+	AllowSchemaMismatch bool
+}
+
+// fileSummary is what the verification pass found for an archive file
+type fileSummary struct {
+	rows   int
+	sha256 string
+}
+
+// Restore verifies an archive and loads it into an empty database in a single
+// transaction. The archive is read twice: first to check the manifest, the
+// checksums and row counts, then to insert the rows.
+func Restore(ctx context.Context, db *sql.DB, r io.ReadSeeker, options RestoreOptions) (*Manifest, error) {
+	manifest, err := Verify(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDatabase(ctx, db, manifest, options); err != nil {
+		return nil, err
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	tables := make(map[string]TableManifest, len(manifest.Tables))
+	for _, table := range manifest.Tables {
+		tables[table.File] = table
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		table, ok := tables[header.Name]
+		if !ok {
+			continue
+		}
+		if err := loadTable(ctx, tx, table, tr); err != nil {
+			return nil, fmt.Errorf("restore %s: %w", table.Name, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// Verify reads the whole archive and checks the manifest, and the checksum and
+// row count of every table file. It returns the manifest.
+func Verify(r io.Reader) (*Manifest, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a backup archive: %w", err)
+	}
+	defer gz.Close()
+
+	var manifest *Manifest
+	var order []string
+	files := make(map[string]fileSummary)
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("corrupt archive: %w", err)
+		}
+
+		if header.Name == manifestName {
+			manifest = &Manifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, fmt.Errorf("invalid manifest: %w", err)
+			}
+			continue
+		}
+
+		hash := sha256.New()
+		lines, err := countLines(io.TeeReader(tr, hash))
+		if err != nil {
+			return nil, fmt.Errorf("corrupt archive: %w", err)
+		}
+		files[header.Name] = fileSummary{rows: lines, sha256: hex.EncodeToString(hash.Sum(nil))}
+		order = append(order, header.Name)
+	}
+
+	if manifest == nil {
+		return nil, errors.New("not a backup archive: manifest missing")
+	}
+	if manifest.Format != Format {
+		return nil, fmt.Errorf("not a backup archive: format %q", manifest.Format)
+	}
+	if manifest.FormatVersion > FormatVersion {
+		return nil, fmt.Errorf("archive format version %d is newer than the supported version %d", manifest.FormatVersion, FormatVersion)
+	}
+
+	if len(order) != len(manifest.Tables) {
+		return nil, fmt.Errorf("archive has %d table files, manifest lists %d", len(order), len(manifest.Tables))
+	}
+	for i, table := range manifest.Tables {
+		if order[i] != table.File {
+			return nil, fmt.Errorf("table file %s is out of order", table.File)
+		}
+		file := files[table.File]
+		if file.sha256 != table.SHA256 {
+			return nil, fmt.Errorf("checksum mismatch for %s", table.File)
+		}
+		if file.rows != table.Rows {
+			return nil, fmt.Errorf("%s has %d rows, manifest lists %d", table.File, file.rows, table.Rows)
+		}
+	}
+
+	return manifest, nil
+}
+
+// checkDatabase checks that the database schema matches the archive and that
+// the tables to restore are empty
+func checkDatabase(ctx context.Context, db *sql.DB, manifest *Manifest, options RestoreOptions) error {
+	current, err := schemaVersion(ctx, db)
+	if err != nil {
+		return err
+	}
+	if manifest.SchemaVersion != current && !options.AllowSchemaMismatch {
+		return fmt.Errorf("schema version mismatch: archive %q, database %q; apply the same migrations first", manifest.SchemaVersion, current)
+	}
+
+	for _, table := range manifest.Tables {
+		columns, err := tableColumns(ctx, db, table.Name)
+		if err != nil {
+			return err
+		}
+		if len(columns) == 0 {
+			return fmt.Errorf("table %s does not exist", table.Name)
+		}
+
+		types := make(map[string]string, len(columns))
+		for _, column := range columns {
+			types[column.Name] = column.Type
+		}
+		for _, column := range table.Columns {
+			current, ok := types[column.Name]
+			if !ok {
+				return fmt.Errorf("column %s.%s does not exist", table.Name, column.Name)
+			}
+			if current != column.Type {
+				return fmt.Errorf("column %s.%s is %s, archive has %s", table.Name, column.Name, current, column.Type)
+			}
+		}
+
+		var exists bool
+		query := fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM public.%s)", pq.QuoteIdentifier(table.Name))
+		if err := db.QueryRowContext(ctx, query).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("table %s is not empty, restore needs an empty database", table.Name)
+		}
+	}
+
+	return nil
+}
+
+// loadTable inserts the NDJSON rows of a table in batches. Columns are listed
+// explicitly so columns added after the backup get their defaults.
+func loadTable(ctx context.Context, tx *sql.Tx, table TableManifest, r io.Reader) error {
+	columns := make([]string, len(table.Columns))
+	for i, column := range table.Columns {
+		columns[i] = pq.QuoteIdentifier(column.Name)
+	}
+	name := "public." + pq.QuoteIdentifier(table.Name)
+	query := fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM json_populate_recordset(NULL::%s, $1::json)",
+		name, strings.Join(columns, ", "), strings.Join(columns, ", "), name)
+
+	var batch bytes.Buffer
+	count := 0
+	flush := func() error {
+		if count == 0 {
+			return nil
+		}
+		batch.WriteByte(']')
+		if _, err := tx.ExecContext(ctx, query, batch.String()); err != nil {
+			return err
+		}
+		batch.Reset()
+		count = 0
+		return nil
+	}
+
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		line = bytes.TrimSpace(line)
+		if len(line) > 0 {
+			if count == 0 {
+				batch.WriteByte('[')
+			} else {
+				batch.WriteByte(',')
+			}
+			batch.Write(line)
+			count++
+			if count == restoreBatchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return flush()
+}
+
+// countLines counts the non-empty lines of r
+func countLines(r io.Reader) (int, error) {
+	reader := bufio.NewReader(r)
+	count := 0
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			count++
+		}
+		if err == io.EOF {
+			return count, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+}
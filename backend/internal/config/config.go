@@ -18,6 +18,11 @@ type Config struct {
 	AllowedOrigins      []string
 	DatabaseURL         string
 	TokenDuration       string
+	AdminToken          string
+	PublicAPIURL        string
+	DemoUserID          string
+	DemoTokenDuration   string
+	DemoResetInterval   string
 }
 
 func LoadConfig() (*Config, error) {
@@ -38,6 +43,11 @@ func LoadConfig() (*Config, error) {
 		JWTSecret:           getEnv("JWT_SECRET", ""),
 		DatabaseURL:         getEnv("DATABASE_URL", ""),
 		TokenDuration:       getEnv("TOKEN_DURATION", "24h"),
+		AdminToken:          getEnv("ADMIN_TOKEN", ""),
+		PublicAPIURL:        strings.TrimSuffix(getEnv("PUBLIC_API_URL", ""), "/"),
+		DemoUserID:          getEnv("DEMO_USER_ID", ""),
+		DemoTokenDuration:   getEnv("DEMO_TOKEN_DURATION", "1h"),
+		DemoResetInterval:   getEnv("DEMO_RESET_INTERVAL", "6h"),
 	}
 
 	allowedOrigins := getEnv("ALLOWED_ORIGINS", "http://localhost:3000")
@@ -0,0 +1,53 @@
+package currency
+
+import (
+	"time"
+
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+)
+
+// AmountConverter converts session amounts into a target currency.
+// Sessions without a currency are assumed to be in the user's default currency.
+type AmountConverter struct {
+	converter       *Converter
+	defaultCurrency string
+	target          string
+}
+
+func NewAmountConverter(converter *Converter, defaultCurrency, target string) *AmountConverter {
+	if target == "" {
+		target = defaultCurrency
+	}
+	return &AmountConverter{
+		converter:       converter,
+		defaultCurrency: defaultCurrency,
+		target:          target,
+	}
+}
+
+// Target returns the currency amounts are converted into
+func (a *AmountConverter) Target() string {
+	return a.target
+}
+
+// SessionCurrency returns the currency of a session amount
+func (a *AmountConverter) SessionCurrency(session *models.ShishaSession) string {
+	if session.Currency != nil && *session.Currency != "" {
+		return *session.Currency
+	}
+	return a.defaultCurrency
+}
+
+// SessionAmount returns the session amount in the target currency using the rate on date.
+// It returns false when the session has no amount or no rate is available.
+func (a *AmountConverter) SessionAmount(session *models.ShishaSession, date time.Time) (float64, bool) {
+	if session.Amount == nil {
+		return 0, false
+	}
+
+	amount, err := a.converter.Convert(float64(*session.Amount), a.SessionCurrency(session), a.target, date)
+	if err != nil {
+		return 0, false
+	}
+	return amount, true
+}
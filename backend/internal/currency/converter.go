@@ -0,0 +1,136 @@
+package currency
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+)
+
+type pair struct {
+	base  string
+	quote string
+}
+
+type datedRate struct {
+	date time.Time
+	rate float64
+}
+
+// Converter converts amounts between currencies using the exchange rate of a given date.
+// The rate used is the latest one on or before the date; dates before the first known
+// rate cannot be converted.
+type Converter struct {
+	rates  map[pair][]datedRate // Sorted by date ascending
+	pivots []string             // Cross rate currencies in the order they are tried
+}
+
+// NewConverter builds a converter from a locally maintained exchange rate table
+func NewConverter(rates []models.ExchangeRate) *Converter {
+	c := &Converter{
+		rates: make(map[pair][]datedRate),
+	}
+	baseCounts := make(map[string]int)
+
+	for _, rate := range rates {
+		date, err := time.Parse("2006-01-02", rate.RateDate)
+		if err != nil || rate.Rate <= 0 {
+			continue
+		}
+		key := pair{base: rate.BaseCurrency, quote: rate.QuoteCurrency}
+		c.rates[key] = append(c.rates[key], datedRate{date: date, rate: rate.Rate})
+		baseCounts[rate.BaseCurrency]++
+		if _, ok := baseCounts[rate.QuoteCurrency]; !ok {
+			baseCounts[rate.QuoteCurrency] = 0
+		}
+	}
+
+	for key := range c.rates {
+		sort.Slice(c.rates[key], func(i, j int) bool {
+			return c.rates[key][i].date.Before(c.rates[key][j].date)
+		})
+	}
+
+	// Rate sources quote against one base currency (EUR for the ECB), so the most
+	// used base is the natural pivot. The order is fixed so that the same conversion
+	// always gives the same amount.
+	for code := range baseCounts {
+		c.pivots = append(c.pivots, code)
+	}
+	sort.Slice(c.pivots, func(i, j int) bool {
+		if baseCounts[c.pivots[i]] != baseCounts[c.pivots[j]] {
+			return baseCounts[c.pivots[i]] > baseCounts[c.pivots[j]]
+		}
+		return c.pivots[i] < c.pivots[j]
+	})
+
+	return c
+}
+
+// Convert converts amount from one currency to another using the rate on date
+func (c *Converter) Convert(amount float64, from, to string, date time.Time) (float64, error) {
+	rate, ok := c.Rate(from, to, date)
+	if !ok {
+		return 0, fmt.Errorf("no exchange rate from %s to %s", from, to)
+	}
+	return amount * rate, nil
+}
+
+// Rate returns the rate from one currency to another on date, trying the direct rate,
+// the inverse rate and finally a cross rate through the first pivot currency that has both rates
+func (c *Converter) Rate(from, to string, date time.Time) (float64, bool) {
+	if from == to {
+		return 1, true
+	}
+
+	if rate, ok := c.pairRate(from, to, date); ok {
+		return rate, true
+	}
+
+	for _, pivot := range c.pivots {
+		if pivot == from || pivot == to {
+			continue
+		}
+		first, ok := c.pairRate(from, pivot, date)
+		if !ok {
+			continue
+		}
+		second, ok := c.pairRate(pivot, to, date)
+		if !ok {
+			continue
+		}
+		return first * second, true
+	}
+
+	return 0, false
+}
+
+// pairRate returns the direct or inverse rate between two currencies
+func (c *Converter) pairRate(from, to string, date time.Time) (float64, bool) {
+	if rate, ok := c.lookup(pair{base: from, quote: to}, date); ok {
+		return rate, true
+	}
+	if rate, ok := c.lookup(pair{base: to, quote: from}, date); ok {
+		return 1 / rate, true
+	}
+	return 0, false
+}
+
+func (c *Converter) lookup(key pair, date time.Time) (float64, bool) {
+	rates := c.rates[key]
+	if len(rates) == 0 {
+		return 0, false
+	}
+
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+
+	// Find the first rate after the date; the one before it is the latest on or before the date
+	i := sort.Search(len(rates), func(i int) bool {
+		return rates[i].date.After(day)
+	})
+	if i == 0 {
+		return 0, false
+	}
+	return rates[i-1].rate, true
+}
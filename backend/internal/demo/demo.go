@@ -0,0 +1,107 @@
+// Package demo keeps the read-only demo account seeded with generated data
+package demo
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+	"github.com/toof-jp/shisha-log/backend/internal/seed"
+)
+
+const (
+	demoSessions = 120
+	demoDays     = 180
+	demoSeed     = 1
+)
+
+// Account is the shared demo user. Its sessions are regenerated on every reset
+// so changes never accumulate and the data always ends today.
+type Account struct {
+	seeder   *seed.Seeder
+	userID   string
+	location *time.Location
+
+	mu   sync.RWMutex
+	user *models.User
+}
+
+func NewAccount(seeder *seed.Seeder, userID string) *Account {
+	location, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		location = time.UTC
+	}
+
+	return &Account{
+		seeder:   seeder,
+		userID:   userID,
+		location: location,
+	}
+}
+
+// User returns the demo user, or nil until the first reset succeeded
+func (a *Account) User() *models.User {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.user
+}
+
+// Reset creates the demo user if needed and replaces its sessions
+func (a *Account) Reset(ctx context.Context) error {
+	password, err := randomPassword()
+	if err != nil {
+		return err
+	}
+
+	// The password is only used when the user is created. Nobody knows it,
+	// so the demo user can only be accessed through demo tokens.
+	user, err := a.seeder.SeedUser(ctx, a.userID, password, seed.Options{
+		Sessions: demoSessions,
+		Days:     demoDays,
+		End:      time.Now().In(a.location),
+		Seed:     demoSeed,
+		Location: a.location,
+	}, true)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	a.user = user
+	a.mu.Unlock()
+
+	return nil
+}
+
+// Run resets the demo data immediately and then every interval until ctx is done
+func (a *Account) Run(ctx context.Context, interval time.Duration) {
+	if err := a.Reset(ctx); err != nil {
+		log.Printf("Failed to reset demo account: %v", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := a.Reset(ctx); err != nil {
+				log.Printf("Failed to reset demo account: %v", err)
+			}
+		}
+	}
+}
+
+func randomPassword() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.EncodeToString(b), nil
+}
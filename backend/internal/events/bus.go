@@ -0,0 +1,72 @@
+package events
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// Event types
+const (
+	BudgetBreached = "budget.breached"
+	SessionCreated = "session.created"
+	SessionUpdated = "session.updated"
+	SessionDeleted = "session.deleted"
+)
+
+// SessionEvents are the event types published for session changes
+var SessionEvents = []string{SessionCreated, SessionUpdated, SessionDeleted}
+
+// Event is something that happened to a user's data
+type Event struct {
+	Type       string      `json:"type"`
+	UserID     string      `json:"user_id"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+// Handler handles a published event
+type Handler func(event Event)
+
+// Bus is an in-process publish/subscribe event bus
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+func NewBus() *Bus {
+	return &Bus{
+		handlers: make(map[string][]Handler),
+	}
+}
+
+// Subscribe registers a handler for an event type
+func (b *Bus) Subscribe(eventType string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+}
+
+// Publish delivers an event to the handlers subscribed to its type.
+// Handlers run in their own goroutine so slow handlers do not block the publisher.
+func (b *Bus) Publish(event Event) {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	b.mu.RLock()
+	handlers := b.handlers[event.Type]
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		go func(handler Handler) {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("Event handler for %s panicked: %v", event.Type, r)
+				}
+			}()
+			handler(event)
+		}(handler)
+	}
+}
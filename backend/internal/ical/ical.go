@@ -0,0 +1,129 @@
+// Package ical writes iCalendar (RFC 5545) documents
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxLineOctets is the longest content line allowed before folding
+const maxLineOctets = 75
+
+// utcLayout formats a UTC date-time value
+const utcLayout = "20060102T150405Z"
+
+// Event is a VEVENT component
+type Event struct {
+	UID          string
+	Created      time.Time
+	LastModified time.Time
+	Start        time.Time
+	End          time.Time // Zero for an event without a known end
+	Summary      string
+	Location     string
+	Description  string
+	Categories   []string
+}
+
+// Writer streams a VCALENDAR with one VEVENT per WriteEvent call
+type Writer struct {
+	w *bufio.Writer
+}
+
+// NewWriter writes the calendar header to w and returns a Writer for its events
+func NewWriter(w io.Writer, productID, name string) (*Writer, error) {
+	cw := &Writer{w: bufio.NewWriter(w)}
+
+	cw.line("BEGIN", "VCALENDAR")
+	cw.line("VERSION", "2.0")
+	cw.line("PRODID", productID)
+	cw.line("CALSCALE", "GREGORIAN")
+	cw.line("METHOD", "PUBLISH")
+	if name != "" {
+		cw.line("X-WR-CALNAME", escapeText(name))
+	}
+
+	return cw, cw.w.Flush()
+}
+
+// WriteEvent writes an event
+func (cw *Writer) WriteEvent(event Event) error {
+	stamp := event.LastModified
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+
+	cw.line("BEGIN", "VEVENT")
+	cw.line("UID", escapeText(event.UID))
+	cw.line("DTSTAMP", formatTime(stamp))
+	if !event.Created.IsZero() {
+		cw.line("CREATED", formatTime(event.Created))
+	}
+	if !event.LastModified.IsZero() {
+		cw.line("LAST-MODIFIED", formatTime(event.LastModified))
+	}
+	cw.line("DTSTART", formatTime(event.Start))
+	if !event.End.IsZero() && event.End.After(event.Start) {
+		cw.line("DTEND", formatTime(event.End))
+	}
+	cw.line("SUMMARY", escapeText(event.Summary))
+	if event.Location != "" {
+		cw.line("LOCATION", escapeText(event.Location))
+	}
+	if event.Description != "" {
+		cw.line("DESCRIPTION", escapeText(event.Description))
+	}
+	if len(event.Categories) > 0 {
+		categories := make([]string, len(event.Categories))
+		for i, category := range event.Categories {
+			categories[i] = escapeText(category)
+		}
+		cw.line("CATEGORIES", strings.Join(categories, ","))
+	}
+	cw.line("END", "VEVENT")
+
+	return cw.w.Flush()
+}
+
+// Close writes the end of the calendar
+func (cw *Writer) Close() error {
+	cw.line("END", "VCALENDAR")
+	return cw.w.Flush()
+}
+
+// line writes a content line, folding it at 75 octets without splitting UTF-8 characters
+func (cw *Writer) line(name, value string) {
+	content := name + ":" + value
+
+	octets := 0
+	for len(content) > 0 {
+		r, size := utf8.DecodeRuneInString(content)
+		if octets+size > maxLineOctets {
+			cw.w.WriteString("\r\n ")
+			octets = 1 // The leading space counts towards the folded line
+		}
+		cw.w.WriteRune(r)
+		octets += size
+		content = content[size:]
+	}
+	cw.w.WriteString("\r\n")
+}
+
+// escapeText escapes a TEXT value
+func escapeText(value string) string {
+	value = strings.ReplaceAll(value, "\r\n", "\n")
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(value)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(utcLayout)
+}
@@ -0,0 +1,288 @@
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/toof-jp/shisha-log/backend/internal/currency"
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+)
+
+// Session fields that can be mapped to CSV columns. Flavors can be mapped either as a
+// single column listing several flavors or as numbered flavor_N and brand_N columns.
+const (
+	FieldSessionDate     = "session_date"
+	FieldStoreName       = "store_name"
+	FieldMixName         = "mix_name"
+	FieldCreator         = "creator"
+	FieldNotes           = "notes"
+	FieldOrderDetails    = "order_details"
+	FieldAmount          = "amount"
+	FieldCurrency        = "currency"
+	FieldDurationMinutes = "duration_minutes"
+	FieldRating          = "rating"
+	FieldFlavors         = "flavors"
+)
+
+var simpleFields = []string{
+	FieldSessionDate, FieldStoreName, FieldMixName, FieldCreator, FieldNotes, FieldOrderDetails,
+	FieldAmount, FieldCurrency, FieldDurationMinutes, FieldRating, FieldFlavors,
+}
+
+var numberedField = regexp.MustCompile(`^(flavor|brand)_([1-9][0-9]?)$`)
+
+// flavorSeparators split a single flavors column into flavors
+var flavorSeparators = regexp.MustCompile(`\s*[/,、・+＋]\s*`)
+
+// dateLayouts are tried in order when no date format is given.
+// Month-first dates are assumed for slashed dates ending in the year.
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/1/2 15:04:05",
+	"2006/1/2 15:04",
+	"2006/1/2",
+	"2006.1.2",
+	"2006年1月2日 15:04",
+	"2006年1月2日 15時04分",
+	"2006年1月2日",
+	"1/2/2006 15:04",
+	"1/2/2006",
+}
+
+// IsValidField reports whether field can be mapped to a column
+func IsValidField(field string) bool {
+	for _, name := range simpleFields {
+		if field == name {
+			return true
+		}
+	}
+	return numberedField.MatchString(field)
+}
+
+// File is a parsed CSV file
+type File struct {
+	Header  []string
+	Records [][]string
+}
+
+// ReadCSV reads a CSV file with a header row. A UTF-8 byte order mark is ignored.
+func ReadCSV(r io.Reader) (*File, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: missing header")
+	}
+	for i, name := range header {
+		header[i] = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+	}
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %v", err)
+	}
+
+	return &File{Header: header, Records: records}, nil
+}
+
+// AutoMapping maps every column whose name is a session field to that field
+func AutoMapping(header []string) map[string]string {
+	mapping := make(map[string]string)
+	for _, name := range header {
+		field := strings.ToLower(name)
+		if IsValidField(field) {
+			mapping[field] = name
+		}
+	}
+	return mapping
+}
+
+// Parser converts CSV records to sessions using a column mapping
+type Parser struct {
+	columns    map[string]int // Field to column index
+	location   *time.Location
+	dateLayout string
+	flavorMax  int
+}
+
+// NewParser validates the mapping against the header. dateLayout is an optional Go
+// time layout used instead of the built-in formats.
+func NewParser(header []string, mapping map[string]string, location *time.Location, dateLayout string) (*Parser, error) {
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[name] = i
+	}
+
+	p := &Parser{
+		columns:    make(map[string]int),
+		location:   location,
+		dateLayout: dateLayout,
+	}
+	for field, column := range mapping {
+		if !IsValidField(field) {
+			return nil, fmt.Errorf("unknown field %q in mapping", field)
+		}
+		i, ok := index[column]
+		if !ok {
+			return nil, fmt.Errorf("column %q mapped to %s is not in the file", column, field)
+		}
+		p.columns[field] = i
+
+		if match := numberedField.FindStringSubmatch(field); match != nil {
+			n, _ := strconv.Atoi(match[2])
+			if n > p.flavorMax {
+				p.flavorMax = n
+			}
+		}
+	}
+
+	if _, ok := p.columns[FieldSessionDate]; !ok {
+		return nil, fmt.Errorf("session_date must be mapped")
+	}
+
+	return p, nil
+}
+
+// Parse converts a record to a session, collecting every validation error
+func (p *Parser) Parse(record []string) (*models.ImportedSession, []string) {
+	var errs []string
+	session := &models.ImportedSession{Flavors: []models.CreateFlavorRequest{}}
+
+	date, err := p.parseDate(p.value(record, FieldSessionDate))
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+	session.SessionDate = date
+
+	session.StoreName = p.text(record, FieldStoreName)
+	session.MixName = p.text(record, FieldMixName)
+	session.Creator = p.text(record, FieldCreator)
+	session.Notes = p.text(record, FieldNotes)
+	session.OrderDetails = p.text(record, FieldOrderDetails)
+
+	if value := p.value(record, FieldAmount); value != "" {
+		amount, err := parseAmount(value)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("invalid amount %q", value))
+		} else {
+			session.Amount = &amount
+		}
+	}
+	if value := p.value(record, FieldCurrency); value != "" {
+		code := currency.Normalize(value)
+		if !currency.IsValidCode(code) {
+			errs = append(errs, fmt.Sprintf("invalid currency %q", value))
+		} else {
+			session.Currency = &code
+		}
+	}
+	if value := p.value(record, FieldDurationMinutes); value != "" {
+		duration, err := strconv.Atoi(value)
+		if err != nil || duration <= 0 {
+			errs = append(errs, fmt.Sprintf("invalid duration %q", value))
+		} else {
+			session.DurationMinutes = &duration
+		}
+	}
+	if value := p.value(record, FieldRating); value != "" {
+		rating, err := strconv.Atoi(value)
+		if err != nil || rating < 1 || rating > 5 {
+			errs = append(errs, fmt.Sprintf("invalid rating %q, use 1 to 5", value))
+		} else {
+			session.Rating = &rating
+		}
+	}
+
+	if value := p.value(record, FieldFlavors); value != "" {
+		for _, name := range flavorSeparators.Split(value, -1) {
+			if name != "" {
+				flavorName := name
+				session.Flavors = append(session.Flavors, models.CreateFlavorRequest{FlavorName: &flavorName})
+			}
+		}
+	}
+	for n := 1; n <= p.flavorMax; n++ {
+		name := p.text(record, fmt.Sprintf("flavor_%d", n))
+		if name == nil {
+			continue
+		}
+		session.Flavors = append(session.Flavors, models.CreateFlavorRequest{
+			FlavorName: name,
+			Brand:      p.text(record, fmt.Sprintf("brand_%d", n)),
+		})
+	}
+
+	return session, errs
+}
+
+func (p *Parser) value(record []string, field string) string {
+	i, ok := p.columns[field]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+func (p *Parser) text(record []string, field string) *string {
+	value := p.value(record, field)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// parseDate parses a date in the parser's location. Dates without a time are placed at noon
+// so they stay on the same day when viewed from nearby timezones.
+func (p *Parser) parseDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, fmt.Errorf("session_date is required")
+	}
+
+	layouts := dateLayouts
+	if p.dateLayout != "" {
+		layouts = []string{p.dateLayout}
+	}
+
+	for _, layout := range layouts {
+		t, err := time.ParseInLocation(layout, value, p.location)
+		if err != nil {
+			continue
+		}
+		if !hasClock(layout) {
+			t = t.Add(12 * time.Hour)
+		}
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid session_date %q", value)
+}
+
+// hasClock reports whether a time layout contains an hour or minute element.
+// "3" and "4" also match the zero-padded "03" and "04"; no date element uses these digits.
+func hasClock(layout string) bool {
+	return strings.Contains(layout, "15") || strings.Contains(layout, "3") || strings.Contains(layout, "4")
+}
+
+// parseAmount parses an integer amount, ignoring currency symbols and thousands separators
+func parseAmount(value string) (int, error) {
+	cleaned := strings.NewReplacer("¥", "", "￥", "", "円", "", "$", "", "€", "", ",", "", " ", "").Replace(value)
+	amount, err := strconv.Atoi(cleaned)
+	if err != nil {
+		return 0, err
+	}
+	if amount < 0 {
+		return 0, fmt.Errorf("negative amount")
+	}
+	return amount, nil
+}
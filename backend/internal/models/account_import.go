@@ -0,0 +1,55 @@
+package models
+
+// Account import modes
+const (
+	AccountImportMerge   = "merge"   // Add archive data next to the existing data
+	AccountImportReplace = "replace" // Delete the existing data before importing
+)
+
+// IsValidAccountImportMode reports whether mode is a known account import mode
+func IsValidAccountImportMode(mode string) bool {
+	return mode == AccountImportMerge || mode == AccountImportReplace
+}
+
+// AccountImportCounts counts entities by kind
+type AccountImportCounts struct {
+	Equipment int `json:"equipment"`
+	Inventory int `json:"inventory"`
+	Recipes   int `json:"recipes"`
+	Budgets   int `json:"budgets"`
+	Sessions  int `json:"sessions"`
+}
+
+// AccountImportConflict describes an archive entity that was not imported
+// because it clashes with existing data
+type AccountImportConflict struct {
+	Kind       string `json:"kind"`        // equipment, inventory, recipe, budget or session
+	ArchiveID  string `json:"archive_id"`  // ID in the archive
+	ExistingID string `json:"existing_id"` // ID of the existing entity that was kept and is used in its place
+	Reason     string `json:"reason"`
+}
+
+// AccountImportResult reports what an account import did
+type AccountImportResult struct {
+	Mode           string                  `json:"mode"`
+	ArchiveVersion int                     `json:"archive_version"`
+	IDsRemapped    bool                    `json:"ids_remapped"` // False when the archive came from the same account
+	Deleted        AccountImportCounts     `json:"deleted"`      // Existing entities removed in replace mode
+	Created        AccountImportCounts     `json:"created"`
+	Skipped        AccountImportCounts     `json:"skipped"` // Already present with the same ID, e.g. from an earlier import
+	Conflicts      []AccountImportConflict `json:"conflicts"`
+}
+
+// AccountImportRows holds the rows an account import writes. References
+// between the rows already use the IDs in the account.
+type AccountImportRows struct {
+	DefaultCurrency  string // Replaces the default currency of the user when set
+	Equipment        []EquipmentInsert
+	Inventory        []InventoryInsert
+	Recipes          []RecipeInsert
+	RecipeFlavors    []RecipeFlavorInsert
+	Budgets          []BudgetInsert
+	Sessions         []SessionBatchInsert
+	SessionFlavors   []FlavorInsert
+	SessionEquipment []SessionEquipmentInsert
+}
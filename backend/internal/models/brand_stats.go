@@ -0,0 +1,28 @@
+package models
+
+// BrandUsage contains usage statistics for a single tobacco brand
+type BrandUsage struct {
+	Brand           string        `json:"brand"`
+	SessionCount    int           `json:"session_count"`     // Sessions with at least one flavor of the brand
+	FlavorCount     int           `json:"flavor_count"`      // Flavor uses of the brand
+	MainFlavorCount int           `json:"main_flavor_count"` // Uses as the main flavor (flavor_order = 1)
+	MainFlavorShare float64       `json:"main_flavor_share"` // Share of all main flavors with a brand
+	TopFlavors      []FlavorCount `json:"top_flavors"`
+}
+
+// BrandDiversityBucket contains the brand diversity of one period
+type BrandDiversityBucket struct {
+	Period         string  `json:"period"` // YYYY-MM-DD (week start), YYYY-MM or YYYY
+	SessionCount   int     `json:"session_count"`
+	DistinctBrands int     `json:"distinct_brands"`
+	Entropy        float64 `json:"entropy"` // Shannon entropy in bits of the brand distribution of flavor uses
+}
+
+// BrandStats contains brand statistics for all sessions of a user
+type BrandStats struct {
+	Timezone      string                 `json:"timezone"`
+	Bucket        string                 `json:"bucket"`
+	Brands        []BrandUsage           `json:"brands"`         // Sorted by session count
+	UnbrandedUses int                    `json:"unbranded_uses"` // Flavor uses without a brand
+	Diversity     []BrandDiversityBucket `json:"diversity"`      // Every period from the first to the last session
+}
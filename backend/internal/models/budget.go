@@ -0,0 +1,80 @@
+package models
+
+import "time"
+
+// Budget periods
+const (
+	BudgetPeriodWeekly  = "weekly"
+	BudgetPeriodMonthly = "monthly"
+)
+
+// IsValidBudgetPeriod reports whether period is a known budget period
+func IsValidBudgetPeriod(period string) bool {
+	return period == BudgetPeriodWeekly || period == BudgetPeriodMonthly
+}
+
+// Budget caps the spend of a user within a week or month, optionally for a single store
+type Budget struct {
+	ID                 string    `json:"id" db:"id"`
+	UserID             string    `json:"user_id" db:"user_id"`
+	Name               string    `json:"name" db:"name"`
+	Period             string    `json:"period" db:"period"`
+	Amount             float64   `json:"amount" db:"amount"`
+	Currency           string    `json:"currency" db:"currency"`
+	StoreName          *string   `json:"store_name" db:"store_name"` // nil applies the budget to all stores
+	Timezone           string    `json:"timezone" db:"timezone"`     // Timezone the periods start in
+	LastBreachedPeriod *string   `json:"last_breached_period" db:"last_breached_period"`
+	CreatedAt          time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// BudgetInsert is used for inserting budgets without timestamps
+type BudgetInsert struct {
+	ID        string  `json:"id"`
+	UserID    string  `json:"user_id"`
+	Name      string  `json:"name"`
+	Period    string  `json:"period"`
+	Amount    float64 `json:"amount"`
+	Currency  string  `json:"currency"`
+	StoreName *string `json:"store_name,omitempty"`
+	Timezone  string  `json:"timezone"`
+}
+
+type CreateBudgetRequest struct {
+	Name      string  `json:"name" validate:"required"`
+	Period    string  `json:"period" validate:"required"` // weekly or monthly
+	Amount    float64 `json:"amount" validate:"required"`
+	Currency  *string `json:"currency"` // Defaults to the user's default currency
+	StoreName *string `json:"store_name"`
+	Timezone  *string `json:"timezone"` // Defaults to UTC
+}
+
+type UpdateBudgetRequest struct {
+	Name      *string  `json:"name"`
+	Period    *string  `json:"period"`
+	Amount    *float64 `json:"amount"`
+	Currency  *string  `json:"currency"`
+	StoreName *string  `json:"store_name"` // An empty string applies the budget to all stores
+	Timezone  *string  `json:"timezone"`
+}
+
+// BudgetStatus contains the spend of the current period of a budget
+type BudgetStatus struct {
+	Budget              Budget    `json:"budget"`
+	Period              string    `json:"period"`       // Label of the current period (YYYY-MM-DD of the week start or YYYY-MM)
+	PeriodStart         time.Time `json:"period_start"` // Inclusive
+	PeriodEnd           time.Time `json:"period_end"`   // Exclusive
+	Spent               float64   `json:"spent"`
+	Remaining           float64   `json:"remaining"` // Negative when over budget
+	PercentUsed         float64   `json:"percent_used"`
+	Projected           float64   `json:"projected"` // Linear projection of the spend at the end of the period
+	SessionCount        int       `json:"session_count"`
+	UnconvertedSessions int       `json:"unconverted_session_count"` // Sessions skipped because no exchange rate was available
+	Breached            bool      `json:"breached"`
+	ProjectedBreach     bool      `json:"projected_breach"`
+}
+
+// BudgetBreachedEvent is the payload of the budget.breached event
+type BudgetBreachedEvent struct {
+	Status BudgetStatus `json:"status"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Discovery is the first appearance of a flavor, brand, store or creator
+type Discovery struct {
+	Name           string    `json:"name"`
+	FirstSeenAt    time.Time `json:"first_seen_at"`
+	FirstSeenDate  string    `json:"first_seen_date"` // YYYY-MM-DD in the timezone
+	FirstSessionID string    `json:"first_session_id"`
+	LastSeenAt     time.Time `json:"last_seen_at"`
+	SessionCount   int       `json:"session_count"`
+}
+
+// DiscoveryMonth counts novel and repeat items in one month
+type DiscoveryMonth struct {
+	Month          string  `json:"month"` // YYYY-MM
+	SessionCount   int     `json:"session_count"`
+	NewFlavors     int     `json:"new_flavors"`    // Flavors used for the first time
+	RepeatFlavors  int     `json:"repeat_flavors"` // Distinct flavors used before this month
+	NewBrands      int     `json:"new_brands"`
+	RepeatBrands   int     `json:"repeat_brands"`
+	NewStores      int     `json:"new_stores"`
+	RepeatStores   int     `json:"repeat_stores"`
+	NewCreators    int     `json:"new_creators"`
+	RepeatCreators int     `json:"repeat_creators"`
+	NoveltyRate    float64 `json:"novelty_rate"` // New flavors divided by distinct flavors of the month
+}
+
+// DiscoveryStats contains the discovery timeline of a user
+type DiscoveryStats struct {
+	Timezone string           `json:"timezone"`
+	Flavors  []Discovery      `json:"flavors"` // Sorted by first appearance
+	Brands   []Discovery      `json:"brands"`
+	Stores   []Discovery      `json:"stores"`
+	Creators []Discovery      `json:"creators"`
+	Monthly  []DiscoveryMonth `json:"monthly"` // Every month from the first to the last session
+}
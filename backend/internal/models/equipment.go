@@ -0,0 +1,76 @@
+package models
+
+import "time"
+
+// Equipment categories
+const (
+	EquipmentCategoryBowl           = "bowl"
+	EquipmentCategoryHookah         = "hookah"
+	EquipmentCategoryHeatManagement = "heat_management"
+	EquipmentCategoryCharcoal       = "charcoal"
+)
+
+// IsValidEquipmentCategory reports whether category is a known equipment category
+func IsValidEquipmentCategory(category string) bool {
+	switch category {
+	case EquipmentCategoryBowl, EquipmentCategoryHookah, EquipmentCategoryHeatManagement, EquipmentCategoryCharcoal:
+		return true
+	}
+	return false
+}
+
+// Equipment is a piece of gear owned by a user (bowl, hookah, heat management device or charcoal type)
+type Equipment struct {
+	ID        string    `json:"id" db:"id"`
+	UserID    string    `json:"user_id" db:"user_id"`
+	Category  string    `json:"category" db:"category"`
+	Name      string    `json:"name" db:"name"`
+	Brand     *string   `json:"brand" db:"brand"`
+	Notes     *string   `json:"notes" db:"notes"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// EquipmentInsert is used for inserting equipment without timestamps
+type EquipmentInsert struct {
+	ID       string  `json:"id"`
+	UserID   string  `json:"user_id"`
+	Category string  `json:"category"`
+	Name     string  `json:"name"`
+	Brand    *string `json:"brand,omitempty"`
+	Notes    *string `json:"notes,omitempty"`
+}
+
+// SessionEquipmentInsert links a session to a piece of equipment
+type SessionEquipmentInsert struct {
+	SessionID   string `json:"session_id"`
+	EquipmentID string `json:"equipment_id"`
+}
+
+type CreateEquipmentRequest struct {
+	Category string  `json:"category" validate:"required"`
+	Name     string  `json:"name" validate:"required"`
+	Brand    *string `json:"brand"`
+	Notes    *string `json:"notes"`
+}
+
+type UpdateEquipmentRequest struct {
+	Category *string `json:"category"`
+	Name     *string `json:"name"`
+	Brand    *string `json:"brand"`
+	Notes    *string `json:"notes"`
+}
+
+// EquipmentUsage contains usage statistics for a single piece of equipment
+type EquipmentUsage struct {
+	EquipmentID string  `json:"equipment_id"`
+	Category    string  `json:"category"`
+	Name        string  `json:"name"`
+	Brand       *string `json:"brand"`
+	Usage
+}
+
+// EquipmentStats contains usage statistics for all equipment of a user
+type EquipmentStats struct {
+	Equipment []EquipmentUsage `json:"equipment"` // Sorted by session count
+}
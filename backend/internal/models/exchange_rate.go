@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// ExchangeRate states that 1 unit of BaseCurrency equals Rate units of QuoteCurrency on RateDate
+type ExchangeRate struct {
+	ID            string    `json:"id" db:"id"`
+	RateDate      string    `json:"rate_date" db:"rate_date"` // YYYY-MM-DD
+	BaseCurrency  string    `json:"base_currency" db:"base_currency"`
+	QuoteCurrency string    `json:"quote_currency" db:"quote_currency"`
+	Rate          float64   `json:"rate" db:"rate"`
+	Source        *string   `json:"source" db:"source"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// ExchangeRateInsert is used for upserting exchange rates without timestamps
+type ExchangeRateInsert struct {
+	RateDate      string  `json:"rate_date"`
+	BaseCurrency  string  `json:"base_currency"`
+	QuoteCurrency string  `json:"quote_currency"`
+	Rate          float64 `json:"rate"`
+	Source        *string `json:"source"`
+}
+
+type ImportExchangeRatesRequest struct {
+	Source *string              `json:"source"`
+	Rates  []ExchangeRateImport `json:"rates"`
+}
+
+type ExchangeRateImport struct {
+	Date  string  `json:"date"`  // YYYY-MM-DD
+	Base  string  `json:"base"`  // ISO 4217 code
+	Quote string  `json:"quote"` // ISO 4217 code
+	Rate  float64 `json:"rate"`
+}
+
+// ExchangeRateImportResult reports the outcome of an exchange rate import
+type ExchangeRateImportResult struct {
+	Imported int      `json:"imported"`
+	Errors   []string `json:"errors"` // Rows that were rejected
+}
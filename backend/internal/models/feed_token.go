@@ -0,0 +1,25 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FeedToken is a secret token authorizing the iCalendar feed of a user
+type FeedToken struct {
+	ID         uuid.UUID    `json:"id"`
+	UserID     uuid.UUID    `json:"user_id"`
+	Token      string       `json:"token"`
+	CreatedAt  time.Time    `json:"created_at"`
+	LastUsedAt sql.NullTime `json:"last_used_at,omitempty"`
+	RevokedAt  sql.NullTime `json:"revoked_at,omitempty"`
+}
+
+// FeedTokenResponse describes the active calendar feed of a user
+type FeedTokenResponse struct {
+	URL        string     `json:"url"` // Subscription URL including the secret token
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+}
@@ -0,0 +1,54 @@
+package models
+
+// Sort orders for flavor pairs and partners
+const (
+	PairSortCount  = "count"
+	PairSortLift   = "lift"
+	PairSortPMI    = "pmi"
+	PairSortRating = "rating"
+)
+
+// IsValidPairSort reports whether sort is a known flavor pair sort order
+func IsValidPairSort(sort string) bool {
+	switch sort {
+	case PairSortCount, PairSortLift, PairSortPMI, PairSortRating:
+		return true
+	}
+	return false
+}
+
+// FlavorPair contains co-occurrence statistics of two flavors used in the same session
+type FlavorPair struct {
+	FlavorA       string   `json:"flavor_a"` // FlavorA sorts before FlavorB
+	FlavorB       string   `json:"flavor_b"`
+	Count         int      `json:"count"`          // Sessions containing both flavors
+	Support       float64  `json:"support"`        // Count divided by sessions with flavors
+	Lift          float64  `json:"lift"`           // Greater than 1 when paired more often than by chance
+	PMI           float64  `json:"pmi"`            // log2 of lift
+	RatedCount    int      `json:"rated_count"`    // Sessions with both flavors and a rating
+	AverageRating *float64 `json:"average_rating"` // nil when no session with both flavors is rated
+}
+
+// FlavorPairStats contains flavor pair statistics for all sessions of a user
+type FlavorPairStats struct {
+	SessionCount int          `json:"session_count"` // Sessions with at least one flavor
+	Pairs        []FlavorPair `json:"pairs"`
+}
+
+// FlavorPartner contains the statistics of a flavor combined with a given flavor
+type FlavorPartner struct {
+	FlavorName    string   `json:"flavor_name"`
+	Count         int      `json:"count"`      // Sessions containing both flavors
+	Confidence    float64  `json:"confidence"` // Share of the given flavor's sessions that contain this partner
+	Lift          float64  `json:"lift"`
+	PMI           float64  `json:"pmi"`
+	RatedCount    int      `json:"rated_count"`
+	AverageRating *float64 `json:"average_rating"`
+}
+
+// FlavorPartners contains the best companions of a flavor
+type FlavorPartners struct {
+	FlavorName   string          `json:"flavor_name"`
+	SessionCount int             `json:"session_count"` // Sessions containing the flavor
+	Partners     []FlavorPartner `json:"partners"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// HabitBreak is the longest period without sessions
+type HabitBreak struct {
+	From string `json:"from"` // Last session day before the break (YYYY-MM-DD)
+	To   string `json:"to"`   // First session day after the break (YYYY-MM-DD)
+	Days int    `json:"days"` // Days between both session days
+}
+
+// HabitWeek is the session count of one week
+type HabitWeek struct {
+	Week         string `json:"week"` // YYYY-MM-DD of the Monday starting the week
+	SessionCount int    `json:"session_count"`
+}
+
+// HabitStats contains streak, gap and frequency metrics in the user's timezone
+type HabitStats struct {
+	Timezone            string      `json:"timezone"`
+	SessionCount        int         `json:"session_count"`
+	ActiveDays          int         `json:"active_days"` // Days with at least one session
+	FirstSessionAt      *time.Time  `json:"first_session_at"`
+	LastSessionAt       *time.Time  `json:"last_session_at"`
+	FirstSessionDate    *string     `json:"first_session_date"` // YYYY-MM-DD in the timezone
+	LastSessionDate     *string     `json:"last_session_date"`
+	CurrentDailyStreak  int         `json:"current_daily_streak"` // Consecutive days ending today or yesterday
+	LongestDailyStreak  int         `json:"longest_daily_streak"`
+	CurrentWeeklyStreak int         `json:"current_weekly_streak"` // Consecutive weeks ending this or last week
+	LongestWeeklyStreak int         `json:"longest_weekly_streak"`
+	AverageDaysBetween  *float64    `json:"average_days_between"` // Between consecutive active days, nil with fewer than two
+	MedianDaysBetween   *float64    `json:"median_days_between"`
+	LongestBreak        *HabitBreak `json:"longest_break"`
+	WeeklyTrend         []HabitWeek `json:"weekly_trend"` // Most recent weeks up to the current week, oldest first
+	TrendSlope          float64     `json:"trend_slope"`  // Least squares change in sessions per week per week over WeeklyTrend
+}
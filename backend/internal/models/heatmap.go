@@ -0,0 +1,33 @@
+package models
+
+// HeatmapDay is the session count of one weekday
+type HeatmapDay struct {
+	Day   string `json:"day"` // Monday..Sunday
+	Count int    `json:"count"`
+}
+
+// HeatmapHour is the session count of one hour of the day
+type HeatmapHour struct {
+	Hour  int `json:"hour"` // 0-23
+	Count int `json:"count"`
+}
+
+// HeatmapSlot is the session count of one weekday and hour combination
+type HeatmapSlot struct {
+	Day   string `json:"day"`
+	Hour  int    `json:"hour"`
+	Count int    `json:"count"`
+}
+
+// Heatmap contains session counts per weekday and hour of the day
+type Heatmap struct {
+	Timezone     string        `json:"timezone"`
+	Store        *string       `json:"store"`  // Store filter, nil when not filtered
+	Flavor       *string       `json:"flavor"` // Flavor filter, nil when not filtered
+	Days         []string      `json:"days"`   // Row labels, starting on Monday
+	Matrix       [7][24]int    `json:"matrix"` // Matrix[day][hour], rows follow Days
+	SessionCount int           `json:"session_count"`
+	TopDay       *HeatmapDay   `json:"top_day"`  // nil when there are no sessions
+	TopHour      *HeatmapHour  `json:"top_hour"` // nil when there are no sessions
+	TopSlots     []HeatmapSlot `json:"top_slots"`
+}
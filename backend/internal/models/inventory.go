@@ -0,0 +1,79 @@
+package models
+
+import "time"
+
+// InventoryItem is a tobacco package owned by a user
+type InventoryItem struct {
+	ID           string    `json:"id" db:"id"`
+	UserID       string    `json:"user_id" db:"user_id"`
+	FlavorName   string    `json:"flavor_name" db:"flavor_name"`
+	Brand        *string   `json:"brand" db:"brand"`
+	WeightGrams  float64   `json:"weight_grams" db:"weight_grams"`
+	PurchaseDate *string   `json:"purchase_date" db:"purchase_date"` // YYYY-MM-DD
+	Price        *int      `json:"price" db:"price"`
+	OpenedDate   *string   `json:"opened_date" db:"opened_date"` // YYYY-MM-DD
+	Notes        *string   `json:"notes" db:"notes"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// InventoryInsert is used for inserting inventory items without timestamps
+type InventoryInsert struct {
+	ID           string  `json:"id"`
+	UserID       string  `json:"user_id"`
+	FlavorName   string  `json:"flavor_name"`
+	Brand        *string `json:"brand,omitempty"`
+	WeightGrams  float64 `json:"weight_grams"`
+	PurchaseDate *string `json:"purchase_date,omitempty"`
+	Price        *int    `json:"price,omitempty"`
+	OpenedDate   *string `json:"opened_date,omitempty"`
+	Notes        *string `json:"notes,omitempty"`
+}
+
+type CreateInventoryRequest struct {
+	FlavorName   string  `json:"flavor_name" validate:"required"`
+	Brand        *string `json:"brand"`
+	WeightGrams  float64 `json:"weight_grams" validate:"required"`
+	PurchaseDate *string `json:"purchase_date"` // YYYY-MM-DD
+	Price        *int    `json:"price"`
+	OpenedDate   *string `json:"opened_date"` // YYYY-MM-DD
+	Notes        *string `json:"notes"`
+}
+
+type UpdateInventoryRequest struct {
+	FlavorName   *string  `json:"flavor_name"`
+	Brand        *string  `json:"brand"`
+	WeightGrams  *float64 `json:"weight_grams"`
+	PurchaseDate *string  `json:"purchase_date"` // YYYY-MM-DD, empty string clears
+	Price        *int     `json:"price"`
+	OpenedDate   *string  `json:"opened_date"` // YYYY-MM-DD, empty string clears
+	Notes        *string  `json:"notes"`
+}
+
+// InventoryConsumption is a single draw of tobacco from an inventory item
+type InventoryConsumption struct {
+	InventoryID string    `json:"inventory_id"`
+	SessionID   string    `json:"session_id"`
+	Grams       float64   `json:"grams"`
+	SessionDate time.Time `json:"session_date"`
+}
+
+// InventoryItemStatus is an inventory item with its consumption and projected stock
+type InventoryItemStatus struct {
+	InventoryItem
+	ConsumedGrams       float64  `json:"consumed_grams"`
+	RemainingGrams      float64  `json:"remaining_grams"`
+	SessionCount        int      `json:"session_count"`
+	LastUsedDate        *string  `json:"last_used_date"`         // YYYY-MM-DD
+	DailyUsageGrams     *float64 `json:"daily_usage_grams"`      // nil when the item has not been used
+	ProjectedRunOutDate *string  `json:"projected_run_out_date"` // YYYY-MM-DD, nil when it cannot be projected
+	LowStock            bool     `json:"low_stock"`
+}
+
+// InventoryReport contains the stock of all inventory items of a user
+type InventoryReport struct {
+	Items               []InventoryItemStatus `json:"items"`
+	LowStock            []InventoryItemStatus `json:"low_stock"` // Items at or below the low stock threshold
+	LowStockGrams       float64               `json:"low_stock_grams"`
+	TotalRemainingGrams float64               `json:"total_remaining_grams"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// JournalEntry is a session in a journal with its local time
+type JournalEntry struct {
+	SessionWithFlavors
+	LocalTime time.Time `json:"local_time"` // Session date in the journal timezone
+}
+
+// JournalMonth groups the journal entries of one month, oldest first
+type JournalMonth struct {
+	Month               string         `json:"month"` // YYYY-MM
+	SessionCount        int            `json:"session_count"`
+	Spend               float64        `json:"spend"`
+	UnconvertedSessions int            `json:"unconverted_session_count"`
+	Entries             []JournalEntry `json:"entries"`
+}
+
+// JournalSummary summarizes the sessions of a journal
+type JournalSummary struct {
+	SessionCount        int            `json:"session_count"`
+	ActiveDays          int            `json:"active_days"`
+	Spend               float64        `json:"spend"`
+	UnconvertedSessions int            `json:"unconverted_session_count"` // Sessions whose amount could not be converted
+	AverageRating       *float64       `json:"average_rating"`            // nil when no session is rated
+	TotalMinutes        int            `json:"total_minutes"`
+	TopFlavors          []FlavorCount  `json:"top_flavors"`
+	TopStores           []StoreCount   `json:"top_stores"`
+	TopCreators         []CreatorCount `json:"top_creators"`
+}
+
+// Journal holds the sessions in a date range grouped by month for printing
+type Journal struct {
+	From     *string        `json:"from"` // YYYY-MM-DD, nil when the range is open
+	To       *string        `json:"to"`   // YYYY-MM-DD, nil when the range is open
+	Timezone string         `json:"timezone"`
+	Currency string         `json:"currency"` // Currency of the spend
+	Summary  JournalSummary `json:"summary"`
+	Months   []JournalMonth `json:"months"`
+}
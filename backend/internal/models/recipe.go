@@ -0,0 +1,76 @@
+package models
+
+import "time"
+
+// Recipe is a saved mix that can be reused when logging sessions
+type Recipe struct {
+	ID        string    `json:"id" db:"id"`
+	UserID    string    `json:"user_id" db:"user_id"`
+	Name      string    `json:"name" db:"name"`
+	Notes     *string   `json:"notes" db:"notes"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type RecipeFlavor struct {
+	ID          string    `json:"id" db:"id"`
+	RecipeID    string    `json:"recipe_id" db:"recipe_id"`
+	FlavorName  string    `json:"flavor_name" db:"flavor_name"`
+	Brand       *string   `json:"brand" db:"brand"`
+	Ratio       *float64  `json:"ratio" db:"ratio"`
+	FlavorOrder int       `json:"flavor_order" db:"flavor_order"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+type RecipeWithFlavors struct {
+	Recipe
+	Flavors []RecipeFlavor `json:"flavors"`
+}
+
+// RecipeInsert is used for inserting recipes without timestamps
+type RecipeInsert struct {
+	ID     string  `json:"id"`
+	UserID string  `json:"user_id"`
+	Name   string  `json:"name"`
+	Notes  *string `json:"notes,omitempty"`
+}
+
+// RecipeFlavorInsert is used for inserting recipe flavors without timestamps
+type RecipeFlavorInsert struct {
+	ID          string   `json:"id"`
+	RecipeID    string   `json:"recipe_id"`
+	FlavorName  string   `json:"flavor_name"`
+	Brand       *string  `json:"brand"`
+	Ratio       *float64 `json:"ratio"`
+	FlavorOrder int      `json:"flavor_order"`
+}
+
+type CreateRecipeRequest struct {
+	Name    string                      `json:"name" validate:"required"`
+	Notes   *string                     `json:"notes"`
+	Flavors []CreateRecipeFlavorRequest `json:"flavors"` // Ordered, the first flavor is the main flavor
+}
+
+type CreateRecipeFlavorRequest struct {
+	FlavorName string   `json:"flavor_name" validate:"required"`
+	Brand      *string  `json:"brand"`
+	Ratio      *float64 `json:"ratio"`
+}
+
+type UpdateRecipeRequest struct {
+	Name    *string                      `json:"name"`
+	Notes   *string                      `json:"notes"`
+	Flavors *[]CreateRecipeFlavorRequest `json:"flavors"`
+}
+
+// RecipeUsage contains usage statistics for a single recipe
+type RecipeUsage struct {
+	RecipeID string `json:"recipe_id"`
+	Name     string `json:"name"`
+	Usage
+}
+
+// RecipeStats contains usage statistics for all recipes of a user
+type RecipeStats struct {
+	Recipes []RecipeUsage `json:"recipes"` // Sorted by session count
+}
@@ -0,0 +1,25 @@
+package models
+
+// Recommendation types
+const (
+	RecommendationMix     = "mix"     // Flavors not yet combined
+	RecommendationFlavor  = "flavor"  // A flavor in the inventory that was never smoked
+	RecommendationRevisit = "revisit" // A well rated flavor not smoked for a while
+)
+
+// Recommendation is a suggestion of what to smoke next
+type Recommendation struct {
+	Type            string   `json:"type"`
+	Flavors         []string `json:"flavors"`
+	Score           float64  `json:"score"`            // 0-1, higher is better
+	PredictedRating float64  `json:"predicted_rating"` // Expected rating from similar sessions, 1-5
+	Novelty         float64  `json:"novelty"`          // 0-1, how unfamiliar the suggestion is
+	Explanation     string   `json:"explanation"`
+}
+
+// RecommendationList contains recommendations ordered by score
+type RecommendationList struct {
+	Exploration     float64          `json:"exploration"` // Weight of novelty against predicted rating
+	SessionCount    int              `json:"session_count"`
+	Recommendations []Recommendation `json:"recommendations"`
+}
@@ -5,18 +5,22 @@ import (
 )
 
 type ShishaSession struct {
-	ID           string    `json:"id" db:"id"`
-	UserID       string    `json:"user_id" db:"user_id"`
-	CreatedBy    string    `json:"created_by" db:"created_by"`
-	SessionDate  time.Time `json:"session_date" db:"session_date"`
-	StoreName    *string   `json:"store_name" db:"store_name"`
-	Notes        *string   `json:"notes" db:"notes"`
-	OrderDetails *string   `json:"order_details" db:"order_details"`
-	MixName      *string   `json:"mix_name" db:"mix_name"`
-	Creator      *string   `json:"creator" db:"creator"`
-	Amount       *int      `json:"amount" db:"amount"`
-	CreatedAt    time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+	ID              string    `json:"id" db:"id"`
+	UserID          string    `json:"user_id" db:"user_id"`
+	CreatedBy       string    `json:"created_by" db:"created_by"`
+	SessionDate     time.Time `json:"session_date" db:"session_date"`
+	StoreName       *string   `json:"store_name" db:"store_name"`
+	Notes           *string   `json:"notes" db:"notes"`
+	OrderDetails    *string   `json:"order_details" db:"order_details"`
+	MixName         *string   `json:"mix_name" db:"mix_name"`
+	Creator         *string   `json:"creator" db:"creator"`
+	Amount          *int      `json:"amount" db:"amount"`
+	Currency        *string   `json:"currency" db:"currency"` // ISO 4217 code of the amount
+	DurationMinutes *int      `json:"duration_minutes" db:"duration_minutes"`
+	Rating          *int      `json:"rating" db:"rating"`
+	RecipeID        *string   `json:"recipe_id" db:"recipe_id"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
 }
 
 type SessionFlavor struct {
@@ -25,39 +29,55 @@ type SessionFlavor struct {
 	FlavorName  *string   `json:"flavor_name" db:"flavor_name"`
 	Brand       *string   `json:"brand" db:"brand"`
 	FlavorOrder int       `json:"flavor_order" db:"flavor_order"`
+	InventoryID *string   `json:"inventory_id" db:"inventory_id"`
+	Grams       *float64  `json:"grams" db:"grams"`
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 }
 
 type SessionWithFlavors struct {
 	ShishaSession
-	Flavors []SessionFlavor `json:"flavors"`
+	Flavors      []SessionFlavor `json:"flavors"`
+	EquipmentIDs []string        `json:"equipment_ids"`
 }
 
 type CreateSessionRequest struct {
-	SessionDate  time.Time              `json:"session_date" validate:"required"`
-	StoreName    *string                `json:"store_name"`
-	Notes        *string                `json:"notes"`
-	OrderDetails *string                `json:"order_details"`
-	MixName      *string                `json:"mix_name"`
-	Creator      *string                `json:"creator"`
-	Amount       *int                   `json:"amount"`
-	Flavors      *[]CreateFlavorRequest `json:"flavors"`
+	SessionDate     time.Time              `json:"session_date" validate:"required"`
+	StoreName       *string                `json:"store_name"`
+	Notes           *string                `json:"notes"`
+	OrderDetails    *string                `json:"order_details"`
+	MixName         *string                `json:"mix_name"`
+	Creator         *string                `json:"creator"`
+	Amount          *int                   `json:"amount"`
+	Currency        *string                `json:"currency"` // ISO 4217 code, defaults to the user's default currency
+	DurationMinutes *int                   `json:"duration_minutes"`
+	Rating          *int                   `json:"rating"`
+	RecipeID        *string                `json:"recipe_id"`
+	BowlGrams       *float64               `json:"bowl_grams"` // With recipe_id, splits this weight across the recipe flavors by their ratios
+	Flavors         *[]CreateFlavorRequest `json:"flavors"`
+	EquipmentIDs    *[]string              `json:"equipment_ids"`
 }
 
 type CreateFlavorRequest struct {
-	FlavorName *string `json:"flavor_name"`
-	Brand      *string `json:"brand"`
+	FlavorName  *string  `json:"flavor_name"`
+	Brand       *string  `json:"brand"`
+	InventoryID *string  `json:"inventory_id"` // Inventory item the tobacco was drawn from
+	Grams       *float64 `json:"grams"`        // Grams drawn from the inventory item
 }
 
 type UpdateSessionRequest struct {
-	SessionDate  *time.Time             `json:"session_date"`
-	StoreName    *string                `json:"store_name"`
-	Notes        *string                `json:"notes"`
-	OrderDetails *string                `json:"order_details"`
-	MixName      *string                `json:"mix_name"`
-	Creator      *string                `json:"creator"`
-	Amount       *int                   `json:"amount"`
-	Flavors      *[]CreateFlavorRequest `json:"flavors"`
+	SessionDate     *time.Time             `json:"session_date"`
+	StoreName       *string                `json:"store_name"`
+	Notes           *string                `json:"notes"`
+	OrderDetails    *string                `json:"order_details"`
+	MixName         *string                `json:"mix_name"`
+	Creator         *string                `json:"creator"`
+	Amount          *int                   `json:"amount"`
+	Currency        *string                `json:"currency"` // ISO 4217 code, defaults to the user's default currency
+	DurationMinutes *int                   `json:"duration_minutes"`
+	Rating          *int                   `json:"rating"`
+	RecipeID        *string                `json:"recipe_id"`
+	Flavors         *[]CreateFlavorRequest `json:"flavors"`
+	EquipmentIDs    *[]string              `json:"equipment_ids"`
 }
 
 type StoreCount struct {
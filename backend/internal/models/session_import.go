@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// ImportedSession is a session parsed from an import file
+type ImportedSession struct {
+	SessionDate     time.Time             `json:"session_date"`
+	StoreName       *string               `json:"store_name"`
+	MixName         *string               `json:"mix_name"`
+	Creator         *string               `json:"creator"`
+	Notes           *string               `json:"notes"`
+	OrderDetails    *string               `json:"order_details"`
+	Amount          *int                  `json:"amount"`
+	Currency        *string               `json:"currency"`
+	DurationMinutes *int                  `json:"duration_minutes"`
+	Rating          *int                  `json:"rating"`
+	Flavors         []CreateFlavorRequest `json:"flavors"`
+}
+
+// ImportRow is the parse result of one CSV row
+type ImportRow struct {
+	Line            int              `json:"line"` // Line number in the file, the header is line 1
+	Session         *ImportedSession `json:"session"`
+	Errors          []string         `json:"errors"`
+	DuplicateOf     *string          `json:"duplicate_of"`      // Existing session on the same date at the same store
+	DuplicateOfLine *int             `json:"duplicate_of_line"` // Earlier row on the same date at the same store
+}
+
+// ImportCSVResult reports a CSV import or its dry run
+type ImportCSVResult struct {
+	DryRun        bool              `json:"dry_run"`
+	Timezone      string            `json:"timezone"`
+	Columns       []string          `json:"columns"` // Header of the file
+	Mapping       map[string]string `json:"mapping"` // Session field to column
+	TotalRows     int               `json:"total_rows"`
+	ValidRows     int               `json:"valid_rows"`
+	InvalidRows   int               `json:"invalid_rows"`
+	DuplicateRows int               `json:"duplicate_rows"`
+	Created       int               `json:"created"`
+	Rows          []ImportRow       `json:"rows"`
+}
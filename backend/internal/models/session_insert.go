@@ -4,25 +4,50 @@ import "time"
 
 // SessionInsert is used for inserting sessions without timestamps
 type SessionInsert struct {
-	ID           string    `json:"id"`
-	UserID       string    `json:"user_id"`
-	CreatedBy    string    `json:"created_by"`
-	SessionDate  time.Time `json:"session_date"`
-	StoreName    *string   `json:"store_name,omitempty"`
-	Notes        *string   `json:"notes,omitempty"`
-	OrderDetails *string   `json:"order_details,omitempty"`
-	MixName      *string   `json:"mix_name,omitempty"`
-	Creator      *string   `json:"creator,omitempty"`
-	Amount       *int      `json:"amount,omitempty"`
+	ID              string    `json:"id"`
+	UserID          string    `json:"user_id"`
+	CreatedBy       string    `json:"created_by"`
+	SessionDate     time.Time `json:"session_date"`
+	StoreName       *string   `json:"store_name,omitempty"`
+	Notes           *string   `json:"notes,omitempty"`
+	OrderDetails    *string   `json:"order_details,omitempty"`
+	MixName         *string   `json:"mix_name,omitempty"`
+	Creator         *string   `json:"creator,omitempty"`
+	Amount          *int      `json:"amount,omitempty"`
+	Currency        *string   `json:"currency,omitempty"`
+	DurationMinutes *int      `json:"duration_minutes,omitempty"`
+	Rating          *int      `json:"rating,omitempty"`
+	RecipeID        *string   `json:"recipe_id,omitempty"`
 	// Explicitly exclude created_at and updated_at
 }
 
 // FlavorInsert is used for inserting flavors without timestamps
 type FlavorInsert struct {
-	ID          string  `json:"id"`
-	SessionID   string  `json:"session_id"`
-	FlavorName  *string `json:"flavor_name"`
-	Brand       *string `json:"brand"`
-	FlavorOrder int     `json:"flavor_order"`
+	ID          string   `json:"id"`
+	SessionID   string   `json:"session_id"`
+	FlavorName  *string  `json:"flavor_name"`
+	Brand       *string  `json:"brand"`
+	FlavorOrder int      `json:"flavor_order"`
+	InventoryID *string  `json:"inventory_id"`
+	Grams       *float64 `json:"grams"`
 	// Explicitly exclude created_at
 }
+
+// SessionBatchInsert is used for inserting many sessions in one request.
+// Bulk inserts need the same keys in every row, so no field is omitted.
+type SessionBatchInsert struct {
+	ID              string    `json:"id"`
+	UserID          string    `json:"user_id"`
+	CreatedBy       string    `json:"created_by"`
+	SessionDate     time.Time `json:"session_date"`
+	StoreName       *string   `json:"store_name"`
+	Notes           *string   `json:"notes"`
+	OrderDetails    *string   `json:"order_details"`
+	MixName         *string   `json:"mix_name"`
+	Creator         *string   `json:"creator"`
+	Amount          *int      `json:"amount"`
+	Currency        *string   `json:"currency"`
+	DurationMinutes *int      `json:"duration_minutes"`
+	Rating          *int      `json:"rating"`
+	RecipeID        *string   `json:"recipe_id"`
+}
@@ -0,0 +1,12 @@
+package models
+
+// SimilarSession is a session ranked by its similarity to another session
+type SimilarSession struct {
+	Session       SessionWithFlavors `json:"session"`
+	Score         float64            `json:"score"`        // 0-1, weighted sum of the components below
+	FlavorScore   float64            `json:"flavor_score"` // Flavor overlap weighted by flavor order, 0-1
+	SharedFlavors []string           `json:"shared_flavors"`
+	SameStore     bool               `json:"same_store"`
+	SameCreator   bool               `json:"same_creator"`
+	SameMixName   bool               `json:"same_mix_name"`
+}
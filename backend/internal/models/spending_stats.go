@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// SpendingBucket contains the spend within one day, week, month or year
+type SpendingBucket struct {
+	Period       string  `json:"period"` // YYYY-MM-DD (day, week start), YYYY-MM or YYYY
+	Total        float64 `json:"total"`
+	SessionCount int     `json:"session_count"`
+	Average      float64 `json:"average"` // Average spend per session
+}
+
+// SpendingPeriodStats contains spend grouped by one bucket size
+type SpendingPeriodStats struct {
+	Buckets          []SpendingBucket `json:"buckets"`            // Only periods with spending, sorted by period
+	AveragePerPeriod float64          `json:"average_per_period"` // Total divided by every period from the first to the last session
+}
+
+// SpendingGroup contains the spend for one store or creator
+type SpendingGroup struct {
+	Name         string  `json:"name"`
+	Total        float64 `json:"total"`
+	SessionCount int     `json:"session_count"`
+	Average      float64 `json:"average"`
+}
+
+// SessionSpend is a single session with its amount
+type SessionSpend struct {
+	SessionID        string    `json:"session_id"`
+	SessionDate      time.Time `json:"session_date"`
+	StoreName        *string   `json:"store_name"`
+	MixName          *string   `json:"mix_name"`
+	Creator          *string   `json:"creator"`
+	Amount           float64   `json:"amount"` // In the report currency
+	OriginalAmount   int       `json:"original_amount"`
+	OriginalCurrency string    `json:"original_currency"`
+}
+
+// SpendingStats contains spending statistics built on session amounts
+type SpendingStats struct {
+	Timezone             string              `json:"timezone"`
+	Currency             string              `json:"currency"`                  // Currency all amounts are reported in
+	UnconvertedSessions  int                 `json:"unconverted_session_count"` // Sessions skipped because no exchange rate was available
+	MissingCurrencies    []string            `json:"missing_currencies"`        // Currencies without a rate to the report currency
+	Total                float64             `json:"total"`
+	SessionCount         int                 `json:"session_count"` // Sessions with an amount
+	AveragePerSession    float64             `json:"average_per_session"`
+	Daily                SpendingPeriodStats `json:"daily"`
+	Weekly               SpendingPeriodStats `json:"weekly"` // Weeks start on Monday
+	Monthly              SpendingPeriodStats `json:"monthly"`
+	Yearly               SpendingPeriodStats `json:"yearly"`
+	ByStore              []SpendingGroup     `json:"by_store"`
+	ByCreator            []SpendingGroup     `json:"by_creator"`
+	CostPerHour          *float64            `json:"cost_per_hour"` // nil when no session with an amount has a duration
+	SessionsWithDuration int                 `json:"sessions_with_duration"`
+	MostExpensive        []SessionSpend      `json:"most_expensive"`
+	LeastExpensive       []SessionSpend      `json:"least_expensive"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// TimeSeriesBucket contains session activity within one day, week or month
+type TimeSeriesBucket struct {
+	Period          string    `json:"period"` // YYYY-MM-DD (day, week start) or YYYY-MM
+	Start           time.Time `json:"start"`
+	SessionCount    int       `json:"session_count"`
+	Spend           float64   `json:"spend"`
+	DistinctFlavors int       `json:"distinct_flavors"`
+}
+
+// TimeSeries contains gap-filled session activity buckets for a date range
+type TimeSeries struct {
+	Timezone            string             `json:"timezone"`
+	Bucket              string             `json:"bucket"`
+	From                string             `json:"from"` // YYYY-MM-DD, inclusive
+	To                  string             `json:"to"`   // YYYY-MM-DD, inclusive
+	Currency            string             `json:"currency"`
+	Buckets             []TimeSeriesBucket `json:"buckets"` // Every bucket in the range, including empty ones
+	SessionCount        int                `json:"session_count"`
+	Spend               float64            `json:"spend"`
+	DistinctFlavors     int                `json:"distinct_flavors"`
+	UnconvertedSessions int                `json:"unconverted_session_count"` // Sessions whose amount could not be converted
+}
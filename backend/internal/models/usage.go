@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// Usage contains how often something was used in sessions and how those sessions were rated
+type Usage struct {
+	SessionCount  int        `json:"session_count"`
+	RatedCount    int        `json:"rated_count"`
+	AverageRating *float64   `json:"average_rating"` // nil when no session using it is rated
+	LastUsedAt    *time.Time `json:"last_used_at"`
+}
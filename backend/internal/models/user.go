@@ -7,11 +7,16 @@ import (
 )
 
 type User struct {
-	ID           uuid.UUID `json:"id"`
-	UserID       string    `json:"user_id"`
-	PasswordHash string    `json:"-"` // Never expose password hash in JSON
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID              uuid.UUID `json:"id"`
+	UserID          string    `json:"user_id"`
+	PasswordHash    string    `json:"-"`                // Never expose password hash in JSON
+	DefaultCurrency string    `json:"default_currency"` // ISO 4217 code used when a session has no currency
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+type UpdateUserRequest struct {
+	DefaultCurrency *string `json:"default_currency"`
 }
 
 type PasswordResetToken struct {
@@ -0,0 +1,28 @@
+package models
+
+// YearReportMonth contains the activity of one month of a year in review
+type YearReportMonth struct {
+	Month        string  `json:"month"` // YYYY-MM
+	SessionCount int     `json:"session_count"`
+	Spend        float64 `json:"spend"`
+}
+
+// YearReport is a year-in-review summary of a user's sessions
+type YearReport struct {
+	Year                int               `json:"year"`
+	Timezone            string            `json:"timezone"`
+	Currency            string            `json:"currency"`
+	SessionCount        int               `json:"session_count"`
+	ActiveDays          int               `json:"active_days"`
+	Spend               float64           `json:"spend"`
+	UnconvertedSessions int               `json:"unconverted_session_count"` // Sessions whose amount could not be converted
+	AverageRating       *float64          `json:"average_rating"`            // nil when no session of the year is rated
+	TopFlavors          []FlavorCount     `json:"top_flavors"`
+	TopStores           []StoreCount      `json:"top_stores"`
+	TopCreators         []CreatorCount    `json:"top_creators"`
+	NewFlavors          []string          `json:"new_flavors"` // Flavors first used this year
+	NewStores           []string          `json:"new_stores"`  // Stores first visited this year
+	LongestStreak       int               `json:"longest_streak"`
+	BusiestMonth        *YearReportMonth  `json:"busiest_month"` // nil when there are no sessions
+	Months              []YearReportMonth `json:"months"`        // All twelve months
+}
@@ -0,0 +1,236 @@
+// Package pdf writes simple flowing text documents as PDF in pure Go.
+//
+// Text is set in the Japanese CID font KozMinPr6N-Regular without embedding,
+// so the document stays small and viewers substitute an installed Mincho font.
+// The UniJIS-UCS2-HW-H encoding sets ASCII characters half-width and everything
+// else full-width, which is what the line breaking assumes. Characters outside
+// the Basic Multilingual Plane are replaced.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+)
+
+// A4 page size and margins in points
+const (
+	pageWidth  = 595.28
+	pageHeight = 841.89
+	margin     = 50.0
+)
+
+// lineSpacing is the line height relative to the font size
+const lineSpacing = 1.5
+
+// replacementChar replaces characters the encoding cannot represent
+const replacementChar = '〓'
+
+// Style controls how a paragraph is set
+type Style struct {
+	Size   float64 // Font size in points
+	Indent float64 // Left indent in points
+	Gray   float64 // Text gray level, 0 is black
+}
+
+// Document is a PDF document being written page by page
+type Document struct {
+	title string
+	pages []*bytes.Buffer
+	y     float64 // Baseline of the next line on the current page
+}
+
+// New returns an empty document with the given title
+func New(title string) *Document {
+	return &Document{title: title}
+}
+
+// Text writes a paragraph, wrapping it to the page width and starting new
+// pages as needed. Newlines in text start new lines.
+func (d *Document) Text(text string, style Style) {
+	if style.Size <= 0 {
+		style.Size = 10
+	}
+	width := pageWidth - 2*margin - style.Indent
+
+	for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		for _, line := range wrap(paragraph, style.Size, width) {
+			d.line(line, style)
+		}
+	}
+}
+
+// Space adds vertical space in points
+func (d *Document) Space(points float64) {
+	if d.page() == nil {
+		return
+	}
+	d.y -= points
+}
+
+// Rule draws a horizontal line across the text area
+func (d *Document) Rule() {
+	d.ensure(8)
+	page := d.page()
+	fmt.Fprintf(page, "0.8 G 0.5 w %.2f %.2f m %.2f %.2f l S\n", margin, d.y+4, pageWidth-margin, d.y+4)
+	d.y -= 8
+}
+
+// PageBreak starts a new page unless the current page is empty
+func (d *Document) PageBreak() {
+	if d.page() != nil && d.y < pageHeight-margin {
+		d.newPage()
+	}
+}
+
+// line writes a single line, starting a new page if it does not fit
+func (d *Document) line(text string, style Style) {
+	height := style.Size * lineSpacing
+	d.ensure(height)
+	d.y -= style.Size
+
+	fmt.Fprintf(d.page(), "BT %.2f g /F1 %.2f Tf %.2f %.2f Td <%s> Tj ET\n",
+		style.Gray, style.Size, margin+style.Indent, d.y, encodeText(text))
+
+	d.y -= height - style.Size
+}
+
+// ensure starts a new page unless height points fit on the current one
+func (d *Document) ensure(height float64) {
+	if d.page() == nil || d.y-height < margin {
+		d.newPage()
+	}
+}
+
+func (d *Document) newPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.y = pageHeight - margin
+}
+
+func (d *Document) page() *bytes.Buffer {
+	if len(d.pages) == 0 {
+		return nil
+	}
+	return d.pages[len(d.pages)-1]
+}
+
+// WriteTo writes the document as PDF
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	if len(d.pages) == 0 {
+		d.newPage()
+	}
+
+	out := &bytes.Buffer{}
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Fixed objects: 1 catalog, 2 page tree, 3 info, 4-6 font, then a page and its contents per page
+	const firstPage = 7
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object(fmt.Sprintf("<< /Title <%s> /Producer (Shisha Log) >>", "FEFF"+encodeText(d.title)))
+	object("<< /Type /Font /Subtype /Type0 /BaseFont /KozMinPr6N-Regular /Encoding /UniJIS-UCS2-HW-H /DescendantFonts [5 0 R] >>")
+	object("<< /Type /Font /Subtype /CIDFontType0 /BaseFont /KozMinPr6N-Regular" +
+		" /CIDSystemInfo << /Registry (Adobe) /Ordering (Japan1) /Supplement 6 >>" +
+		" /FontDescriptor 6 0 R /DW 1000 /W [231 325 500] >>")
+	object("<< /Type /FontDescriptor /FontName /KozMinPr6N-Regular /Flags 6" +
+		" /FontBBox [-437 -340 1147 1317] /ItalicAngle 0 /Ascent 880 /Descent -120" +
+		" /CapHeight 742 /StemV 80 >>")
+
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f]"+
+			" /Resources << /Font << /F1 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, firstPage+2*i+1))
+
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		if _, err := zw.Write(page.Bytes()); err != nil {
+			return 0, err
+		}
+		if err := zw.Close(); err != nil {
+			return 0, err
+		}
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", compressed.Len(), compressed.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(out, "trailer\n<< /Size %d /Root 1 0 R /Info 3 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return out.WriteTo(w)
+}
+
+// wrap breaks text into lines no wider than width. Lines break after spaces
+// where possible; runs without spaces, like Japanese text, break anywhere.
+func wrap(text string, size, width float64) []string {
+	runes := []rune(text)
+	if len(runes) == 0 {
+		return []string{""}
+	}
+
+	var lines []string
+	start, lastSpace := 0, -1
+	lineWidth := 0.0
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == ' ' {
+			lastSpace = i
+		}
+		lineWidth += charWidth(runes[i]) * size
+		if lineWidth <= width || i == start {
+			continue
+		}
+
+		end := i
+		if lastSpace > start {
+			end = lastSpace + 1
+		}
+		lines = append(lines, strings.TrimRight(string(runes[start:end]), " "))
+		start, lastSpace = end, -1
+		lineWidth = 0
+		for _, r := range runes[start : i+1] {
+			lineWidth += charWidth(r) * size
+		}
+	}
+	lines = append(lines, string(runes[start:]))
+
+	return lines
+}
+
+// charWidth returns the advance of a character in em
+func charWidth(r rune) float64 {
+	if r >= 0x20 && r < 0x7f {
+		return 0.5
+	}
+	return 1
+}
+
+// encodeText encodes text as hex UTF-16BE for the UniJIS-UCS2 encodings
+func encodeText(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if r > 0xffff || utf16.IsSurrogate(r) {
+			r = replacementChar
+		}
+		if r < 0x20 {
+			r = ' '
+		}
+		fmt.Fprintf(&b, "%04X", r)
+	}
+	return b.String()
+}
@@ -0,0 +1,267 @@
+package recommend
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+)
+
+const (
+	// priorWeight is the number of virtual sessions at the average rating added to
+	// each flavor so a single rating does not dominate
+	priorWeight = 2.0
+	// defaultRating is used as the average rating when no session is rated
+	defaultRating = 3.0
+	// maxMixFlavors limits the flavors combined into mix candidates
+	maxMixFlavors = 40
+	// revisitAfterDays is the number of days after which a flavor can be revisited
+	revisitAfterDays = 30
+)
+
+// Options configures the recommendations
+type Options struct {
+	Exploration float64 // 0 only exploits known ratings, 1 only explores unfamiliar flavors
+	Type        string  // Recommendation type, empty for all types
+	Limit       int
+	Now         time.Time
+}
+
+type flavorProfile struct {
+	name        string
+	uses        int
+	ratedCount  int
+	ratingTotal int
+	lastUsed    time.Time
+	inInventory bool
+}
+
+// affinity returns the rating of the flavor shrunk towards the average rating
+func (f *flavorProfile) affinity(mean float64) float64 {
+	return (float64(f.ratingTotal) + priorWeight*mean) / (float64(f.ratedCount) + priorWeight)
+}
+
+// Recommend suggests mixes and flavors from the session history, ratings and inventory.
+// Everything is computed locally from the given data.
+func Recommend(sessions []models.SessionWithFlavors, inventory []models.InventoryItem, opts Options) *models.RecommendationList {
+	profiles := make(map[string]*flavorProfile)
+	profile := func(name string) *flavorProfile {
+		key := strings.ToLower(strings.TrimSpace(name))
+		p, ok := profiles[key]
+		if !ok {
+			p = &flavorProfile{name: strings.TrimSpace(name)}
+			profiles[key] = p
+		}
+		return p
+	}
+
+	tried := make(map[[2]string]bool)
+	ratingTotal, ratedCount := 0, 0
+
+	for _, session := range sessions {
+		var keys []string
+		seen := make(map[string]bool)
+		for _, flavor := range session.Flavors {
+			if flavor.FlavorName == nil || strings.TrimSpace(*flavor.FlavorName) == "" {
+				continue
+			}
+			key := strings.ToLower(strings.TrimSpace(*flavor.FlavorName))
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			keys = append(keys, key)
+
+			p := profile(*flavor.FlavorName)
+			p.uses++
+			if session.SessionDate.After(p.lastUsed) {
+				p.lastUsed = session.SessionDate
+			}
+			if session.Rating != nil {
+				p.ratedCount++
+				p.ratingTotal += *session.Rating
+			}
+		}
+
+		if session.Rating != nil {
+			ratingTotal += *session.Rating
+			ratedCount++
+		}
+
+		sort.Strings(keys)
+		for i := range keys {
+			for _, b := range keys[i+1:] {
+				tried[[2]string{keys[i], b}] = true
+			}
+		}
+	}
+
+	for _, item := range inventory {
+		if strings.TrimSpace(item.FlavorName) != "" {
+			profile(item.FlavorName).inInventory = true
+		}
+	}
+
+	mean := defaultRating
+	if ratedCount > 0 {
+		mean = float64(ratingTotal) / float64(ratedCount)
+	}
+
+	exploration := math.Max(0, math.Min(1, opts.Exploration))
+	score := func(predicted, novelty float64) float64 {
+		return (1-exploration)*(predicted-1)/4 + exploration*novelty
+	}
+
+	var recommendations []models.Recommendation
+
+	if opts.Type == "" || opts.Type == models.RecommendationMix {
+		candidates := mixCandidates(profiles)
+		for i, a := range candidates {
+			for _, b := range candidates[i+1:] {
+				pair := [2]string{strings.ToLower(a.name), strings.ToLower(b.name)}
+				if pair[0] > pair[1] {
+					pair[0], pair[1] = pair[1], pair[0]
+				}
+				if tried[pair] {
+					continue
+				}
+
+				predicted := (a.affinity(mean) + b.affinity(mean)) / 2
+				novelty := 1 / math.Sqrt(1+float64(a.uses+b.uses))
+				recommendations = append(recommendations, models.Recommendation{
+					Type:            models.RecommendationMix,
+					Flavors:         []string{a.name, b.name},
+					Score:           score(predicted, novelty),
+					PredictedRating: predicted,
+					Novelty:         novelty,
+					Explanation:     mixExplanation(a, b, mean),
+				})
+			}
+		}
+	}
+
+	for _, p := range profiles {
+		switch {
+		case p.uses == 0 && p.inInventory && (opts.Type == "" || opts.Type == models.RecommendationFlavor):
+			recommendations = append(recommendations, models.Recommendation{
+				Type:            models.RecommendationFlavor,
+				Flavors:         []string{p.name},
+				Score:           score(mean, 1),
+				PredictedRating: mean,
+				Novelty:         1,
+				Explanation:     fmt.Sprintf("%s is in your inventory but you haven't smoked it yet", p.name),
+			})
+
+		case p.uses > 0 && p.ratedCount > 0 && (opts.Type == "" || opts.Type == models.RecommendationRevisit):
+			days := int(opts.Now.Sub(p.lastUsed).Hours() / 24)
+			affinity := p.affinity(mean)
+			if days < revisitAfterDays || affinity < mean {
+				continue
+			}
+
+			novelty := float64(days) / float64(days+365)
+			recommendations = append(recommendations, models.Recommendation{
+				Type:            models.RecommendationRevisit,
+				Flavors:         []string{p.name},
+				Score:           score(affinity, novelty),
+				PredictedRating: affinity,
+				Novelty:         novelty,
+				Explanation: fmt.Sprintf("You rated %s %.1f on average over %s but haven't had it in %d days",
+					p.name, float64(p.ratingTotal)/float64(p.ratedCount), sessionCount(p.ratedCount), days),
+			})
+		}
+	}
+
+	sort.Slice(recommendations, func(i, j int) bool {
+		if recommendations[i].Score != recommendations[j].Score {
+			return recommendations[i].Score > recommendations[j].Score
+		}
+		return strings.Join(recommendations[i].Flavors, "+") < strings.Join(recommendations[j].Flavors, "+")
+	})
+
+	if opts.Limit > 0 && opts.Limit < len(recommendations) {
+		recommendations = recommendations[:opts.Limit]
+	}
+	if recommendations == nil {
+		recommendations = []models.Recommendation{}
+	}
+
+	return &models.RecommendationList{
+		Exploration:     exploration,
+		SessionCount:    len(sessions),
+		Recommendations: recommendations,
+	}
+}
+
+// mixCandidates returns the flavors combined into mix candidates: the most used
+// flavors plus everything in the inventory, in name order
+func mixCandidates(profiles map[string]*flavorProfile) []*flavorProfile {
+	var used, candidates []*flavorProfile
+	for _, p := range profiles {
+		if p.inInventory {
+			candidates = append(candidates, p)
+		} else {
+			used = append(used, p)
+		}
+	}
+
+	sort.Slice(used, func(i, j int) bool {
+		if used[i].uses != used[j].uses {
+			return used[i].uses > used[j].uses
+		}
+		return used[i].name < used[j].name
+	})
+	if remaining := maxMixFlavors - len(candidates); remaining < len(used) {
+		if remaining < 0 {
+			remaining = 0
+		}
+		used = used[:remaining]
+	}
+	candidates = append(candidates, used...)
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].name < candidates[j].name
+	})
+	return candidates
+}
+
+// mixExplanation explains a mix suggestion using the better rated of both flavors
+func mixExplanation(a, b *flavorProfile, mean float64) string {
+	best, other := a, b
+	if b.affinity(mean) > a.affinity(mean) {
+		best, other = b, a
+	}
+
+	var explanation string
+	switch {
+	case best.ratedCount > 0:
+		explanation = fmt.Sprintf("You rated %s combos %.1f on average over %s and haven't tried %s+%s",
+			best.name, float64(best.ratingTotal)/float64(best.ratedCount), sessionCount(best.ratedCount), best.name, other.name)
+	case best.uses > 0 && other.uses > 0:
+		explanation = fmt.Sprintf("You often use %s and %s but haven't combined them yet", best.name, other.name)
+	default:
+		explanation = fmt.Sprintf("You haven't tried %s+%s yet", best.name, other.name)
+	}
+
+	var stocked []string
+	for _, p := range []*flavorProfile{best, other} {
+		if p.inInventory {
+			stocked = append(stocked, p.name)
+		}
+	}
+	if len(stocked) > 0 {
+		explanation += fmt.Sprintf(" (%s in your inventory)", strings.Join(stocked, " and "))
+	}
+
+	return explanation
+}
+
+func sessionCount(n int) string {
+	if n == 1 {
+		return "1 session"
+	}
+	return fmt.Sprintf("%d sessions", n)
+}
@@ -0,0 +1,139 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+)
+
+var journalTemplate = texttemplate.Must(texttemplate.New("journal.md").Funcs(texttemplate.FuncMap{
+	"amount":      formatAmount,
+	"duration":    formatDuration,
+	"entryAmount": entryAmount,
+	"entryTitle":  entryTitle,
+	"flavors":     flavorList,
+	"inc":         func(i int) int { return i + 1 },
+	"md":          escapeMarkdown,
+	"monthLabel":  monthLabel,
+	"period":      periodLabel,
+	"quote":       quoteMarkdown,
+	"rating":      formatRating,
+	"stars":       stars,
+}).ParseFS(templateFS, "templates/journal.md"))
+
+// weekdays are the Japanese weekday abbreviations starting on Sunday
+var weekdays = []string{"日", "月", "火", "水", "木", "金", "土"}
+
+// RenderJournalMarkdown writes the journal as a Markdown document
+func RenderJournalMarkdown(w io.Writer, journal *models.Journal) error {
+	return journalTemplate.Execute(w, journal)
+}
+
+// periodLabel describes the date range of a journal
+func periodLabel(journal *models.Journal) string {
+	from, to := "", ""
+	if journal.From != nil {
+		from = *journal.From
+	}
+	if journal.To != nil {
+		to = *journal.To
+	}
+	if from == "" && to == "" {
+		return "全期間"
+	}
+	return strings.TrimSpace(from + " 〜 " + to)
+}
+
+// monthLabel formats a YYYY-MM month in Japanese
+func monthLabel(month string) string {
+	t, err := time.Parse("2006-01", month)
+	if err != nil {
+		return month
+	}
+	return fmt.Sprintf("%d年%d月", t.Year(), int(t.Month()))
+}
+
+// entryTitle formats the local date, time and store of an entry
+func entryTitle(entry models.JournalEntry) string {
+	t := entry.LocalTime
+	title := fmt.Sprintf("%d/%d (%s) %s", int(t.Month()), t.Day(), weekdays[t.Weekday()], t.Format("15:04"))
+	if entry.StoreName != nil && *entry.StoreName != "" {
+		title += " " + *entry.StoreName
+	}
+	return title
+}
+
+// flavorList joins the flavors of an entry in order with their brands
+func flavorList(entry models.JournalEntry) string {
+	var names []string
+	for _, flavor := range entry.Flavors {
+		if flavor.FlavorName == nil || *flavor.FlavorName == "" {
+			continue
+		}
+		name := *flavor.FlavorName
+		if flavor.Brand != nil && *flavor.Brand != "" {
+			name += " (" + *flavor.Brand + ")"
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, " / ")
+}
+
+// entryAmount formats the amount of an entry in its own currency
+func entryAmount(entry models.JournalEntry) string {
+	if entry.Amount == nil {
+		return ""
+	}
+	amount := formatAmount(float64(*entry.Amount))
+	if entry.Currency != nil {
+		amount += " " + *entry.Currency
+	}
+	return amount
+}
+
+// formatDuration formats minutes as hours and minutes
+func formatDuration(minutes int) string {
+	if minutes < 60 {
+		return fmt.Sprintf("%d分", minutes)
+	}
+	if minutes%60 == 0 {
+		return fmt.Sprintf("%d時間", minutes/60)
+	}
+	return fmt.Sprintf("%d時間%d分", minutes/60, minutes%60)
+}
+
+// stars draws a 1-5 rating
+func stars(rating int) string {
+	if rating < 0 {
+		rating = 0
+	}
+	if rating > 5 {
+		rating = 5
+	}
+	return strings.Repeat("★", rating) + strings.Repeat("☆", 5-rating)
+}
+
+// markdownEscaper escapes characters with a meaning in Markdown
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"<", `\<`, ">", `\>`, "#", `\#`, "|", `\|`,
+)
+
+// escapeMarkdown escapes user text for a single Markdown line
+func escapeMarkdown(text string) string {
+	text = strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", " "), "\n", " ")
+	return markdownEscaper.Replace(text)
+}
+
+// quoteMarkdown formats notes as a Markdown block quote
+func quoteMarkdown(text string) string {
+	lines := strings.Split(strings.ReplaceAll(strings.TrimSpace(text), "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("> "+markdownEscaper.Replace(line), " ")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,116 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+	"github.com/toof-jp/shisha-log/backend/internal/pdf"
+)
+
+// Text styles of the PDF journal
+var (
+	pdfTitle   = pdf.Style{Size: 20}
+	pdfHeading = pdf.Style{Size: 14}
+	pdfEntry   = pdf.Style{Size: 11}
+	pdfBody    = pdf.Style{Size: 9.5, Indent: 12}
+	pdfNotes   = pdf.Style{Size: 9.5, Indent: 24, Gray: 0.35}
+	pdfMuted   = pdf.Style{Size: 9.5, Gray: 0.45}
+)
+
+// RenderJournalPDF writes the journal as an A4 PDF with the summary on the
+// first page and every month starting on a new page
+func RenderJournalPDF(w io.Writer, journal *models.Journal) error {
+	doc := pdf.New("シーシャ ジャーナル " + periodLabel(journal))
+
+	doc.Text("シーシャ ジャーナル", pdfTitle)
+	doc.Text(fmt.Sprintf("%s (%s)", periodLabel(journal), journal.Timezone), pdfMuted)
+	doc.Space(12)
+
+	summary := journal.Summary
+	doc.Text("サマリー", pdfHeading)
+	doc.Rule()
+	doc.Text(fmt.Sprintf("セッション数: %d", summary.SessionCount), pdfBody)
+	doc.Text(fmt.Sprintf("吸った日数: %d", summary.ActiveDays), pdfBody)
+	spend := fmt.Sprintf("合計金額: %s %s", formatAmount(summary.Spend), journal.Currency)
+	if summary.UnconvertedSessions > 0 {
+		spend += fmt.Sprintf(" (換算できなかったセッション: %d)", summary.UnconvertedSessions)
+	}
+	doc.Text(spend, pdfBody)
+	if summary.AverageRating != nil {
+		doc.Text("平均評価: "+formatRating(summary.AverageRating), pdfBody)
+	}
+	if summary.TotalMinutes > 0 {
+		doc.Text("合計時間: "+formatDuration(summary.TotalMinutes), pdfBody)
+	}
+
+	topList := func(title string, names []string, counts []int) {
+		if len(names) == 0 {
+			return
+		}
+		doc.Space(6)
+		doc.Text(title, pdfEntry)
+		for i, name := range names {
+			doc.Text(fmt.Sprintf("%d. %s (%d 回)", i+1, name, counts[i]), pdfBody)
+		}
+	}
+	var names []string
+	var counts []int
+	for _, flavor := range summary.TopFlavors {
+		names, counts = append(names, flavor.FlavorName), append(counts, flavor.Count)
+	}
+	topList("よく吸ったフレーバー", names, counts)
+	names, counts = nil, nil
+	for _, store := range summary.TopStores {
+		names, counts = append(names, store.StoreName), append(counts, store.Count)
+	}
+	topList("よく行ったお店", names, counts)
+	names, counts = nil, nil
+	for _, creator := range summary.TopCreators {
+		names, counts = append(names, creator.Creator), append(counts, creator.Count)
+	}
+	topList("よく作ってもらった人", names, counts)
+
+	for _, month := range journal.Months {
+		doc.PageBreak()
+		doc.Text(monthLabel(month.Month), pdfHeading)
+		doc.Text(fmt.Sprintf("%d セッション / 合計 %s %s", month.SessionCount, formatAmount(month.Spend), journal.Currency), pdfMuted)
+		doc.Rule()
+
+		for _, entry := range month.Entries {
+			doc.Space(4)
+			doc.Text(entryTitle(entry), pdfEntry)
+
+			var details []string
+			if entry.MixName != nil && *entry.MixName != "" {
+				details = append(details, "ミックス: "+*entry.MixName)
+			}
+			if flavors := flavorList(entry); flavors != "" {
+				details = append(details, "フレーバー: "+flavors)
+			}
+			if entry.Creator != nil && *entry.Creator != "" {
+				details = append(details, "作り手: "+*entry.Creator)
+			}
+			if entry.Amount != nil {
+				details = append(details, "金額: "+entryAmount(entry))
+			}
+			if entry.DurationMinutes != nil {
+				details = append(details, "時間: "+formatDuration(*entry.DurationMinutes))
+			}
+			if entry.Rating != nil {
+				details = append(details, "評価: "+stars(*entry.Rating))
+			}
+			for _, detail := range details {
+				doc.Text(detail, pdfBody)
+			}
+
+			if entry.Notes != nil && strings.TrimSpace(*entry.Notes) != "" {
+				doc.Text(strings.TrimSpace(*entry.Notes), pdfNotes)
+			}
+		}
+	}
+
+	_, err := doc.WriteTo(w)
+	return err
+}
@@ -0,0 +1,55 @@
+package report
+
+import (
+	"embed"
+	"html/template"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+)
+
+//go:embed templates/*
+var templateFS embed.FS
+
+var yearTemplate = template.Must(template.New("year.html").Funcs(template.FuncMap{
+	"amount":   formatAmount,
+	"barWidth": barWidth,
+	"rating":   formatRating,
+}).ParseFS(templateFS, "templates/year.html"))
+
+// RenderYearHTML writes the year-in-review report as a standalone HTML page
+func RenderYearHTML(w io.Writer, report *models.YearReport) error {
+	return yearTemplate.Execute(w, report)
+}
+
+// formatAmount rounds an amount and groups the thousands with commas
+func formatAmount(amount float64) string {
+	digits := strconv.FormatInt(int64(math.Round(amount)), 10)
+
+	sign := ""
+	if digits[0] == '-' {
+		sign, digits = "-", digits[1:]
+	}
+	for i := len(digits) - 3; i > 0; i -= 3 {
+		digits = digits[:i] + "," + digits[i:]
+	}
+	return sign + digits
+}
+
+// formatRating formats an average rating with one decimal
+func formatRating(rating *float64) string {
+	if rating == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*rating, 'f', 1, 64)
+}
+
+// barWidth returns the width of a month bar in percent of the busiest month
+func barWidth(count int, busiest *models.YearReportMonth) int {
+	if busiest == nil || busiest.SessionCount == 0 {
+		return 0
+	}
+	return count * 100 / busiest.SessionCount
+}
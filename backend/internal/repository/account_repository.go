@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+)
+
+// AccountRepository writes account imports in a single transaction
+type AccountRepository struct {
+	db *sql.DB
+}
+
+func NewAccountRepository(db *sql.DB) *AccountRepository {
+	return &AccountRepository{db: db}
+}
+
+// Import inserts the rows of an account import. With replace all existing
+// data of the user is deleted first and the deleted entities are counted.
+// Either everything is written or, on any error, nothing is.
+func (r *AccountRepository) Import(ctx context.Context, userID uuid.UUID, rows *models.AccountImportRows, replace bool) (models.AccountImportCounts, error) {
+	var deleted models.AccountImportCounts
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return deleted, err
+	}
+	defer tx.Rollback()
+
+	if replace {
+		// Sessions first so no references remain to the other entities;
+		// flavors and equipment links are removed with their parents
+		for _, step := range []struct {
+			table string
+			count *int
+		}{
+			{"shisha_sessions", &deleted.Sessions},
+			{"budgets", &deleted.Budgets},
+			{"recipes", &deleted.Recipes},
+			{"tobacco_inventory", &deleted.Inventory},
+			{"equipment", &deleted.Equipment},
+		} {
+			result, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM public.%s WHERE user_id = $1", step.table), userID)
+			if err != nil {
+				return deleted, fmt.Errorf("delete %s: %w", step.table, err)
+			}
+			affected, err := result.RowsAffected()
+			if err != nil {
+				return deleted, err
+			}
+			*step.count = int(affected)
+		}
+	}
+
+	if rows.DefaultCurrency != "" {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE users
+			SET default_currency = $2, updated_at = NOW()
+			WHERE id = $1
+		`, userID, rows.DefaultCurrency)
+		if err != nil {
+			return deleted, err
+		}
+	}
+
+	inserts := []struct {
+		table   string
+		columns string
+		rows    any
+		count   int
+	}{
+		{"equipment", "id, user_id, category, name, brand, notes", rows.Equipment, len(rows.Equipment)},
+		{"tobacco_inventory", "id, user_id, flavor_name, brand, weight_grams, purchase_date, price, opened_date, notes", rows.Inventory, len(rows.Inventory)},
+		{"recipes", "id, user_id, name, notes", rows.Recipes, len(rows.Recipes)},
+		{"recipe_flavors", "id, recipe_id, flavor_name, brand, ratio, flavor_order", rows.RecipeFlavors, len(rows.RecipeFlavors)},
+		{"budgets", "id, user_id, name, period, amount, currency, store_name, timezone", rows.Budgets, len(rows.Budgets)},
+		{"shisha_sessions", "id, user_id, created_by, session_date, store_name, notes, order_details, mix_name, creator, amount, currency, duration_minutes, rating, recipe_id", rows.Sessions, len(rows.Sessions)},
+		{"session_flavors", "id, session_id, flavor_name, brand, flavor_order, inventory_id, grams", rows.SessionFlavors, len(rows.SessionFlavors)},
+		{"session_equipment", "session_id, equipment_id", rows.SessionEquipment, len(rows.SessionEquipment)},
+	}
+	for _, insert := range inserts {
+		if insert.count == 0 {
+			continue
+		}
+		if err := insertJSON(ctx, tx, insert.table, insert.columns, insert.rows); err != nil {
+			return deleted, fmt.Errorf("insert %s: %w", insert.table, err)
+		}
+	}
+
+	return deleted, tx.Commit()
+}
+
+// insertJSON inserts rows, a slice of insert models, with one statement.
+// Columns missing from the JSON of a row are inserted as NULL.
+func insertJSON(ctx context.Context, tx *sql.Tx, table, columns string, rows any) error {
+	data, err := json.Marshal(rows)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("INSERT INTO public.%s (%s) SELECT %s FROM json_populate_recordset(NULL::public.%s, $1::json)",
+		table, columns, columns, table)
+	_, err = tx.ExecContext(ctx, query, string(data))
+	return err
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+	postgrest "github.com/supabase-community/postgrest-go"
+	"github.com/supabase-community/supabase-go"
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+)
+
+const budgetColumns = "id,user_id,name,period,amount,currency,store_name,timezone,last_breached_period,created_at,updated_at"
+
+type BudgetRepository struct {
+	client *supabase.Client
+}
+
+func NewBudgetRepository(client *supabase.Client) *BudgetRepository {
+	return &BudgetRepository{client: client}
+}
+
+func (r *BudgetRepository) Create(ctx context.Context, budget *models.Budget) (*models.Budget, error) {
+	insert := models.BudgetInsert{
+		ID:        uuid.New().String(),
+		UserID:    budget.UserID,
+		Name:      budget.Name,
+		Period:    budget.Period,
+		Amount:    budget.Amount,
+		Currency:  budget.Currency,
+		StoreName: budget.StoreName,
+		Timezone:  budget.Timezone,
+	}
+
+	_, _, err := r.client.From("budgets").
+		Insert(insert, false, "", "", "").
+		Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch again to get proper timestamps
+	return r.GetByID(ctx, insert.ID)
+}
+
+func (r *BudgetRepository) GetByID(ctx context.Context, id string) (*models.Budget, error) {
+	data, _, err := r.client.From("budgets").
+		Select(budgetColumns, "", false).
+		Eq("id", id).
+		Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var budgets []models.Budget
+	if err := json.Unmarshal(data, &budgets); err != nil {
+		return nil, err
+	}
+
+	if len(budgets) == 0 {
+		return nil, errors.New("budget not found")
+	}
+
+	return &budgets[0], nil
+}
+
+func (r *BudgetRepository) GetByUserID(ctx context.Context, userID string) ([]models.Budget, error) {
+	data, _, err := r.client.From("budgets").
+		Select(budgetColumns, "", false).
+		Eq("user_id", userID).
+		Order("name", &postgrest.OrderOpts{Ascending: true}).
+		Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	budgets := []models.Budget{}
+	if err := json.Unmarshal(data, &budgets); err != nil {
+		return nil, err
+	}
+
+	return budgets, nil
+}
+
+func (r *BudgetRepository) Update(ctx context.Context, id string, update *models.UpdateBudgetRequest) error {
+	updateMap := make(map[string]interface{})
+
+	if update.Name != nil {
+		updateMap["name"] = *update.Name
+	}
+	if update.Period != nil {
+		updateMap["period"] = *update.Period
+	}
+	if update.Amount != nil {
+		updateMap["amount"] = *update.Amount
+	}
+	if update.Currency != nil {
+		updateMap["currency"] = *update.Currency
+	}
+	if update.StoreName != nil {
+		if *update.StoreName == "" {
+			updateMap["store_name"] = nil
+		} else {
+			updateMap["store_name"] = *update.StoreName
+		}
+	}
+	if update.Timezone != nil {
+		updateMap["timezone"] = *update.Timezone
+	}
+
+	if len(updateMap) == 0 {
+		return nil
+	}
+
+	// A changed rule may be breached again in the current period
+	updateMap["last_breached_period"] = nil
+
+	_, _, err := r.client.From("budgets").
+		Update(updateMap, "", "").
+		Eq("id", id).
+		Execute()
+
+	return err
+}
+
+// MarkBreached records the period in which a budget was last breached. The
+// update only applies when the period was not recorded yet, so concurrent
+// checks report a breach once; the result tells whether this call recorded it.
+func (r *BudgetRepository) MarkBreached(ctx context.Context, id string, period string) (bool, error) {
+	data, _, err := r.client.From("budgets").
+		Update(map[string]interface{}{"last_breached_period": period}, "representation", "").
+		Eq("id", id).
+		Or("last_breached_period.is.null,last_breached_period.neq."+period, "").
+		Execute()
+	if err != nil {
+		return false, err
+	}
+
+	var updated []models.Budget
+	if err := json.Unmarshal(data, &updated); err != nil {
+		return false, err
+	}
+
+	return len(updated) > 0, nil
+}
+
+func (r *BudgetRepository) Delete(ctx context.Context, id string) error {
+	_, _, err := r.client.From("budgets").
+		Delete("", "").
+		Eq("id", id).
+		Execute()
+
+	return err
+}
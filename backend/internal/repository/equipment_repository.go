@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+	postgrest "github.com/supabase-community/postgrest-go"
+	"github.com/supabase-community/supabase-go"
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+)
+
+const equipmentColumns = "id,user_id,category,name,brand,notes,created_at,updated_at"
+
+type EquipmentRepository struct {
+	client *supabase.Client
+}
+
+func NewEquipmentRepository(client *supabase.Client) *EquipmentRepository {
+	return &EquipmentRepository{client: client}
+}
+
+func (r *EquipmentRepository) Create(ctx context.Context, equipment *models.Equipment) (*models.Equipment, error) {
+	insert := models.EquipmentInsert{
+		ID:       uuid.New().String(),
+		UserID:   equipment.UserID,
+		Category: equipment.Category,
+		Name:     equipment.Name,
+		Brand:    equipment.Brand,
+		Notes:    equipment.Notes,
+	}
+
+	_, _, err := r.client.From("equipment").
+		Insert(insert, false, "", "", "").
+		Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch again to get proper timestamps
+	return r.GetByID(ctx, insert.ID)
+}
+
+func (r *EquipmentRepository) GetByID(ctx context.Context, id string) (*models.Equipment, error) {
+	data, _, err := r.client.From("equipment").
+		Select(equipmentColumns, "", false).
+		Eq("id", id).
+		Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var equipment []models.Equipment
+	if err := json.Unmarshal(data, &equipment); err != nil {
+		return nil, err
+	}
+
+	if len(equipment) == 0 {
+		return nil, errors.New("equipment not found")
+	}
+
+	return &equipment[0], nil
+}
+
+func (r *EquipmentRepository) GetByUserID(ctx context.Context, userID string, category string) ([]models.Equipment, error) {
+	query := r.client.From("equipment").
+		Select(equipmentColumns, "", false).
+		Eq("user_id", userID)
+
+	if category != "" {
+		query = query.Eq("category", category)
+	}
+
+	data, _, err := query.
+		Order("category", &postgrest.OrderOpts{Ascending: true}).
+		Order("name", &postgrest.OrderOpts{Ascending: true}).
+		Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	equipment := []models.Equipment{}
+	if err := json.Unmarshal(data, &equipment); err != nil {
+		return nil, err
+	}
+
+	return equipment, nil
+}
+
+func (r *EquipmentRepository) Update(ctx context.Context, id string, update *models.UpdateEquipmentRequest) error {
+	updateMap := make(map[string]interface{})
+
+	if update.Category != nil {
+		updateMap["category"] = *update.Category
+	}
+	if update.Name != nil {
+		updateMap["name"] = *update.Name
+	}
+	if update.Brand != nil {
+		if *update.Brand == "" {
+			updateMap["brand"] = nil
+		} else {
+			updateMap["brand"] = *update.Brand
+		}
+	}
+	if update.Notes != nil {
+		if *update.Notes == "" {
+			updateMap["notes"] = nil
+		} else {
+			updateMap["notes"] = *update.Notes
+		}
+	}
+
+	if len(updateMap) == 0 {
+		return nil
+	}
+
+	_, _, err := r.client.From("equipment").
+		Update(updateMap, "", "").
+		Eq("id", id).
+		Execute()
+
+	return err
+}
+
+func (r *EquipmentRepository) Delete(ctx context.Context, id string) error {
+	// Session links are removed by ON DELETE CASCADE
+	_, _, err := r.client.From("equipment").
+		Delete("", "").
+		Eq("id", id).
+		Execute()
+
+	return err
+}
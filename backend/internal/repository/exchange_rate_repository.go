@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+
+	postgrest "github.com/supabase-community/postgrest-go"
+	"github.com/supabase-community/supabase-go"
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+)
+
+const exchangeRateColumns = "id,rate_date,base_currency,quote_currency,rate,source,created_at"
+
+// exchangeRatePageSize is the number of rates fetched per request
+const exchangeRatePageSize = 1000
+
+type ExchangeRateRepository struct {
+	client *supabase.Client
+}
+
+func NewExchangeRateRepository(client *supabase.Client) *ExchangeRateRepository {
+	return &ExchangeRateRepository{client: client}
+}
+
+// Upsert inserts exchange rates, replacing existing rates for the same date and currency pair
+func (r *ExchangeRateRepository) Upsert(ctx context.Context, rates []models.ExchangeRateInsert) error {
+	if len(rates) == 0 {
+		return nil
+	}
+
+	_, _, err := r.client.From("exchange_rates").
+		Upsert(rates, "rate_date,base_currency,quote_currency", "", "").
+		Execute()
+
+	return err
+}
+
+// GetAll returns every exchange rate, optionally limited to one currency pair
+func (r *ExchangeRateRepository) GetAll(ctx context.Context, base, quote string) ([]models.ExchangeRate, error) {
+	rates := []models.ExchangeRate{}
+
+	// Page through the table since PostgREST caps the number of rows per request
+	for offset := 0; ; offset += exchangeRatePageSize {
+		query := r.client.From("exchange_rates").
+			Select(exchangeRateColumns, "", false)
+
+		if base != "" {
+			query = query.Eq("base_currency", base)
+		}
+		if quote != "" {
+			query = query.Eq("quote_currency", quote)
+		}
+
+		data, _, err := query.
+			Order("rate_date", &postgrest.OrderOpts{Ascending: true}).
+			Order("id", &postgrest.OrderOpts{Ascending: true}).
+			Range(offset, offset+exchangeRatePageSize-1, "").
+			Execute()
+
+		if err != nil {
+			return nil, err
+		}
+
+		var page []models.ExchangeRate
+		if err := json.Unmarshal(data, &page); err != nil {
+			return nil, err
+		}
+
+		rates = append(rates, page...)
+		if len(page) < exchangeRatePageSize {
+			break
+		}
+	}
+
+	return rates, nil
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+)
+
+type FeedTokenRepository struct {
+	db *sql.DB
+}
+
+func NewFeedTokenRepository(db *sql.DB) *FeedTokenRepository {
+	return &FeedTokenRepository{db: db}
+}
+
+// Replace revokes the active token of the user and stores a new one
+func (r *FeedTokenRepository) Replace(userID uuid.UUID, token string) (*models.FeedToken, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		UPDATE feed_tokens
+		SET revoked_at = NOW()
+		WHERE user_id = $1 AND revoked_at IS NULL
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	feedToken := &models.FeedToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Token:     token,
+		CreatedAt: time.Now(),
+	}
+	_, err = tx.Exec(`
+		INSERT INTO feed_tokens (id, user_id, token, created_at)
+		VALUES ($1, $2, $3, $4)
+	`, feedToken.ID, feedToken.UserID, feedToken.Token, feedToken.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return feedToken, nil
+}
+
+// GetActiveByUserID returns the active token of the user
+func (r *FeedTokenRepository) GetActiveByUserID(userID uuid.UUID) (*models.FeedToken, error) {
+	query := `
+		SELECT id, user_id, token, created_at, last_used_at, revoked_at
+		FROM feed_tokens
+		WHERE user_id = $1 AND revoked_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	return r.scan(r.db.QueryRow(query, userID))
+}
+
+// GetActiveByToken returns the token if it has not been revoked
+func (r *FeedTokenRepository) GetActiveByToken(token string) (*models.FeedToken, error) {
+	query := `
+		SELECT id, user_id, token, created_at, last_used_at, revoked_at
+		FROM feed_tokens
+		WHERE token = $1 AND revoked_at IS NULL
+	`
+
+	return r.scan(r.db.QueryRow(query, token))
+}
+
+func (r *FeedTokenRepository) UpdateLastUsedAt(id uuid.UUID) error {
+	query := `
+		UPDATE feed_tokens
+		SET last_used_at = NOW()
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(query, id)
+	return err
+}
+
+// RevokeAllByUserID revokes every token of the user. It reports whether a token was active.
+func (r *FeedTokenRepository) RevokeAllByUserID(userID uuid.UUID) (bool, error) {
+	query := `
+		UPDATE feed_tokens
+		SET revoked_at = NOW()
+		WHERE user_id = $1 AND revoked_at IS NULL
+	`
+
+	result, err := r.db.Exec(query, userID)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+func (r *FeedTokenRepository) scan(row *sql.Row) (*models.FeedToken, error) {
+	feedToken := &models.FeedToken{}
+	err := row.Scan(&feedToken.ID, &feedToken.UserID, &feedToken.Token,
+		&feedToken.CreatedAt, &feedToken.LastUsedAt, &feedToken.RevokedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return feedToken, nil
+}
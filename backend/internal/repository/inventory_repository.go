@@ -0,0 +1,324 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	postgrest "github.com/supabase-community/postgrest-go"
+	"github.com/supabase-community/supabase-go"
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+)
+
+const inventoryColumns = "id,user_id,flavor_name,brand,weight_grams,purchase_date,price,opened_date,notes,created_at,updated_at"
+
+type InventoryRepository struct {
+	client *supabase.Client
+}
+
+func NewInventoryRepository(client *supabase.Client) *InventoryRepository {
+	return &InventoryRepository{client: client}
+}
+
+func (r *InventoryRepository) Create(ctx context.Context, item *models.InventoryItem) (*models.InventoryItem, error) {
+	insert := models.InventoryInsert{
+		ID:           uuid.New().String(),
+		UserID:       item.UserID,
+		FlavorName:   item.FlavorName,
+		Brand:        item.Brand,
+		WeightGrams:  item.WeightGrams,
+		PurchaseDate: item.PurchaseDate,
+		Price:        item.Price,
+		OpenedDate:   item.OpenedDate,
+		Notes:        item.Notes,
+	}
+
+	_, _, err := r.client.From("tobacco_inventory").
+		Insert(insert, false, "", "", "").
+		Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch again to get proper timestamps
+	return r.GetByID(ctx, insert.ID)
+}
+
+func (r *InventoryRepository) GetByID(ctx context.Context, id string) (*models.InventoryItem, error) {
+	data, _, err := r.client.From("tobacco_inventory").
+		Select(inventoryColumns, "", false).
+		Eq("id", id).
+		Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var items []models.InventoryItem
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+
+	if len(items) == 0 {
+		return nil, errors.New("inventory item not found")
+	}
+
+	return &items[0], nil
+}
+
+func (r *InventoryRepository) GetByUserID(ctx context.Context, userID string) ([]models.InventoryItem, error) {
+	data, _, err := r.client.From("tobacco_inventory").
+		Select(inventoryColumns, "", false).
+		Eq("user_id", userID).
+		Order("created_at", &postgrest.OrderOpts{Ascending: true}).
+		Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	items := []models.InventoryItem{}
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (r *InventoryRepository) Update(ctx context.Context, id string, update *models.UpdateInventoryRequest) error {
+	updateMap := make(map[string]interface{})
+
+	if update.FlavorName != nil {
+		updateMap["flavor_name"] = *update.FlavorName
+	}
+	if update.Brand != nil {
+		if *update.Brand == "" {
+			updateMap["brand"] = nil
+		} else {
+			updateMap["brand"] = *update.Brand
+		}
+	}
+	if update.WeightGrams != nil {
+		updateMap["weight_grams"] = *update.WeightGrams
+	}
+	if update.PurchaseDate != nil {
+		if *update.PurchaseDate == "" {
+			updateMap["purchase_date"] = nil
+		} else {
+			updateMap["purchase_date"] = *update.PurchaseDate
+		}
+	}
+	if update.Price != nil {
+		updateMap["price"] = *update.Price
+	}
+	if update.OpenedDate != nil {
+		if *update.OpenedDate == "" {
+			updateMap["opened_date"] = nil
+		} else {
+			updateMap["opened_date"] = *update.OpenedDate
+		}
+	}
+	if update.Notes != nil {
+		if *update.Notes == "" {
+			updateMap["notes"] = nil
+		} else {
+			updateMap["notes"] = *update.Notes
+		}
+	}
+
+	if len(updateMap) == 0 {
+		return nil
+	}
+
+	_, _, err := r.client.From("tobacco_inventory").
+		Update(updateMap, "", "").
+		Eq("id", id).
+		Execute()
+
+	return err
+}
+
+func (r *InventoryRepository) Delete(ctx context.Context, id string) error {
+	// Session flavors keep their data; inventory_id is cleared by ON DELETE SET NULL
+	_, _, err := r.client.From("tobacco_inventory").
+		Delete("", "").
+		Eq("id", id).
+		Execute()
+
+	return err
+}
+
+// GetConsumption returns every draw of tobacco from the given inventory items
+func (r *InventoryRepository) GetConsumption(ctx context.Context, inventoryIDs []string) ([]models.InventoryConsumption, error) {
+	if len(inventoryIDs) == 0 {
+		return []models.InventoryConsumption{}, nil
+	}
+
+	data, _, err := r.client.From("session_flavors").
+		Select("session_id,inventory_id,grams", "", false).
+		In("inventory_id", inventoryIDs).
+		Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var flavors []struct {
+		SessionID   string   `json:"session_id"`
+		InventoryID string   `json:"inventory_id"`
+		Grams       *float64 `json:"grams"`
+	}
+	if err := json.Unmarshal(data, &flavors); err != nil {
+		return nil, err
+	}
+
+	if len(flavors) == 0 {
+		return []models.InventoryConsumption{}, nil
+	}
+
+	// Look up the session dates to compute usage rates
+	sessionIDs := make([]string, 0, len(flavors))
+	for _, flavor := range flavors {
+		sessionIDs = append(sessionIDs, flavor.SessionID)
+	}
+
+	data, _, err = r.client.From("shisha_sessions").
+		Select("id,session_date", "", false).
+		In("id", sessionIDs).
+		Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions []struct {
+		ID          string    `json:"id"`
+		SessionDate time.Time `json:"session_date"`
+	}
+	if err := json.Unmarshal(data, &sessions); err != nil {
+		return nil, err
+	}
+
+	sessionDates := make(map[string]time.Time, len(sessions))
+	for _, session := range sessions {
+		sessionDates[session.ID] = session.SessionDate
+	}
+
+	consumption := make([]models.InventoryConsumption, 0, len(flavors))
+	for _, flavor := range flavors {
+		if flavor.Grams == nil {
+			continue
+		}
+		consumption = append(consumption, models.InventoryConsumption{
+			InventoryID: flavor.InventoryID,
+			SessionID:   flavor.SessionID,
+			Grams:       *flavor.Grams,
+			SessionDate: sessionDates[flavor.SessionID],
+		})
+	}
+
+	return consumption, nil
+}
+
+// GetReport computes remaining stock, usage rates and projected run-out dates for
+// all inventory items of a user. Dates are computed in the given timezone.
+func (r *InventoryRepository) GetReport(ctx context.Context, userID string, lowStockGrams float64, timezone string) (*models.InventoryReport, error) {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		// Fallback to UTC if timezone is invalid
+		loc = time.UTC
+	}
+
+	items, err := r.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	inventoryIDs := make([]string, len(items))
+	for i, item := range items {
+		inventoryIDs[i] = item.ID
+	}
+
+	consumption, err := r.GetConsumption(ctx, inventoryIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	consumptionMap := make(map[string][]models.InventoryConsumption)
+	for _, draw := range consumption {
+		consumptionMap[draw.InventoryID] = append(consumptionMap[draw.InventoryID], draw)
+	}
+
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	report := &models.InventoryReport{
+		Items:         make([]models.InventoryItemStatus, 0, len(items)),
+		LowStock:      []models.InventoryItemStatus{},
+		LowStockGrams: lowStockGrams,
+	}
+
+	for _, item := range items {
+		status := models.InventoryItemStatus{InventoryItem: item}
+
+		// Usage starts when the package was opened, or at the first draw otherwise
+		var firstUse, lastUse time.Time
+		if item.OpenedDate != nil {
+			if opened, err := time.ParseInLocation("2006-01-02", *item.OpenedDate, loc); err == nil {
+				firstUse = opened
+			}
+		}
+
+		for _, draw := range consumptionMap[item.ID] {
+			status.ConsumedGrams += draw.Grams
+			status.SessionCount++
+
+			drawDate := draw.SessionDate.In(loc)
+			if firstUse.IsZero() || drawDate.Before(firstUse) {
+				firstUse = drawDate
+			}
+			if drawDate.After(lastUse) {
+				lastUse = drawDate
+			}
+		}
+
+		status.RemainingGrams = math.Max(item.WeightGrams-status.ConsumedGrams, 0)
+		if !lastUse.IsZero() {
+			lastUsed := lastUse.Format("2006-01-02")
+			status.LastUsedDate = &lastUsed
+		}
+
+		// Project the run-out date from the average daily usage since first use
+		if status.ConsumedGrams > 0 {
+			days := math.Max(today.Sub(firstUse).Hours()/24, 1)
+			dailyUsage := status.ConsumedGrams / days
+			status.DailyUsageGrams = &dailyUsage
+
+			runOut := today
+			if status.RemainingGrams > 0 {
+				runOut = today.AddDate(0, 0, int(math.Ceil(status.RemainingGrams/dailyUsage)))
+			}
+			runOutDate := runOut.Format("2006-01-02")
+			status.ProjectedRunOutDate = &runOutDate
+		}
+
+		status.LowStock = status.RemainingGrams <= lowStockGrams
+		report.TotalRemainingGrams += status.RemainingGrams
+		report.Items = append(report.Items, status)
+		if status.LowStock {
+			report.LowStock = append(report.LowStock, status)
+		}
+	}
+
+	// Sort low stock items by remaining grams ascending
+	sort.Slice(report.LowStock, func(i, j int) bool {
+		return report.LowStock[i].RemainingGrams < report.LowStock[j].RemainingGrams
+	})
+
+	return report, nil
+}
@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+	postgrest "github.com/supabase-community/postgrest-go"
+	"github.com/supabase-community/supabase-go"
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+)
+
+const (
+	recipeColumns       = "id,user_id,name,notes,created_at,updated_at"
+	recipeFlavorColumns = "id,recipe_id,flavor_name,brand,ratio,flavor_order,created_at"
+)
+
+type RecipeRepository struct {
+	client *supabase.Client
+}
+
+func NewRecipeRepository(client *supabase.Client) *RecipeRepository {
+	return &RecipeRepository{client: client}
+}
+
+func (r *RecipeRepository) Create(ctx context.Context, recipe *models.Recipe, flavors []models.CreateRecipeFlavorRequest) (*models.RecipeWithFlavors, error) {
+	insert := models.RecipeInsert{
+		ID:     uuid.New().String(),
+		UserID: recipe.UserID,
+		Name:   recipe.Name,
+		Notes:  recipe.Notes,
+	}
+
+	_, _, err := r.client.From("recipes").
+		Insert(insert, false, "", "", "").
+		Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.insertFlavors(insert.ID, flavors); err != nil {
+		// Supabase doesn't support transactions via REST API
+		return nil, err
+	}
+
+	// Fetch again to get proper timestamps
+	return r.GetByID(ctx, insert.ID)
+}
+
+func (r *RecipeRepository) GetByID(ctx context.Context, id string) (*models.RecipeWithFlavors, error) {
+	data, _, err := r.client.From("recipes").
+		Select(recipeColumns, "", false).
+		Eq("id", id).
+		Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var recipes []models.Recipe
+	if err := json.Unmarshal(data, &recipes); err != nil {
+		return nil, err
+	}
+
+	if len(recipes) == 0 {
+		return nil, errors.New("recipe not found")
+	}
+
+	result, err := r.attachFlavors(recipes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result[0], nil
+}
+
+func (r *RecipeRepository) GetByUserID(ctx context.Context, userID string) ([]models.RecipeWithFlavors, error) {
+	data, _, err := r.client.From("recipes").
+		Select(recipeColumns, "", false).
+		Eq("user_id", userID).
+		Order("name", &postgrest.OrderOpts{Ascending: true}).
+		Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var recipes []models.Recipe
+	if err := json.Unmarshal(data, &recipes); err != nil {
+		return nil, err
+	}
+
+	return r.attachFlavors(recipes)
+}
+
+func (r *RecipeRepository) Update(ctx context.Context, id string, update *models.UpdateRecipeRequest) error {
+	updateMap := make(map[string]interface{})
+
+	if update.Name != nil {
+		updateMap["name"] = *update.Name
+	}
+	if update.Notes != nil {
+		if *update.Notes == "" {
+			updateMap["notes"] = nil
+		} else {
+			updateMap["notes"] = *update.Notes
+		}
+	}
+
+	if len(updateMap) > 0 {
+		_, _, err := r.client.From("recipes").
+			Update(updateMap, "", "").
+			Eq("id", id).
+			Execute()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	// Replace flavors if provided
+	if update.Flavors != nil {
+		_, _, err := r.client.From("recipe_flavors").
+			Delete("", "").
+			Eq("recipe_id", id).
+			Execute()
+
+		if err != nil {
+			return err
+		}
+
+		if err := r.insertFlavors(id, *update.Flavors); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *RecipeRepository) Delete(ctx context.Context, id string) error {
+	// Recipe flavors are removed by ON DELETE CASCADE and sessions keep their flavors
+	_, _, err := r.client.From("recipes").
+		Delete("", "").
+		Eq("id", id).
+		Execute()
+
+	return err
+}
+
+func (r *RecipeRepository) insertFlavors(recipeID string, flavors []models.CreateRecipeFlavorRequest) error {
+	var inserts []models.RecipeFlavorInsert
+	for i, flavor := range flavors {
+		inserts = append(inserts, models.RecipeFlavorInsert{
+			ID:          uuid.New().String(),
+			RecipeID:    recipeID,
+			FlavorName:  flavor.FlavorName,
+			Brand:       flavor.Brand,
+			Ratio:       flavor.Ratio,
+			FlavorOrder: i + 1, // Order starts from 1
+		})
+	}
+
+	if len(inserts) == 0 {
+		return nil
+	}
+
+	_, _, err := r.client.From("recipe_flavors").
+		Insert(inserts, false, "", "", "").
+		Execute()
+
+	return err
+}
+
+func (r *RecipeRepository) attachFlavors(recipes []models.Recipe) ([]models.RecipeWithFlavors, error) {
+	result := make([]models.RecipeWithFlavors, len(recipes))
+	if len(recipes) == 0 {
+		return result, nil
+	}
+
+	recipeIDs := make([]string, len(recipes))
+	for i, recipe := range recipes {
+		recipeIDs[i] = recipe.ID
+	}
+
+	data, _, err := r.client.From("recipe_flavors").
+		Select(recipeFlavorColumns, "", false).
+		In("recipe_id", recipeIDs).
+		Order("flavor_order", &postgrest.OrderOpts{Ascending: true}).
+		Execute()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var flavors []models.RecipeFlavor
+	if err := json.Unmarshal(data, &flavors); err != nil {
+		return nil, err
+	}
+
+	flavorMap := make(map[string][]models.RecipeFlavor)
+	for _, flavor := range flavors {
+		flavorMap[flavor.RecipeID] = append(flavorMap[flavor.RecipeID], flavor)
+	}
+
+	for i, recipe := range recipes {
+		recipeFlavors := flavorMap[recipe.ID]
+		if recipeFlavors == nil {
+			recipeFlavors = []models.RecipeFlavor{}
+		}
+		result[i] = models.RecipeWithFlavors{
+			Recipe:  recipe,
+			Flavors: recipeFlavors,
+		}
+	}
+
+	return result, nil
+}
@@ -14,6 +14,12 @@ import (
 	"github.com/toof-jp/shisha-log/backend/internal/models"
 )
 
+// sessionColumns lists the shisha_sessions columns selected by the repository
+const sessionColumns = "id,user_id,created_by,session_date,store_name,notes,order_details,mix_name,creator,amount,currency,duration_minutes,rating,recipe_id,created_at,updated_at"
+
+// flavorColumns lists the session_flavors columns selected by the repository
+const flavorColumns = "id,session_id,flavor_name,brand,flavor_order,inventory_id,grams,created_at"
+
 type SessionRepository struct {
 	client *supabase.Client
 }
@@ -22,23 +28,27 @@ func NewSessionRepository(client *supabase.Client) *SessionRepository {
 	return &SessionRepository{client: client}
 }
 
-func (r *SessionRepository) Create(ctx context.Context, session *models.ShishaSession, flavors []models.CreateFlavorRequest) (*models.SessionWithFlavors, error) {
+func (r *SessionRepository) Create(ctx context.Context, session *models.ShishaSession, flavors []models.CreateFlavorRequest, equipmentIDs []string) (*models.SessionWithFlavors, error) {
 	// Start transaction by creating session first
 	sessionID := uuid.New().String()
 	session.ID = sessionID
 
 	// Create insert struct without timestamps
 	insertSession := models.SessionInsert{
-		ID:           sessionID,
-		UserID:       session.UserID,
-		CreatedBy:    session.CreatedBy,
-		SessionDate:  session.SessionDate,
-		StoreName:    session.StoreName,
-		Notes:        session.Notes,
-		OrderDetails: session.OrderDetails,
-		MixName:      session.MixName,
-		Creator:      session.Creator,
-		Amount:       session.Amount,
+		ID:              sessionID,
+		UserID:          session.UserID,
+		CreatedBy:       session.CreatedBy,
+		SessionDate:     session.SessionDate,
+		StoreName:       session.StoreName,
+		Notes:           session.Notes,
+		OrderDetails:    session.OrderDetails,
+		MixName:         session.MixName,
+		Creator:         session.Creator,
+		Amount:          session.Amount,
+		Currency:        session.Currency,
+		DurationMinutes: session.DurationMinutes,
+		Rating:          session.Rating,
+		RecipeID:        session.RecipeID,
 	}
 
 	data, _, err := r.client.From("shisha_sessions").
@@ -70,6 +80,8 @@ func (r *SessionRepository) Create(ctx context.Context, session *models.ShishaSe
 			FlavorName:  flavor.FlavorName,
 			Brand:       flavor.Brand,
 			FlavorOrder: i + 1, // Order starts from 1
+			InventoryID: flavor.InventoryID,
+			Grams:       flavor.Grams,
 		}
 		flavorInserts = append(flavorInserts, flavorInsert)
 	}
@@ -85,6 +97,11 @@ func (r *SessionRepository) Create(ctx context.Context, session *models.ShishaSe
 		}
 	}
 
+	// Link equipment used in the session
+	if err := r.insertSessionEquipment(createdSession.ID, equipmentIDs); err != nil {
+		return nil, err
+	}
+
 	// Fetch the session again to get proper timestamps
 	// This is a workaround for Supabase Go client timestamp issue
 	freshSession, err := r.GetByID(ctx, createdSession.ID)
@@ -99,7 +116,7 @@ func (r *SessionRepository) GetByID(ctx context.Context, id string) (*models.Ses
 	var sessions []models.ShishaSession
 
 	data, _, err := r.client.From("shisha_sessions").
-		Select("id,user_id,created_by,session_date,store_name,notes,order_details,mix_name,creator,amount,created_at,updated_at", "exact", false).
+		Select(sessionColumns, "exact", false).
 		Eq("id", id).
 		Execute()
 
@@ -121,7 +138,7 @@ func (r *SessionRepository) GetByID(ctx context.Context, id string) (*models.Ses
 	// Get flavors
 	var flavors []models.SessionFlavor
 	data, _, err = r.client.From("session_flavors").
-		Select("id,session_id,flavor_name,brand,flavor_order,created_at", "exact", false).
+		Select(flavorColumns, "exact", false).
 		Eq("session_id", id).
 		Order("flavor_order", nil).
 		Execute()
@@ -135,17 +152,22 @@ func (r *SessionRepository) GetByID(ctx context.Context, id string) (*models.Ses
 		return nil, err
 	}
 
-	return &models.SessionWithFlavors{
+	result := []models.SessionWithFlavors{{
 		ShishaSession: session,
 		Flavors:       flavors,
-	}, nil
+	}}
+	if err := r.attachEquipment(result); err != nil {
+		return nil, err
+	}
+
+	return &result[0], nil
 }
 
 func (r *SessionRepository) GetByUserID(ctx context.Context, userID string, limit, offset int) ([]models.SessionWithFlavors, error) {
 	var sessions []models.ShishaSession
 
 	query := r.client.From("shisha_sessions").
-		Select("id,user_id,created_by,session_date,store_name,notes,order_details,mix_name,creator,amount,created_at,updated_at", "exact", false).
+		Select(sessionColumns, "exact", false).
 		Eq("user_id", userID).
 		Order("created_at", &postgrest.OrderOpts{Ascending: false}) // Order by created_at descending
 
@@ -167,6 +189,11 @@ func (r *SessionRepository) GetByUserID(ctx context.Context, userID string, limi
 		return nil, err
 	}
 
+	return r.withFlavors(sessions)
+}
+
+// withFlavors loads the flavors and equipment of the sessions, keeping their order
+func (r *SessionRepository) withFlavors(sessions []models.ShishaSession) ([]models.SessionWithFlavors, error) {
 	// Get flavors for all sessions
 	sessionIDs := make([]string, len(sessions))
 	for i, session := range sessions {
@@ -176,7 +203,7 @@ func (r *SessionRepository) GetByUserID(ctx context.Context, userID string, limi
 	var allFlavors []models.SessionFlavor
 	if len(sessionIDs) > 0 {
 		data, _, err := r.client.From("session_flavors").
-			Select("id,session_id,flavor_name,brand,flavor_order,created_at", "exact", false).
+			Select(flavorColumns, "exact", false).
 			In("session_id", sessionIDs).
 			Order("flavor_order", nil).
 			Execute()
@@ -205,6 +232,10 @@ func (r *SessionRepository) GetByUserID(ctx context.Context, userID string, limi
 		}
 	}
 
+	if err := r.attachEquipment(result); err != nil {
+		return nil, err
+	}
+
 	return result, nil
 }
 
@@ -252,6 +283,34 @@ func (r *SessionRepository) Update(ctx context.Context, id string, update *model
 	if update.Amount != nil {
 		updateMap["amount"] = *update.Amount
 	}
+	if update.Currency != nil {
+		if *update.Currency == "" {
+			updateMap["currency"] = nil
+		} else {
+			updateMap["currency"] = *update.Currency
+		}
+	}
+	if update.DurationMinutes != nil {
+		if *update.DurationMinutes == 0 {
+			updateMap["duration_minutes"] = nil
+		} else {
+			updateMap["duration_minutes"] = *update.DurationMinutes
+		}
+	}
+	if update.Rating != nil {
+		if *update.Rating == 0 {
+			updateMap["rating"] = nil
+		} else {
+			updateMap["rating"] = *update.Rating
+		}
+	}
+	if update.RecipeID != nil {
+		if *update.RecipeID == "" {
+			updateMap["recipe_id"] = nil
+		} else {
+			updateMap["recipe_id"] = *update.RecipeID
+		}
+	}
 
 	// Debug log
 	// fmt.Printf("Updating session %s with data: %+v\n", id, updateMap)
@@ -289,6 +348,8 @@ func (r *SessionRepository) Update(ctx context.Context, id string, update *model
 				FlavorName:  flavor.FlavorName,
 				Brand:       flavor.Brand,
 				FlavorOrder: i + 1, // Order starts from 1
+				InventoryID: flavor.InventoryID,
+				Grams:       flavor.Grams,
 			}
 			flavorInserts = append(flavorInserts, flavorInsert)
 		}
@@ -304,6 +365,88 @@ func (r *SessionRepository) Update(ctx context.Context, id string, update *model
 		}
 	}
 
+	// Replace equipment links if provided
+	if update.EquipmentIDs != nil {
+		_, _, err := r.client.From("session_equipment").
+			Delete("", "").
+			Eq("session_id", id).
+			Execute()
+
+		if err != nil {
+			return err
+		}
+
+		if err := r.insertSessionEquipment(id, *update.EquipmentIDs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// insertSessionEquipment links the given equipment to a session
+func (r *SessionRepository) insertSessionEquipment(sessionID string, equipmentIDs []string) error {
+	var inserts []models.SessionEquipmentInsert
+	seen := make(map[string]bool)
+	for _, equipmentID := range equipmentIDs {
+		if equipmentID == "" || seen[equipmentID] {
+			continue
+		}
+		seen[equipmentID] = true
+		inserts = append(inserts, models.SessionEquipmentInsert{
+			SessionID:   sessionID,
+			EquipmentID: equipmentID,
+		})
+	}
+
+	if len(inserts) == 0 {
+		return nil
+	}
+
+	_, _, err := r.client.From("session_equipment").
+		Insert(inserts, false, "", "", "").
+		Execute()
+
+	return err
+}
+
+// attachEquipment fills EquipmentIDs for the given sessions
+func (r *SessionRepository) attachEquipment(sessions []models.SessionWithFlavors) error {
+	sessionIDs := make([]string, len(sessions))
+	for i, session := range sessions {
+		sessionIDs[i] = session.ID
+		sessions[i].EquipmentIDs = []string{}
+	}
+
+	if len(sessionIDs) == 0 {
+		return nil
+	}
+
+	data, _, err := r.client.From("session_equipment").
+		Select("session_id,equipment_id", "", false).
+		In("session_id", sessionIDs).
+		Execute()
+
+	if err != nil {
+		return err
+	}
+
+	var links []models.SessionEquipmentInsert
+	if err := json.Unmarshal(data, &links); err != nil {
+		return err
+	}
+
+	equipmentMap := make(map[string][]string)
+	for _, link := range links {
+		equipmentMap[link.SessionID] = append(equipmentMap[link.SessionID], link.EquipmentID)
+	}
+
+	for i := range sessions {
+		if ids, ok := equipmentMap[sessions[i].ID]; ok {
+			sessions[i].EquipmentIDs = ids
+		}
+	}
+
 	return nil
 }
 
@@ -355,51 +498,8 @@ func (r *SessionRepository) GetFlavorStats(ctx context.Context, userID string) (
 		return nil, err
 	}
 
-	// Count main flavors (flavor_order = 1) and all flavors
-	mainFlavorMap := make(map[string]int)
-	allFlavorMap := make(map[string]int)
-
-	for _, session := range sessions {
-		for _, flavor := range session.Flavors {
-			if flavor.FlavorName != nil && *flavor.FlavorName != "" {
-				// Count all flavors
-				allFlavorMap[*flavor.FlavorName]++
-
-				// Count only main flavors (flavor_order = 1)
-				if flavor.FlavorOrder == 1 {
-					mainFlavorMap[*flavor.FlavorName]++
-				}
-			}
-		}
-	}
-
-	// Convert maps to sorted slices
-	mainFlavors := make([]models.FlavorCount, 0, len(mainFlavorMap))
-	for name, count := range mainFlavorMap {
-		mainFlavors = append(mainFlavors, models.FlavorCount{
-			FlavorName: name,
-			Count:      count,
-		})
-	}
-
-	allFlavors := make([]models.FlavorCount, 0, len(allFlavorMap))
-	for name, count := range allFlavorMap {
-		allFlavors = append(allFlavors, models.FlavorCount{
-			FlavorName: name,
-			Count:      count,
-		})
-	}
-
-	// Sort by count descending
-	sort.Slice(mainFlavors, func(i, j int) bool {
-		return mainFlavors[i].Count > mainFlavors[j].Count
-	})
-
-	sort.Slice(allFlavors, func(i, j int) bool {
-		return allFlavors[i].Count > allFlavors[j].Count
-	})
-
 	// No limit - return all flavors
+	mainFlavors, allFlavors := countFlavors(sessions)
 
 	return &models.FlavorStats{
 		MainFlavors: mainFlavors,
@@ -518,6 +618,10 @@ func (r *SessionRepository) GetByDate(ctx context.Context, userID string, date s
 		sessionsWithFlavors = append(sessionsWithFlavors, sessionWithFlavors)
 	}
 
+	if err := r.attachEquipment(sessionsWithFlavors); err != nil {
+		return nil, err
+	}
+
 	return sessionsWithFlavors, nil
 }
 
@@ -589,6 +693,10 @@ func (r *SessionRepository) GetByDateRange(ctx context.Context, userID string, s
 		sessionsWithFlavors = append(sessionsWithFlavors, sessionWithFlavors)
 	}
 
+	if err := r.attachEquipment(sessionsWithFlavors); err != nil {
+		return nil, err
+	}
+
 	return sessionsWithFlavors, nil
 }
 
@@ -599,32 +707,9 @@ func (r *SessionRepository) GetStoreStats(ctx context.Context, userID string) (*
 		return nil, err
 	}
 
-	// Count sessions by store (excluding empty store names)
-	storeMap := make(map[string]int)
-	for _, session := range sessions {
-		if session.StoreName != nil && *session.StoreName != "" {
-			storeMap[*session.StoreName]++
-		}
-	}
-
-	// Convert to sorted slice
-	stores := make([]models.StoreCount, 0, len(storeMap))
-	for name, count := range storeMap {
-		stores = append(stores, models.StoreCount{
-			StoreName: name,
-			Count:     count,
-		})
-	}
-
-	// Sort by count descending
-	sort.Slice(stores, func(i, j int) bool {
-		return stores[i].Count > stores[j].Count
-	})
-
 	// No limit - return all stores
-
 	return &models.StoreStats{
-		Stores: stores,
+		Stores: countStores(sessions),
 	}, nil
 }
 
@@ -635,32 +720,9 @@ func (r *SessionRepository) GetCreatorStats(ctx context.Context, userID string)
 		return nil, err
 	}
 
-	// Count sessions by creator (excluding empty creator names)
-	creatorMap := make(map[string]int)
-	for _, session := range sessions {
-		if session.Creator != nil && *session.Creator != "" {
-			creatorMap[*session.Creator]++
-		}
-	}
-
-	// Convert to sorted slice
-	creators := make([]models.CreatorCount, 0, len(creatorMap))
-	for name, count := range creatorMap {
-		creators = append(creators, models.CreatorCount{
-			Creator: name,
-			Count:   count,
-		})
-	}
-
-	// Sort by count descending
-	sort.Slice(creators, func(i, j int) bool {
-		return creators[i].Count > creators[j].Count
-	})
-
 	// No limit - return all creators
-
 	return &models.CreatorStats{
-		Creators: creators,
+		Creators: countCreators(sessions),
 	}, nil
 }
 
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+)
+
+// batchInsertSize is the number of rows sent per insert request
+const batchInsertSize = 500
+
+// CreateBatch inserts many sessions with their flavors using bulk inserts.
+// flavors[i] holds the flavors of sessions[i]. Sessions without an ID get a new one.
+// It returns the IDs of the sessions in order. The REST API has no transactions, so
+// when an insert fails the sessions inserted so far are deleted again, which also
+// removes their flavors, and either all sessions are created or none.
+func (r *SessionRepository) CreateBatch(ctx context.Context, sessions []models.ShishaSession, flavors [][]models.CreateFlavorRequest) ([]string, error) {
+	ids := make([]string, len(sessions))
+	sessionInserts := make([]models.SessionBatchInsert, len(sessions))
+	var flavorInserts []models.FlavorInsert
+
+	for i, session := range sessions {
+		if session.ID == "" {
+			session.ID = uuid.New().String()
+		}
+		ids[i] = session.ID
+
+		sessionInserts[i] = models.SessionBatchInsert{
+			ID:              session.ID,
+			UserID:          session.UserID,
+			CreatedBy:       session.CreatedBy,
+			SessionDate:     session.SessionDate,
+			StoreName:       session.StoreName,
+			Notes:           session.Notes,
+			OrderDetails:    session.OrderDetails,
+			MixName:         session.MixName,
+			Creator:         session.Creator,
+			Amount:          session.Amount,
+			Currency:        session.Currency,
+			DurationMinutes: session.DurationMinutes,
+			Rating:          session.Rating,
+			RecipeID:        session.RecipeID,
+		}
+
+		if i >= len(flavors) {
+			continue
+		}
+		for j, flavor := range flavors[i] {
+			flavorInserts = append(flavorInserts, models.FlavorInsert{
+				ID:          uuid.New().String(),
+				SessionID:   session.ID,
+				FlavorName:  flavor.FlavorName,
+				Brand:       flavor.Brand,
+				FlavorOrder: j + 1, // Order starts from 1
+				InventoryID: flavor.InventoryID,
+				Grams:       flavor.Grams,
+			})
+		}
+	}
+
+	inserted := 0
+	for start := 0; start < len(sessionInserts); start += batchInsertSize {
+		end := start + batchInsertSize
+		if end > len(sessionInserts) {
+			end = len(sessionInserts)
+		}
+
+		_, _, err := r.client.From("shisha_sessions").
+			Insert(sessionInserts[start:end], false, "", "", "").
+			Execute()
+
+		if err != nil {
+			return nil, r.rollbackBatch(ids[:inserted], err)
+		}
+		inserted = end
+	}
+
+	for start := 0; start < len(flavorInserts); start += batchInsertSize {
+		end := start + batchInsertSize
+		if end > len(flavorInserts) {
+			end = len(flavorInserts)
+		}
+
+		_, _, err := r.client.From("session_flavors").
+			Insert(flavorInserts[start:end], false, "", "", "").
+			Execute()
+
+		if err != nil {
+			return nil, r.rollbackBatch(ids, err)
+		}
+	}
+
+	return ids, nil
+}
+
+// rollbackBatch deletes the sessions of a failed batch and returns the original error
+func (r *SessionRepository) rollbackBatch(ids []string, cause error) error {
+	for start := 0; start < len(ids); start += batchInsertSize {
+		end := start + batchInsertSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		_, _, err := r.client.From("shisha_sessions").
+			Delete("", "").
+			In("id", ids[start:end]).
+			Execute()
+
+		if err != nil {
+			return fmt.Errorf("%w (removing the partially created sessions also failed: %v)", cause, err)
+		}
+	}
+
+	return cause
+}
+
+// DeleteByUserID deletes all sessions of the user
+func (r *SessionRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	// Flavors and equipment links are removed by ON DELETE CASCADE
+	_, _, err := r.client.From("shisha_sessions").
+		Delete("", "").
+		Eq("user_id", userID).
+		Execute()
+
+	return err
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+)
+
+type brandCounts struct {
+	sessions    int
+	uses        int
+	mainUses    int
+	flavorCount map[string]int
+}
+
+// GetBrandStats computes per-brand usage and brand diversity per bucket in the timezone
+func (r *SessionRepository) GetBrandStats(ctx context.Context, userID string, bucket string, timezone string, flavorLimit int) (*models.BrandStats, error) {
+	loc := loadLocation(timezone)
+
+	// Get all sessions for the user
+	sessions, err := r.GetByUserID(ctx, userID, 10000, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &models.BrandStats{
+		Timezone:  loc.String(),
+		Bucket:    bucket,
+		Brands:    []models.BrandUsage{},
+		Diversity: []models.BrandDiversityBucket{},
+	}
+
+	brands := make(map[string]*brandCounts)
+	periodSessions := make(map[string]int)
+	periodBrandUses := make(map[string]map[string]int)
+	totalMainUses := 0
+	var first, last time.Time
+
+	for _, session := range sessions {
+		localTime := session.SessionDate.In(loc)
+		if first.IsZero() || localTime.Before(first) {
+			first = localTime
+		}
+		if localTime.After(last) {
+			last = localTime
+		}
+
+		period := bucketLabel(bucketStart(localTime, bucket), bucket)
+		periodSessions[period]++
+		if periodBrandUses[period] == nil {
+			periodBrandUses[period] = make(map[string]int)
+		}
+
+		sessionBrands := make(map[string]bool)
+		for _, flavor := range session.Flavors {
+			if flavor.Brand == nil || strings.TrimSpace(*flavor.Brand) == "" {
+				stats.UnbrandedUses++
+				continue
+			}
+			brand := strings.TrimSpace(*flavor.Brand)
+
+			counts, ok := brands[brand]
+			if !ok {
+				counts = &brandCounts{flavorCount: make(map[string]int)}
+				brands[brand] = counts
+			}
+			counts.uses++
+			if flavor.FlavorOrder == 1 {
+				counts.mainUses++
+				totalMainUses++
+			}
+			if flavor.FlavorName != nil && *flavor.FlavorName != "" {
+				counts.flavorCount[*flavor.FlavorName]++
+			}
+			if !sessionBrands[brand] {
+				sessionBrands[brand] = true
+				counts.sessions++
+			}
+
+			periodBrandUses[period][brand]++
+		}
+	}
+
+	for brand, counts := range brands {
+		usage := models.BrandUsage{
+			Brand:           brand,
+			SessionCount:    counts.sessions,
+			FlavorCount:     counts.uses,
+			MainFlavorCount: counts.mainUses,
+			TopFlavors:      []models.FlavorCount{},
+		}
+		if totalMainUses > 0 {
+			usage.MainFlavorShare = float64(counts.mainUses) / float64(totalMainUses)
+		}
+
+		for name, count := range counts.flavorCount {
+			usage.TopFlavors = append(usage.TopFlavors, models.FlavorCount{FlavorName: name, Count: count})
+		}
+		sort.Slice(usage.TopFlavors, func(i, j int) bool {
+			if usage.TopFlavors[i].Count != usage.TopFlavors[j].Count {
+				return usage.TopFlavors[i].Count > usage.TopFlavors[j].Count
+			}
+			return usage.TopFlavors[i].FlavorName < usage.TopFlavors[j].FlavorName
+		})
+		if flavorLimit < len(usage.TopFlavors) {
+			usage.TopFlavors = usage.TopFlavors[:flavorLimit]
+		}
+
+		stats.Brands = append(stats.Brands, usage)
+	}
+
+	sort.Slice(stats.Brands, func(i, j int) bool {
+		if stats.Brands[i].SessionCount != stats.Brands[j].SessionCount {
+			return stats.Brands[i].SessionCount > stats.Brands[j].SessionCount
+		}
+		return stats.Brands[i].Brand < stats.Brands[j].Brand
+	})
+
+	if len(sessions) == 0 {
+		return stats, nil
+	}
+
+	// Report every period from the first to the last session, including empty ones
+	end := bucketStart(last, bucket)
+	for start := bucketStart(first, bucket); !start.After(end); start = nextBucket(start, bucket) {
+		period := bucketLabel(start, bucket)
+		uses := periodBrandUses[period]
+
+		stats.Diversity = append(stats.Diversity, models.BrandDiversityBucket{
+			Period:         period,
+			SessionCount:   periodSessions[period],
+			DistinctBrands: len(uses),
+			Entropy:        entropy(uses),
+		})
+	}
+
+	return stats, nil
+}
+
+// entropy returns the Shannon entropy in bits of a count distribution
+func entropy(counts map[string]int) float64 {
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+	if total == 0 {
+		return 0
+	}
+
+	result := 0.0
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		result -= p * math.Log2(p)
+	}
+	return result
+}
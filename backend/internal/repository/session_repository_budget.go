@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/toof-jp/shisha-log/backend/internal/currency"
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+)
+
+// budgetPeriod returns the start and end of the budget period containing now
+// and its label
+func budgetPeriod(budget models.Budget, now time.Time) (time.Time, time.Time, string) {
+	bucket := BucketMonth
+	if budget.Period == models.BudgetPeriodWeekly {
+		bucket = BucketWeek
+	}
+
+	start := bucketStart(now.In(loadLocation(budget.Timezone)), bucket)
+	return start, nextBucket(start, bucket), bucketLabel(start, bucket)
+}
+
+// GetBudgetStatuses computes the spend of the current period of each budget at now.
+// Amounts are converted into the budget currency using the exchange rate of the session date.
+func (r *SessionRepository) GetBudgetStatuses(ctx context.Context, userID string, budgets []models.Budget, now time.Time, converter *currency.Converter, defaultCurrency string) ([]models.BudgetStatus, error) {
+	statuses := make([]models.BudgetStatus, 0, len(budgets))
+	if len(budgets) == 0 {
+		return statuses, nil
+	}
+
+	// Fetch the sessions of every current period at once
+	var from, to time.Time
+	for _, budget := range budgets {
+		start, end, _ := budgetPeriod(budget, now)
+		if from.IsZero() || start.Before(from) {
+			from = start
+		}
+		if end.After(to) {
+			to = end
+		}
+	}
+
+	sessions, err := r.GetByDateRange(ctx, userID, from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, budget := range budgets {
+		start, end, label := budgetPeriod(budget, now)
+		amounts := currency.NewAmountConverter(converter, defaultCurrency, budget.Currency)
+
+		status := models.BudgetStatus{
+			Budget:      budget,
+			Period:      label,
+			PeriodStart: start,
+			PeriodEnd:   end,
+		}
+
+		for _, session := range sessions {
+			if session.Amount == nil || session.SessionDate.Before(start) || !session.SessionDate.Before(end) {
+				continue
+			}
+			if budget.StoreName != nil && (session.StoreName == nil || *session.StoreName != *budget.StoreName) {
+				continue
+			}
+
+			amount, ok := amounts.SessionAmount(&session.ShishaSession, session.SessionDate.In(start.Location()))
+			if !ok {
+				status.UnconvertedSessions++
+				continue
+			}
+			status.Spent += amount
+			status.SessionCount++
+		}
+
+		status.Remaining = budget.Amount - status.Spent
+		status.PercentUsed = status.Spent / budget.Amount * 100
+		status.Breached = status.Spent > budget.Amount
+
+		// Project linearly over the elapsed part of the period, counting at least one day
+		// so a single session early in the period does not explode the projection
+		elapsed := now.Sub(start)
+		if elapsed < 24*time.Hour {
+			elapsed = 24 * time.Hour
+		}
+		total := end.Sub(start)
+		if elapsed > total {
+			elapsed = total
+		}
+		status.Projected = status.Spent * float64(total) / float64(elapsed)
+		status.ProjectedBreach = status.Projected > budget.Amount
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
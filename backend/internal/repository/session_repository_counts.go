@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+)
+
+// countFlavors counts how often each flavor was used, in all positions and as
+// the main flavor (flavor_order 1). Both lists are sorted by count descending, then name.
+func countFlavors(sessions []models.SessionWithFlavors) (main, all []models.FlavorCount) {
+	mainCounts := make(map[string]int)
+	allCounts := make(map[string]int)
+
+	for _, session := range sessions {
+		for _, flavor := range session.Flavors {
+			if flavor.FlavorName == nil || *flavor.FlavorName == "" {
+				continue
+			}
+			allCounts[*flavor.FlavorName]++
+			if flavor.FlavorOrder == 1 {
+				mainCounts[*flavor.FlavorName]++
+			}
+		}
+	}
+
+	toFlavorCounts := func(counts map[string]int) []models.FlavorCount {
+		result := make([]models.FlavorCount, 0, len(counts))
+		for _, entry := range topCounts(counts, len(counts)) {
+			result = append(result, models.FlavorCount{FlavorName: entry.name, Count: entry.count})
+		}
+		return result
+	}
+
+	return toFlavorCounts(mainCounts), toFlavorCounts(allCounts)
+}
+
+// countStores counts the sessions of each store, sorted by count descending, then name
+func countStores(sessions []models.SessionWithFlavors) []models.StoreCount {
+	counts := make(map[string]int)
+	for _, session := range sessions {
+		if session.StoreName != nil && *session.StoreName != "" {
+			counts[*session.StoreName]++
+		}
+	}
+
+	stores := make([]models.StoreCount, 0, len(counts))
+	for _, entry := range topCounts(counts, len(counts)) {
+		stores = append(stores, models.StoreCount{StoreName: entry.name, Count: entry.count})
+	}
+	return stores
+}
+
+// countCreators counts the sessions of each creator, sorted by count descending, then name
+func countCreators(sessions []models.SessionWithFlavors) []models.CreatorCount {
+	counts := make(map[string]int)
+	for _, session := range sessions {
+		if session.Creator != nil && *session.Creator != "" {
+			counts[*session.Creator]++
+		}
+	}
+
+	creators := make([]models.CreatorCount, 0, len(counts))
+	for _, entry := range topCounts(counts, len(counts)) {
+		creators = append(creators, models.CreatorCount{Creator: entry.name, Count: entry.count})
+	}
+	return creators
+}
+
+// firstN returns at most the first n items
+func firstN[T any](items []T, n int) []T {
+	if n < len(items) {
+		return items[:n]
+	}
+	return items
+}
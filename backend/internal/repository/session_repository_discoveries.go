@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+)
+
+// discoveryTracker records first and last appearances of the items of one kind
+type discoveryTracker struct {
+	items map[string]*models.Discovery
+}
+
+func newDiscoveryTracker() *discoveryTracker {
+	return &discoveryTracker{items: make(map[string]*models.Discovery)}
+}
+
+// see records the use of an item in a session and reports whether it is new.
+// Sessions must be seen in chronological order.
+func (t *discoveryTracker) see(name string, session models.SessionWithFlavors, date string) bool {
+	item, ok := t.items[name]
+	if !ok {
+		t.items[name] = &models.Discovery{
+			Name:           name,
+			FirstSeenAt:    session.SessionDate,
+			FirstSeenDate:  date,
+			FirstSessionID: session.ID,
+			LastSeenAt:     session.SessionDate,
+			SessionCount:   1,
+		}
+		return true
+	}
+	item.LastSeenAt = session.SessionDate
+	item.SessionCount++
+	return false
+}
+
+// sorted returns the items in order of first appearance
+func (t *discoveryTracker) sorted() []models.Discovery {
+	result := make([]models.Discovery, 0, len(t.items))
+	for _, item := range t.items {
+		result = append(result, *item)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].FirstSeenAt.Equal(result[j].FirstSeenAt) {
+			return result[i].FirstSeenAt.Before(result[j].FirstSeenAt)
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// GetDiscoveryStats returns when each flavor, brand, store and creator first appeared and
+// the number of novel and repeat items per month in the timezone
+func (r *SessionRepository) GetDiscoveryStats(ctx context.Context, userID string, timezone string) (*models.DiscoveryStats, error) {
+	loc := loadLocation(timezone)
+
+	// Get all sessions for the user
+	sessions, err := r.GetByUserID(ctx, userID, 10000, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Walk sessions from the oldest so the first sighting is the discovery
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].SessionDate.Before(sessions[j].SessionDate)
+	})
+
+	flavors := newDiscoveryTracker()
+	brands := newDiscoveryTracker()
+	stores := newDiscoveryTracker()
+	creators := newDiscoveryTracker()
+
+	stats := &models.DiscoveryStats{
+		Timezone: loc.String(),
+		Monthly:  []models.DiscoveryMonth{},
+	}
+
+	months := make(map[string]*models.DiscoveryMonth)
+	// Distinct items seen per month, so an item used twice in a month counts once
+	monthItems := make(map[string]map[string]bool)
+
+	for _, session := range sessions {
+		localTime := session.SessionDate.In(loc)
+		date := localTime.Format("2006-01-02")
+		label := bucketLabel(bucketStart(localTime, BucketMonth), BucketMonth)
+
+		month, ok := months[label]
+		if !ok {
+			month = &models.DiscoveryMonth{Month: label}
+			months[label] = month
+			monthItems[label] = make(map[string]bool)
+		}
+		month.SessionCount++
+		seen := monthItems[label]
+
+		// count records a use in the month once per distinct item
+		count := func(kind string, name string, isNew bool, newCount, repeatCount *int) {
+			key := kind + "\x00" + name
+			if seen[key] {
+				return
+			}
+			seen[key] = true
+			if isNew {
+				*newCount++
+			} else {
+				*repeatCount++
+			}
+		}
+
+		sessionFlavors := make(map[string]bool)
+		sessionBrands := make(map[string]bool)
+		for _, flavor := range session.Flavors {
+			if flavor.FlavorName != nil && *flavor.FlavorName != "" && !sessionFlavors[*flavor.FlavorName] {
+				sessionFlavors[*flavor.FlavorName] = true
+				isNew := flavors.see(*flavor.FlavorName, session, date)
+				count("flavor", *flavor.FlavorName, isNew, &month.NewFlavors, &month.RepeatFlavors)
+			}
+			if flavor.Brand != nil && strings.TrimSpace(*flavor.Brand) != "" {
+				brand := strings.TrimSpace(*flavor.Brand)
+				if !sessionBrands[brand] {
+					sessionBrands[brand] = true
+					isNew := brands.see(brand, session, date)
+					count("brand", brand, isNew, &month.NewBrands, &month.RepeatBrands)
+				}
+			}
+		}
+		if session.StoreName != nil && *session.StoreName != "" {
+			isNew := stores.see(*session.StoreName, session, date)
+			count("store", *session.StoreName, isNew, &month.NewStores, &month.RepeatStores)
+		}
+		if session.Creator != nil && *session.Creator != "" {
+			isNew := creators.see(*session.Creator, session, date)
+			count("creator", *session.Creator, isNew, &month.NewCreators, &month.RepeatCreators)
+		}
+	}
+
+	stats.Flavors = flavors.sorted()
+	stats.Brands = brands.sorted()
+	stats.Stores = stores.sorted()
+	stats.Creators = creators.sorted()
+
+	if len(sessions) == 0 {
+		return stats, nil
+	}
+
+	// Report every month from the first to the last session, including empty ones
+	end := bucketStart(sessions[len(sessions)-1].SessionDate.In(loc), BucketMonth)
+	for start := bucketStart(sessions[0].SessionDate.In(loc), BucketMonth); !start.After(end); start = nextBucket(start, BucketMonth) {
+		label := bucketLabel(start, BucketMonth)
+		month, ok := months[label]
+		if !ok {
+			month = &models.DiscoveryMonth{Month: label}
+		}
+		if distinct := month.NewFlavors + month.RepeatFlavors; distinct > 0 {
+			month.NoveltyRate = float64(month.NewFlavors) / float64(distinct)
+		}
+		stats.Monthly = append(stats.Monthly, *month)
+	}
+
+	return stats, nil
+}
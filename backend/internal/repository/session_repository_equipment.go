@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+)
+
+func (r *SessionRepository) GetEquipmentStats(ctx context.Context, userID string, equipment []models.Equipment) (*models.EquipmentStats, error) {
+	// Get all sessions for the user
+	sessions, err := r.GetByUserID(ctx, userID, 10000, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every piece of equipment is reported, unused items too
+	ids := make([]string, len(equipment))
+	for i, item := range equipment {
+		ids[i] = item.ID
+	}
+	usages := tallyUsage(sessions, ids, func(session models.SessionWithFlavors) []string {
+		return session.EquipmentIDs
+	})
+
+	stats := make([]models.EquipmentUsage, 0, len(equipment))
+	for _, item := range equipment {
+		stats = append(stats, models.EquipmentUsage{
+			EquipmentID: item.ID,
+			Category:    item.Category,
+			Name:        item.Name,
+			Brand:       item.Brand,
+			Usage:       usages[item.ID],
+		})
+	}
+	sortByUsage(stats, func(u models.EquipmentUsage) models.Usage { return u.Usage }, func(u models.EquipmentUsage) string { return u.Name })
+
+	return &models.EquipmentStats{
+		Equipment: stats,
+	}, nil
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	postgrest "github.com/supabase-community/postgrest-go"
+
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+)
+
+// exportBatchSize is the number of sessions fetched per request while exporting
+const exportBatchSize = 500
+
+// ForEachSession calls fn for every session of the user, newest first, fetching the
+// sessions in batches so exports can stream without loading everything at once.
+// Iteration stops at the first error returned by fn.
+func (r *SessionRepository) ForEachSession(ctx context.Context, userID string, fn func(session models.SessionWithFlavors) error) error {
+	return r.ForEachSessionBetween(ctx, userID, time.Time{}, time.Time{}, 0, 0, fn)
+}
+
+// ForEachSessionBetween is ForEachSession restricted to sessions with from <= session_date < to.
+// A zero from or to leaves that side open. The sessions are ordered by session date, newest
+// first, with the ID as tiebreaker so batches neither skip nor repeat sessions; offset and
+// limit (0 for all) apply to that order.
+func (r *SessionRepository) ForEachSessionBetween(ctx context.Context, userID string, from, to time.Time, offset, limit int, fn func(session models.SessionWithFlavors) error) error {
+	remaining := limit
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		batchSize := exportBatchSize
+		if limit > 0 && remaining < batchSize {
+			batchSize = remaining
+		}
+		if batchSize == 0 {
+			return nil
+		}
+
+		query := r.client.From("shisha_sessions").
+			Select(sessionColumns, "", false).
+			Eq("user_id", userID)
+		if !from.IsZero() {
+			query = query.Gte("session_date", from.UTC().Format(time.RFC3339))
+		}
+		if !to.IsZero() {
+			query = query.Lt("session_date", to.UTC().Format(time.RFC3339))
+		}
+		data, _, err := query.
+			Order("session_date", &postgrest.OrderOpts{Ascending: false}).
+			Order("id", &postgrest.OrderOpts{Ascending: false}).
+			Range(offset, offset+batchSize-1, "").
+			Execute()
+		if err != nil {
+			return err
+		}
+
+		var page []models.ShishaSession
+		if err := json.Unmarshal(data, &page); err != nil {
+			return err
+		}
+
+		sessions, err := r.withFlavors(page)
+		if err != nil {
+			return err
+		}
+
+		for _, session := range sessions {
+			if err := fn(session); err != nil {
+				return err
+			}
+		}
+
+		if len(page) < batchSize {
+			return nil
+		}
+		offset += batchSize
+		remaining -= batchSize
+	}
+}
+
+// GetMaxFlavorCount returns the largest number of flavors in any session of the user
+func (r *SessionRepository) GetMaxFlavorCount(ctx context.Context, userID string) (int, error) {
+	data, _, err := r.client.From("shisha_sessions").
+		Select("id", "", false).
+		Eq("user_id", userID).
+		Execute()
+
+	if err != nil {
+		return 0, err
+	}
+
+	var sessions []struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(data, &sessions); err != nil {
+		return 0, err
+	}
+
+	counts := make(map[string]int)
+	maxCount := 0
+	for start := 0; start < len(sessions); start += exportBatchSize {
+		end := start + exportBatchSize
+		if end > len(sessions) {
+			end = len(sessions)
+		}
+
+		ids := make([]string, 0, end-start)
+		for _, session := range sessions[start:end] {
+			ids = append(ids, session.ID)
+		}
+
+		data, _, err := r.client.From("session_flavors").
+			Select("session_id", "", false).
+			In("session_id", ids).
+			Execute()
+
+		if err != nil {
+			return 0, err
+		}
+
+		var flavors []struct {
+			SessionID string `json:"session_id"`
+		}
+		if err := json.Unmarshal(data, &flavors); err != nil {
+			return 0, err
+		}
+
+		for _, flavor := range flavors {
+			counts[flavor.SessionID]++
+			if counts[flavor.SessionID] > maxCount {
+				maxCount = counts[flavor.SessionID]
+			}
+		}
+	}
+
+	return maxCount, nil
+}
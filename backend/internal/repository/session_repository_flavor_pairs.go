@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+)
+
+// flavorPairKey identifies an unordered pair of flavors, with a sorting before b
+type flavorPairKey struct {
+	a, b string
+}
+
+type flavorPairCount struct {
+	count       int
+	ratedCount  int
+	ratingTotal int
+}
+
+// flavorCooccurrence contains the flavor and pair counts over sessions with flavors
+type flavorCooccurrence struct {
+	sessionCount int
+	flavors      map[string]int
+	pairs        map[flavorPairKey]*flavorPairCount
+}
+
+// sessionFlavorNames returns the distinct flavor names of a session in sorted order
+func sessionFlavorNames(session models.SessionWithFlavors) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, flavor := range session.Flavors {
+		if flavor.FlavorName == nil || *flavor.FlavorName == "" || seen[*flavor.FlavorName] {
+			continue
+		}
+		seen[*flavor.FlavorName] = true
+		names = append(names, *flavor.FlavorName)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func countFlavorCooccurrence(sessions []models.SessionWithFlavors) *flavorCooccurrence {
+	result := &flavorCooccurrence{
+		flavors: make(map[string]int),
+		pairs:   make(map[flavorPairKey]*flavorPairCount),
+	}
+
+	for _, session := range sessions {
+		names := sessionFlavorNames(session)
+		if len(names) == 0 {
+			continue
+		}
+		result.sessionCount++
+
+		for i, a := range names {
+			result.flavors[a]++
+			for _, b := range names[i+1:] {
+				key := flavorPairKey{a: a, b: b}
+				pair, ok := result.pairs[key]
+				if !ok {
+					pair = &flavorPairCount{}
+					result.pairs[key] = pair
+				}
+				pair.count++
+				if session.Rating != nil {
+					pair.ratedCount++
+					pair.ratingTotal += *session.Rating
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// lift returns the lift and PMI of a pair
+func (f *flavorCooccurrence) lift(key flavorPairKey, count int) (float64, float64) {
+	lift := float64(count) * float64(f.sessionCount) / (float64(f.flavors[key.a]) * float64(f.flavors[key.b]))
+	return lift, math.Log2(lift)
+}
+
+func averageRating(pair *flavorPairCount) *float64 {
+	if pair.ratedCount == 0 {
+		return nil
+	}
+	average := float64(pair.ratingTotal) / float64(pair.ratedCount)
+	return &average
+}
+
+// compareRatings orders rated pairs before unrated ones, higher ratings first
+func compareRatings(a, b *float64) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	case *a > *b:
+		return -1
+	case *a < *b:
+		return 1
+	}
+	return 0
+}
+
+// GetFlavorPairStats returns flavor pairs used together in at least minCount sessions
+func (r *SessionRepository) GetFlavorPairStats(ctx context.Context, userID string, minCount int, sortBy string, limit int) (*models.FlavorPairStats, error) {
+	// Get all sessions for the user
+	sessions, err := r.GetByUserID(ctx, userID, 10000, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := countFlavorCooccurrence(sessions)
+
+	pairs := make([]models.FlavorPair, 0, len(counts.pairs))
+	for key, pair := range counts.pairs {
+		if pair.count < minCount {
+			continue
+		}
+		lift, pmi := counts.lift(key, pair.count)
+		pairs = append(pairs, models.FlavorPair{
+			FlavorA:       key.a,
+			FlavorB:       key.b,
+			Count:         pair.count,
+			Support:       float64(pair.count) / float64(counts.sessionCount),
+			Lift:          lift,
+			PMI:           pmi,
+			RatedCount:    pair.ratedCount,
+			AverageRating: averageRating(pair),
+		})
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		a, b := pairs[i], pairs[j]
+		switch sortBy {
+		case models.PairSortLift, models.PairSortPMI:
+			if a.Lift != b.Lift {
+				return a.Lift > b.Lift
+			}
+		case models.PairSortRating:
+			if cmp := compareRatings(a.AverageRating, b.AverageRating); cmp != 0 {
+				return cmp < 0
+			}
+		}
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.FlavorA != b.FlavorA {
+			return a.FlavorA < b.FlavorA
+		}
+		return a.FlavorB < b.FlavorB
+	})
+
+	if limit > 0 && limit < len(pairs) {
+		pairs = pairs[:limit]
+	}
+
+	return &models.FlavorPairStats{
+		SessionCount: counts.sessionCount,
+		Pairs:        pairs,
+	}, nil
+}
+
+// GetFlavorPartners returns the flavors used together with the named flavor in at least
+// minCount sessions. The name is matched case-insensitively. It returns nil when the
+// flavor was never used.
+func (r *SessionRepository) GetFlavorPartners(ctx context.Context, userID string, name string, minCount int, sortBy string, limit int) (*models.FlavorPartners, error) {
+	// Get all sessions for the user
+	sessions, err := r.GetByUserID(ctx, userID, 10000, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := countFlavorCooccurrence(sessions)
+
+	// Resolve the stored spelling of the flavor, preferring an exact match
+	flavorName := ""
+	for stored := range counts.flavors {
+		if stored == name {
+			flavorName = stored
+			break
+		}
+		if strings.EqualFold(stored, name) && (flavorName == "" || stored < flavorName) {
+			flavorName = stored
+		}
+	}
+	if flavorName == "" {
+		return nil, nil
+	}
+
+	result := &models.FlavorPartners{
+		FlavorName:   flavorName,
+		SessionCount: counts.flavors[flavorName],
+		Partners:     []models.FlavorPartner{},
+	}
+
+	for key, pair := range counts.pairs {
+		if pair.count < minCount {
+			continue
+		}
+
+		var partner string
+		switch flavorName {
+		case key.a:
+			partner = key.b
+		case key.b:
+			partner = key.a
+		default:
+			continue
+		}
+
+		lift, pmi := counts.lift(key, pair.count)
+		result.Partners = append(result.Partners, models.FlavorPartner{
+			FlavorName:    partner,
+			Count:         pair.count,
+			Confidence:    float64(pair.count) / float64(result.SessionCount),
+			Lift:          lift,
+			PMI:           pmi,
+			RatedCount:    pair.ratedCount,
+			AverageRating: averageRating(pair),
+		})
+	}
+
+	partners := result.Partners
+	sort.Slice(partners, func(i, j int) bool {
+		a, b := partners[i], partners[j]
+		switch sortBy {
+		case models.PairSortLift, models.PairSortPMI:
+			if a.Lift != b.Lift {
+				return a.Lift > b.Lift
+			}
+		case models.PairSortRating:
+			if cmp := compareRatings(a.AverageRating, b.AverageRating); cmp != 0 {
+				return cmp < 0
+			}
+		}
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.FlavorName < b.FlavorName
+	})
+
+	if limit > 0 && limit < len(partners) {
+		result.Partners = partners[:limit]
+	}
+
+	return result, nil
+}
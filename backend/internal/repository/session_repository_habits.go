@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+)
+
+// dayNumber returns the number of days since the Unix epoch of t's calendar date.
+// Using the calendar date keeps day differences exact across DST changes.
+func dayNumber(t time.Time) int {
+	return int(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix() / 86400)
+}
+
+// GetHabitStats computes streaks, gaps and the weekly trend at now with day
+// boundaries in the timezone. The trend covers the last weeks weeks.
+func (r *SessionRepository) GetHabitStats(ctx context.Context, userID string, timezone string, now time.Time, weeks int) (*models.HabitStats, error) {
+	loc := loadLocation(timezone)
+	now = now.In(loc)
+
+	// Get all sessions for the user
+	sessions, err := r.GetByUserID(ctx, userID, 10000, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &models.HabitStats{
+		Timezone:     loc.String(),
+		SessionCount: len(sessions),
+		WeeklyTrend:  []models.HabitWeek{},
+	}
+
+	dayDates := make(map[int]string)
+	weekCounts := make(map[int]int) // Keyed by the day number of the week start
+	for _, session := range sessions {
+		localTime := session.SessionDate.In(loc)
+		dayDates[dayNumber(localTime)] = localTime.Format("2006-01-02")
+		weekCounts[dayNumber(bucketStart(localTime, BucketWeek))]++
+
+		if stats.FirstSessionAt == nil || session.SessionDate.Before(*stats.FirstSessionAt) {
+			first := session.SessionDate
+			stats.FirstSessionAt = &first
+		}
+		if stats.LastSessionAt == nil || session.SessionDate.After(*stats.LastSessionAt) {
+			last := session.SessionDate
+			stats.LastSessionAt = &last
+		}
+	}
+
+	// Weekly trend, gap-filled up to the current week
+	currentWeek := bucketStart(now, BucketWeek)
+	start := currentWeek.AddDate(0, 0, -7*(weeks-1))
+	for week := start; !week.After(currentWeek); week = nextBucket(week, BucketWeek) {
+		stats.WeeklyTrend = append(stats.WeeklyTrend, models.HabitWeek{
+			Week:         bucketLabel(week, BucketWeek),
+			SessionCount: weekCounts[dayNumber(week)],
+		})
+	}
+	stats.TrendSlope = trendSlope(stats.WeeklyTrend)
+
+	if len(sessions) == 0 {
+		return stats, nil
+	}
+
+	firstDate := stats.FirstSessionAt.In(loc).Format("2006-01-02")
+	lastDate := stats.LastSessionAt.In(loc).Format("2006-01-02")
+	stats.FirstSessionDate = &firstDate
+	stats.LastSessionDate = &lastDate
+
+	days := make([]int, 0, len(dayDates))
+	for day := range dayDates {
+		days = append(days, day)
+	}
+	sort.Ints(days)
+	stats.ActiveDays = len(days)
+
+	stats.LongestDailyStreak, stats.CurrentDailyStreak = streaks(days, 1, dayNumber(now))
+
+	weekStarts := make([]int, 0, len(weekCounts))
+	for week := range weekCounts {
+		weekStarts = append(weekStarts, week)
+	}
+	sort.Ints(weekStarts)
+	stats.LongestWeeklyStreak, stats.CurrentWeeklyStreak = streaks(weekStarts, 7, dayNumber(currentWeek))
+
+	if len(days) < 2 {
+		return stats, nil
+	}
+
+	gaps := make([]int, 0, len(days)-1)
+	total := 0
+	for i := 1; i < len(days); i++ {
+		gap := days[i] - days[i-1]
+		gaps = append(gaps, gap)
+		total += gap
+
+		if stats.LongestBreak == nil || gap > stats.LongestBreak.Days {
+			stats.LongestBreak = &models.HabitBreak{
+				From: dayDates[days[i-1]],
+				To:   dayDates[days[i]],
+				Days: gap,
+			}
+		}
+	}
+
+	average := float64(total) / float64(len(gaps))
+	stats.AverageDaysBetween = &average
+
+	sort.Ints(gaps)
+	median := float64(gaps[len(gaps)/2])
+	if len(gaps)%2 == 0 {
+		median = float64(gaps[len(gaps)/2-1]+gaps[len(gaps)/2]) / 2
+	}
+	stats.MedianDaysBetween = &median
+
+	return stats, nil
+}
+
+// streaks returns the longest run of values spaced step apart in sorted values and the
+// run ending at current or one step before it
+func streaks(values []int, step int, current int) (int, int) {
+	longest, run := 0, 0
+	for i, value := range values {
+		if i > 0 && value-values[i-1] == step {
+			run++
+		} else {
+			run = 1
+		}
+		if run > longest {
+			longest = run
+		}
+	}
+
+	// The run at the end of values is still going if it reaches the current period or the one before
+	last := values[len(values)-1]
+	if last != current && last != current-step {
+		return longest, 0
+	}
+	return longest, run
+}
+
+// trendSlope fits a least squares line through the weekly session counts
+func trendSlope(weeks []models.HabitWeek) float64 {
+	n := float64(len(weeks))
+	if n < 2 {
+		return 0
+	}
+
+	var sumX, sumY, sumXY, sumXX float64
+	for i, week := range weeks {
+		x := float64(i)
+		y := float64(week.SessionCount)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+
+	return (n*sumXY - sumX*sumY) / (n*sumXX - sumX*sumX)
+}
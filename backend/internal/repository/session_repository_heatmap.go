@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+)
+
+// heatmapDays lists the heatmap rows, starting on Monday like the week buckets
+var heatmapDays = []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"}
+
+// GetHeatmap counts sessions per weekday and hour of session_date in the timezone.
+// Empty store and flavor filters match every session; both are compared case-insensitively.
+func (r *SessionRepository) GetHeatmap(ctx context.Context, userID string, timezone string, store string, flavor string, slotLimit int) (*models.Heatmap, error) {
+	loc := loadLocation(timezone)
+
+	// Get all sessions for the user
+	sessions, err := r.GetByUserID(ctx, userID, 10000, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	heatmap := &models.Heatmap{
+		Timezone: loc.String(),
+		Days:     heatmapDays,
+		TopSlots: []models.HeatmapSlot{},
+	}
+	if store != "" {
+		heatmap.Store = &store
+	}
+	if flavor != "" {
+		heatmap.Flavor = &flavor
+	}
+
+	var dayCounts [7]int
+	var hourCounts [24]int
+
+	for _, session := range sessions {
+		if store != "" && (session.StoreName == nil || !strings.EqualFold(*session.StoreName, store)) {
+			continue
+		}
+		if flavor != "" && !hasFlavor(session, flavor) {
+			continue
+		}
+
+		localTime := session.SessionDate.In(loc)
+		day := (int(localTime.Weekday()) + 6) % 7 // Monday is 0
+		hour := localTime.Hour()
+
+		heatmap.Matrix[day][hour]++
+		dayCounts[day]++
+		hourCounts[hour]++
+		heatmap.SessionCount++
+	}
+
+	if heatmap.SessionCount == 0 {
+		return heatmap, nil
+	}
+
+	// Ties go to the earlier day and hour
+	topDay := 0
+	for day, count := range dayCounts {
+		if count > dayCounts[topDay] {
+			topDay = day
+		}
+	}
+	heatmap.TopDay = &models.HeatmapDay{Day: heatmapDays[topDay], Count: dayCounts[topDay]}
+
+	topHour := 0
+	for hour, count := range hourCounts {
+		if count > hourCounts[topHour] {
+			topHour = hour
+		}
+	}
+	heatmap.TopHour = &models.HeatmapHour{Hour: topHour, Count: hourCounts[topHour]}
+
+	var slots []models.HeatmapSlot
+	for day, hours := range heatmap.Matrix {
+		for hour, count := range hours {
+			if count > 0 {
+				slots = append(slots, models.HeatmapSlot{Day: heatmapDays[day], Hour: hour, Count: count})
+			}
+		}
+	}
+	// Slots are collected in day and hour order, so a stable sort keeps that order for ties
+	sort.SliceStable(slots, func(i, j int) bool {
+		return slots[i].Count > slots[j].Count
+	})
+	if slotLimit < len(slots) {
+		slots = slots[:slotLimit]
+	}
+	heatmap.TopSlots = append(heatmap.TopSlots, slots...)
+
+	return heatmap, nil
+}
+
+// hasFlavor reports whether any flavor of the session matches name case-insensitively
+func hasFlavor(session models.SessionWithFlavors, name string) bool {
+	for _, flavor := range session.Flavors {
+		if flavor.FlavorName != nil && strings.EqualFold(*flavor.FlavorName, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/toof-jp/shisha-log/backend/internal/currency"
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+)
+
+// GetJournal returns the sessions from from (inclusive) to to (exclusive), oldest
+// first and grouped by month in the timezone, with a summary of the range. A zero
+// from or to leaves that side of the range open. Top lists are limited to limit entries.
+func (r *SessionRepository) GetJournal(ctx context.Context, userID string, from, to time.Time, timezone string, limit int, converter *currency.AmountConverter) (*models.Journal, error) {
+	loc := loadLocation(timezone)
+
+	journal := &models.Journal{
+		Timezone: loc.String(),
+		Currency: converter.Target(),
+		Months:   []models.JournalMonth{},
+	}
+	if !from.IsZero() {
+		value := from.In(loc).Format("2006-01-02")
+		journal.From = &value
+	}
+	if !to.IsZero() {
+		value := to.In(loc).AddDate(0, 0, -1).Format("2006-01-02")
+		journal.To = &value
+	}
+
+	// Sessions arrive newest first, the journal lists them oldest first
+	var sessions []models.SessionWithFlavors
+	err := r.ForEachSessionBetween(ctx, userID, from, to, 0, 0, func(session models.SessionWithFlavors) error {
+		sessions = append(sessions, session)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.Reverse(sessions)
+
+	var monthSessions [][]models.SessionWithFlavors
+	activeDays := make(map[int]bool)
+	for _, session := range sessions {
+		localTime := session.SessionDate.In(loc)
+		monthKey := localTime.Format("2006-01")
+		if len(journal.Months) == 0 || journal.Months[len(journal.Months)-1].Month != monthKey {
+			journal.Months = append(journal.Months, models.JournalMonth{Month: monthKey, Entries: []models.JournalEntry{}})
+			monthSessions = append(monthSessions, nil)
+		}
+		month := &journal.Months[len(journal.Months)-1]
+		month.SessionCount++
+		monthSessions[len(monthSessions)-1] = append(monthSessions[len(monthSessions)-1], session)
+		activeDays[dayNumber(localTime)] = true
+
+		if session.DurationMinutes != nil {
+			journal.Summary.TotalMinutes += *session.DurationMinutes
+		}
+
+		// Resolve the currency so the journal can show amounts without the user's default
+		if session.Amount != nil && session.Currency == nil {
+			sessionCurrency := converter.SessionCurrency(&session.ShishaSession)
+			session.Currency = &sessionCurrency
+		}
+		month.Entries = append(month.Entries, models.JournalEntry{
+			SessionWithFlavors: session,
+			LocalTime:          localTime,
+		})
+	}
+
+	// Spend, rating and top lists are computed like the statistics endpoints
+	for i := range journal.Months {
+		spending := spendingStats(monthSessions[i], loc, 0, converter)
+		journal.Months[i].Spend = spending.Total
+		journal.Months[i].UnconvertedSessions = spending.UnconvertedSessions
+	}
+
+	summary := &journal.Summary
+	spending := spendingStats(sessions, loc, 0, converter)
+	summary.SessionCount = len(sessions)
+	summary.ActiveDays = len(activeDays)
+	summary.Spend = spending.Total
+	summary.UnconvertedSessions = spending.UnconvertedSessions
+	summary.AverageRating = totalUsage(sessions).AverageRating
+
+	_, flavors := countFlavors(sessions)
+	summary.TopFlavors = firstN(flavors, limit)
+	summary.TopStores = firstN(countStores(sessions), limit)
+	summary.TopCreators = firstN(countCreators(sessions), limit)
+
+	return journal, nil
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+)
+
+func (r *SessionRepository) GetRecipeStats(ctx context.Context, userID string, recipes []models.RecipeWithFlavors) (*models.RecipeStats, error) {
+	// Get all sessions for the user
+	sessions, err := r.GetByUserID(ctx, userID, 10000, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every recipe is reported, unused recipes too
+	ids := make([]string, len(recipes))
+	for i, recipe := range recipes {
+		ids[i] = recipe.ID
+	}
+	usages := tallyUsage(sessions, ids, func(session models.SessionWithFlavors) []string {
+		if session.RecipeID == nil {
+			return nil
+		}
+		return []string{*session.RecipeID}
+	})
+
+	stats := make([]models.RecipeUsage, 0, len(recipes))
+	for _, recipe := range recipes {
+		stats = append(stats, models.RecipeUsage{
+			RecipeID: recipe.ID,
+			Name:     recipe.Name,
+			Usage:    usages[recipe.ID],
+		})
+	}
+	sortByUsage(stats, func(u models.RecipeUsage) models.Usage { return u.Usage }, func(u models.RecipeUsage) string { return u.Name })
+
+	return &models.RecipeStats{
+		Recipes: stats,
+	}, nil
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/toof-jp/shisha-log/backend/internal/currency"
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+)
+
+// GetYearReport builds the year-in-review summary of a year in the timezone.
+// Top lists are limited to limit entries.
+func (r *SessionRepository) GetYearReport(ctx context.Context, userID string, year int, timezone string, limit int, converter *currency.AmountConverter) (*models.YearReport, error) {
+	loc := loadLocation(timezone)
+
+	// Get all sessions for the user, earlier years are needed to find first-time flavors and stores
+	sessions, err := r.GetByUserID(ctx, userID, 10000, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.YearReport{
+		Year:       year,
+		Timezone:   loc.String(),
+		Currency:   converter.Target(),
+		NewFlavors: []string{},
+		NewStores:  []string{},
+		Months:     make([]models.YearReportMonth, 12),
+	}
+	for month := range report.Months {
+		report.Months[month].Month = time.Date(year, time.Month(month+1), 1, 0, 0, 0, 0, loc).Format("2006-01")
+	}
+
+	flavorFirstYear := make(map[string]int)
+	storeFirstYear := make(map[string]int)
+	activeDays := make(map[int]bool)
+	var yearSessions []models.SessionWithFlavors
+	var monthSessions [12][]models.SessionWithFlavors
+
+	for _, session := range sessions {
+		localTime := session.SessionDate.In(loc)
+		sessionYear := localTime.Year()
+
+		for _, flavor := range session.Flavors {
+			if flavor.FlavorName == nil || *flavor.FlavorName == "" {
+				continue
+			}
+			if first, ok := flavorFirstYear[*flavor.FlavorName]; !ok || sessionYear < first {
+				flavorFirstYear[*flavor.FlavorName] = sessionYear
+			}
+		}
+		if session.StoreName != nil && *session.StoreName != "" {
+			if first, ok := storeFirstYear[*session.StoreName]; !ok || sessionYear < first {
+				storeFirstYear[*session.StoreName] = sessionYear
+			}
+		}
+
+		if sessionYear != year {
+			continue
+		}
+
+		report.SessionCount++
+		activeDays[dayNumber(localTime)] = true
+		report.Months[localTime.Month()-1].SessionCount++
+		yearSessions = append(yearSessions, session)
+		monthSessions[localTime.Month()-1] = append(monthSessions[localTime.Month()-1], session)
+	}
+
+	for name, first := range flavorFirstYear {
+		if first == year {
+			report.NewFlavors = append(report.NewFlavors, name)
+		}
+	}
+	sort.Strings(report.NewFlavors)
+	for name, first := range storeFirstYear {
+		if first == year {
+			report.NewStores = append(report.NewStores, name)
+		}
+	}
+	sort.Strings(report.NewStores)
+
+	// Spend, rating and top lists are computed like the statistics endpoints
+	for month := range report.Months {
+		report.Months[month].Spend = spendingStats(monthSessions[month], loc, 0, converter).Total
+	}
+	spending := spendingStats(yearSessions, loc, 0, converter)
+	report.Spend = spending.Total
+	report.UnconvertedSessions = spending.UnconvertedSessions
+	report.AverageRating = totalUsage(yearSessions).AverageRating
+
+	_, flavors := countFlavors(yearSessions)
+	report.TopFlavors = firstN(flavors, limit)
+	report.TopStores = firstN(countStores(yearSessions), limit)
+	report.TopCreators = firstN(countCreators(yearSessions), limit)
+
+	if report.SessionCount == 0 {
+		return report, nil
+	}
+
+	report.ActiveDays = len(activeDays)
+	days := make([]int, 0, len(activeDays))
+	for day := range activeDays {
+		days = append(days, day)
+	}
+	sort.Ints(days)
+	report.LongestStreak, _ = streaks(days, 1, days[len(days)-1])
+
+	// Ties go to the earlier month
+	busiest := report.Months[0]
+	for _, month := range report.Months[1:] {
+		if month.SessionCount > busiest.SessionCount {
+			busiest = month
+		}
+	}
+	report.BusiestMonth = &busiest
+
+	return report, nil
+}
+
+type nameCount struct {
+	name  string
+	count int
+}
+
+// topCounts returns up to limit entries sorted by count descending, then name
+func topCounts(counts map[string]int, limit int) []nameCount {
+	entries := make([]nameCount, 0, len(counts))
+	for name, count := range counts {
+		entries = append(entries, nameCount{name: name, count: count})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].count != entries[j].count {
+			return entries[i].count > entries[j].count
+		}
+		return entries[i].name < entries[j].name
+	})
+
+	if limit < len(entries) {
+		entries = entries[:limit]
+	}
+	return entries
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+)
+
+// Weights of the similarity components, summing to 1
+const (
+	similarityFlavorWeight  = 0.6
+	similarityStoreWeight   = 0.15
+	similarityCreatorWeight = 0.1
+	similarityMixWeight     = 0.15
+)
+
+// flavorWeights weights each flavor of a session by 1/flavor_order so the main
+// flavor counts most. Names are compared case-insensitively.
+func flavorWeights(session *models.SessionWithFlavors) (map[string]float64, map[string]string) {
+	weights := make(map[string]float64)
+	names := make(map[string]string)
+	for i, flavor := range session.Flavors {
+		if flavor.FlavorName == nil || strings.TrimSpace(*flavor.FlavorName) == "" {
+			continue
+		}
+		order := flavor.FlavorOrder
+		if order < 1 {
+			order = i + 1
+		}
+
+		key := strings.ToLower(strings.TrimSpace(*flavor.FlavorName))
+		if weight := 1 / float64(order); weight > weights[key] {
+			weights[key] = weight
+			names[key] = strings.TrimSpace(*flavor.FlavorName)
+		}
+	}
+	return weights, names
+}
+
+// sameText reports whether both values are set and equal ignoring case and surrounding spaces
+func sameText(a, b *string) bool {
+	if a == nil || b == nil || strings.TrimSpace(*a) == "" {
+		return false
+	}
+	return strings.EqualFold(strings.TrimSpace(*a), strings.TrimSpace(*b))
+}
+
+// GetSimilarSessions ranks the user's other sessions by similarity to target and
+// returns up to limit sessions with a score above zero
+func (r *SessionRepository) GetSimilarSessions(ctx context.Context, userID string, target *models.SessionWithFlavors, limit int) ([]models.SimilarSession, error) {
+	// Get all sessions for the user
+	sessions, err := r.GetByUserID(ctx, userID, 10000, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	targetWeights, _ := flavorWeights(target)
+
+	results := []models.SimilarSession{}
+	for i := range sessions {
+		session := &sessions[i]
+		if session.ID == target.ID {
+			continue
+		}
+
+		weights, names := flavorWeights(session)
+
+		// Weighted Jaccard similarity of the flavor sets
+		var minSum, maxSum float64
+		shared := []string{}
+		for key, weight := range weights {
+			targetWeight := targetWeights[key]
+			if targetWeight > 0 {
+				shared = append(shared, names[key])
+			}
+			if weight < targetWeight {
+				minSum += weight
+				maxSum += targetWeight
+			} else {
+				minSum += targetWeight
+				maxSum += weight
+			}
+		}
+		for key, targetWeight := range targetWeights {
+			if _, ok := weights[key]; !ok {
+				maxSum += targetWeight
+			}
+		}
+		sort.Strings(shared)
+
+		result := models.SimilarSession{
+			Session:       *session,
+			SharedFlavors: shared,
+			SameStore:     sameText(target.StoreName, session.StoreName),
+			SameCreator:   sameText(target.Creator, session.Creator),
+			SameMixName:   sameText(target.MixName, session.MixName),
+		}
+		if maxSum > 0 {
+			result.FlavorScore = minSum / maxSum
+		}
+
+		result.Score = similarityFlavorWeight * result.FlavorScore
+		if result.SameStore {
+			result.Score += similarityStoreWeight
+		}
+		if result.SameCreator {
+			result.Score += similarityCreatorWeight
+		}
+		if result.SameMixName {
+			result.Score += similarityMixWeight
+		}
+
+		if result.Score > 0 {
+			results = append(results, result)
+		}
+	}
+
+	// Most similar first, more recent sessions first on ties
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Session.SessionDate.After(results[j].Session.SessionDate)
+	})
+
+	if limit < len(results) {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/toof-jp/shisha-log/backend/internal/currency"
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+)
+
+// spendingBuckets lists the bucket sizes reported by the spending statistics
+var spendingBuckets = []string{BucketDay, BucketWeek, BucketMonth, BucketYear}
+
+// GetSpendingStats computes spending statistics with every amount converted into the
+// target currency of the converter using the exchange rate of the session date
+func (r *SessionRepository) GetSpendingStats(ctx context.Context, userID string, timezone string, limit int, converter *currency.AmountConverter) (*models.SpendingStats, error) {
+	// Get all sessions for the user
+	sessions, err := r.GetByUserID(ctx, userID, 10000, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return spendingStats(sessions, loadLocation(timezone), limit, converter), nil
+}
+
+// spendingStats computes the spending statistics of sessions with periods in loc.
+// The most and least expensive lists are limited to limit entries.
+func spendingStats(sessions []models.SessionWithFlavors, loc *time.Location, limit int, converter *currency.AmountConverter) *models.SpendingStats {
+	stats := &models.SpendingStats{
+		Timezone:          loc.String(),
+		Currency:          converter.Target(),
+		MissingCurrencies: []string{},
+		ByStore:           []models.SpendingGroup{},
+		ByCreator:         []models.SpendingGroup{},
+		MostExpensive:     []models.SessionSpend{},
+		LeastExpensive:    []models.SessionSpend{},
+	}
+
+	bucketMaps := make(map[string]map[string]*models.SpendingBucket, len(spendingBuckets))
+	for _, bucket := range spendingBuckets {
+		bucketMaps[bucket] = make(map[string]*models.SpendingBucket)
+	}
+	storeMap := make(map[string]*models.SpendingGroup)
+	creatorMap := make(map[string]*models.SpendingGroup)
+	missingCurrencies := make(map[string]bool)
+
+	var spends []models.SessionSpend
+	var durationAmount float64
+	var durationMinutes int
+	var first, last time.Time
+
+	for _, session := range sessions {
+		// Only sessions with an amount count towards spending
+		if session.Amount == nil {
+			continue
+		}
+
+		localTime := session.SessionDate.In(loc)
+		amount, ok := converter.SessionAmount(&session.ShishaSession, localTime)
+		if !ok {
+			stats.UnconvertedSessions++
+			missingCurrencies[converter.SessionCurrency(&session.ShishaSession)] = true
+			continue
+		}
+
+		stats.Total += amount
+		stats.SessionCount++
+
+		if first.IsZero() || localTime.Before(first) {
+			first = localTime
+		}
+		if localTime.After(last) {
+			last = localTime
+		}
+
+		for _, bucket := range spendingBuckets {
+			period := bucketLabel(bucketStart(localTime, bucket), bucket)
+			entry, ok := bucketMaps[bucket][period]
+			if !ok {
+				entry = &models.SpendingBucket{Period: period}
+				bucketMaps[bucket][period] = entry
+			}
+			entry.Total += amount
+			entry.SessionCount++
+		}
+
+		if session.StoreName != nil && *session.StoreName != "" {
+			addSpendingGroup(storeMap, *session.StoreName, amount)
+		}
+		if session.Creator != nil && *session.Creator != "" {
+			addSpendingGroup(creatorMap, *session.Creator, amount)
+		}
+
+		if session.DurationMinutes != nil && *session.DurationMinutes > 0 {
+			durationAmount += amount
+			durationMinutes += *session.DurationMinutes
+			stats.SessionsWithDuration++
+		}
+
+		spends = append(spends, models.SessionSpend{
+			SessionID:   session.ID,
+			SessionDate: session.SessionDate,
+			StoreName:   session.StoreName,
+			MixName:     session.MixName,
+			Creator:     session.Creator,
+			Amount:      amount,
+
+			OriginalAmount:   *session.Amount,
+			OriginalCurrency: converter.SessionCurrency(&session.ShishaSession),
+		})
+	}
+
+	for code := range missingCurrencies {
+		stats.MissingCurrencies = append(stats.MissingCurrencies, code)
+	}
+	sort.Strings(stats.MissingCurrencies)
+
+	if stats.SessionCount == 0 {
+		for _, bucket := range spendingBuckets {
+			*spendingPeriod(stats, bucket) = models.SpendingPeriodStats{Buckets: []models.SpendingBucket{}}
+		}
+		return stats
+	}
+
+	stats.AveragePerSession = stats.Total / float64(stats.SessionCount)
+
+	// Sort sessions by amount to find the most and least expensive ones
+	sort.SliceStable(spends, func(i, j int) bool {
+		return spends[i].Amount > spends[j].Amount
+	})
+
+	if limit > len(spends) {
+		limit = len(spends)
+	}
+	stats.MostExpensive = append(stats.MostExpensive, spends[:limit]...)
+	for i := len(spends) - 1; i >= len(spends)-limit; i-- {
+		stats.LeastExpensive = append(stats.LeastExpensive, spends[i])
+	}
+
+	// Convert bucket maps to sorted slices and average over every period in the range
+	for _, bucket := range spendingBuckets {
+		buckets := make([]models.SpendingBucket, 0, len(bucketMaps[bucket]))
+		for _, entry := range bucketMaps[bucket] {
+			entry.Average = entry.Total / float64(entry.SessionCount)
+			buckets = append(buckets, *entry)
+		}
+		sort.Slice(buckets, func(i, j int) bool {
+			return buckets[i].Period < buckets[j].Period
+		})
+
+		*spendingPeriod(stats, bucket) = models.SpendingPeriodStats{
+			Buckets:          buckets,
+			AveragePerPeriod: stats.Total / float64(bucketCount(first, last, bucket)),
+		}
+	}
+
+	stats.ByStore = sortedSpendingGroups(storeMap)
+	stats.ByCreator = sortedSpendingGroups(creatorMap)
+
+	if durationMinutes > 0 {
+		costPerHour := durationAmount / (float64(durationMinutes) / 60)
+		stats.CostPerHour = &costPerHour
+	}
+
+	return stats
+}
+
+// spendingPeriod returns the period statistics of stats for a bucket size
+func spendingPeriod(stats *models.SpendingStats, bucket string) *models.SpendingPeriodStats {
+	switch bucket {
+	case BucketWeek:
+		return &stats.Weekly
+	case BucketMonth:
+		return &stats.Monthly
+	case BucketYear:
+		return &stats.Yearly
+	default:
+		return &stats.Daily
+	}
+}
+
+func addSpendingGroup(groups map[string]*models.SpendingGroup, name string, amount float64) {
+	group, ok := groups[name]
+	if !ok {
+		group = &models.SpendingGroup{Name: name}
+		groups[name] = group
+	}
+	group.Total += amount
+	group.SessionCount++
+}
+
+// sortedSpendingGroups converts a group map to a slice sorted by total descending
+func sortedSpendingGroups(groups map[string]*models.SpendingGroup) []models.SpendingGroup {
+	result := make([]models.SpendingGroup, 0, len(groups))
+	for _, group := range groups {
+		group.Average = group.Total / float64(group.SessionCount)
+		result = append(result, *group)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Total != result[j].Total {
+			return result[i].Total > result[j].Total
+		}
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/toof-jp/shisha-log/backend/internal/currency"
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+)
+
+// GetTimeSeries returns session counts, spend and distinct flavors per bucket between
+// the from and to dates (YYYY-MM-DD, inclusive) in the timezone. Buckets without sessions are included.
+func (r *SessionRepository) GetTimeSeries(ctx context.Context, userID string, from, to string, bucket string, timezone string, converter *currency.AmountConverter) (*models.TimeSeries, error) {
+	loc := loadLocation(timezone)
+
+	fromDate, err := time.ParseInLocation("2006-01-02", from, loc)
+	if err != nil {
+		return nil, err
+	}
+	toDate, err := time.ParseInLocation("2006-01-02", to, loc)
+	if err != nil {
+		return nil, err
+	}
+	end := toDate.AddDate(0, 0, 1)
+
+	// Get all sessions for the user
+	sessions, err := r.GetByUserID(ctx, userID, 10000, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create every bucket in the range so gaps are reported as zero
+	series := &models.TimeSeries{
+		Timezone: loc.String(),
+		Bucket:   bucket,
+		From:     from,
+		To:       to,
+		Currency: converter.Target(),
+		Buckets:  []models.TimeSeriesBucket{},
+	}
+	bucketIndex := make(map[string]int)
+	for start := bucketStart(fromDate, bucket); start.Before(end); start = nextBucket(start, bucket) {
+		label := bucketLabel(start, bucket)
+		bucketIndex[label] = len(series.Buckets)
+		series.Buckets = append(series.Buckets, models.TimeSeriesBucket{
+			Period: label,
+			Start:  start,
+		})
+	}
+
+	bucketFlavors := make([]map[string]bool, len(series.Buckets))
+	for i := range bucketFlavors {
+		bucketFlavors[i] = make(map[string]bool)
+	}
+	allFlavors := make(map[string]bool)
+
+	for _, session := range sessions {
+		localTime := session.SessionDate.In(loc)
+		if localTime.Before(fromDate) || !localTime.Before(end) {
+			continue
+		}
+
+		i := bucketIndex[bucketLabel(bucketStart(localTime, bucket), bucket)]
+		entry := &series.Buckets[i]
+		entry.SessionCount++
+		series.SessionCount++
+
+		if session.Amount != nil {
+			amount, ok := converter.SessionAmount(&session.ShishaSession, localTime)
+			if ok {
+				entry.Spend += amount
+				series.Spend += amount
+			} else {
+				series.UnconvertedSessions++
+			}
+		}
+
+		for _, flavor := range session.Flavors {
+			if flavor.FlavorName != nil && *flavor.FlavorName != "" {
+				bucketFlavors[i][*flavor.FlavorName] = true
+				allFlavors[*flavor.FlavorName] = true
+			}
+		}
+	}
+
+	for i := range series.Buckets {
+		series.Buckets[i].DistinctFlavors = len(bucketFlavors[i])
+	}
+	series.DistinctFlavors = len(allFlavors)
+
+	return series, nil
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"sort"
+
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+)
+
+// tallyUsage computes the usage of every key. A session counts for each key
+// returned by keysOf; keys that are not in keys are ignored, and keys without
+// sessions are reported with zero usage.
+func tallyUsage(sessions []models.SessionWithFlavors, keys []string, keysOf func(models.SessionWithFlavors) []string) map[string]models.Usage {
+	usages := make(map[string]*models.Usage, len(keys))
+	ratingSums := make(map[string]int, len(keys))
+	for _, key := range keys {
+		usages[key] = &models.Usage{}
+	}
+
+	for _, session := range sessions {
+		for _, key := range keysOf(session) {
+			usage, ok := usages[key]
+			if !ok {
+				continue
+			}
+
+			usage.SessionCount++
+			if session.Rating != nil {
+				usage.RatedCount++
+				ratingSums[key] += *session.Rating
+			}
+
+			sessionDate := session.SessionDate
+			if usage.LastUsedAt == nil || sessionDate.After(*usage.LastUsedAt) {
+				usage.LastUsedAt = &sessionDate
+			}
+		}
+	}
+
+	result := make(map[string]models.Usage, len(usages))
+	for key, usage := range usages {
+		if usage.RatedCount > 0 {
+			average := float64(ratingSums[key]) / float64(usage.RatedCount)
+			usage.AverageRating = &average
+		}
+		result[key] = *usage
+	}
+
+	return result
+}
+
+// sortByUsage sorts by session count descending, then by name
+func sortByUsage[T any](items []T, usage func(T) models.Usage, name func(T) string) {
+	sort.Slice(items, func(i, j int) bool {
+		countI, countJ := usage(items[i]).SessionCount, usage(items[j]).SessionCount
+		if countI != countJ {
+			return countI > countJ
+		}
+		return name(items[i]) < name(items[j])
+	})
+}
+
+// totalUsage computes the usage of all sessions together
+func totalUsage(sessions []models.SessionWithFlavors) models.Usage {
+	all := func(models.SessionWithFlavors) []string { return []string{""} }
+	return tallyUsage(sessions, []string{""}, all)[""]
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+)
+
+// Bucket sizes used to group sessions over time
+const (
+	BucketDay   = "day"
+	BucketWeek  = "week"
+	BucketMonth = "month"
+	BucketYear  = "year"
+)
+
+// IsValidBucket reports whether bucket is a known bucket size
+func IsValidBucket(bucket string) bool {
+	switch bucket {
+	case BucketDay, BucketWeek, BucketMonth, BucketYear:
+		return true
+	}
+	return false
+}
+
+// loadLocation loads a timezone, falling back to UTC if it is invalid
+func loadLocation(timezone string) *time.Location {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// bucketStart returns the start of the bucket containing t in t's location.
+// Weeks start on Monday.
+func bucketStart(t time.Time, bucket string) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+
+	switch bucket {
+	case BucketWeek:
+		offset := (int(day.Weekday()) + 6) % 7 // Days since Monday
+		return day.AddDate(0, 0, -offset)
+	case BucketMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	case BucketYear:
+		return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return day
+	}
+}
+
+// nextBucket returns the start of the bucket following the one starting at start
+func nextBucket(start time.Time, bucket string) time.Time {
+	switch bucket {
+	case BucketWeek:
+		return start.AddDate(0, 0, 7)
+	case BucketMonth:
+		return start.AddDate(0, 1, 0)
+	case BucketYear:
+		return start.AddDate(1, 0, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// bucketLabel formats the start of a bucket: YYYY-MM-DD for days and weeks,
+// YYYY-MM for months and YYYY for years
+func bucketLabel(start time.Time, bucket string) string {
+	switch bucket {
+	case BucketMonth:
+		return start.Format("2006-01")
+	case BucketYear:
+		return fmt.Sprintf("%04d", start.Year())
+	default:
+		return start.Format("2006-01-02")
+	}
+}
+
+// bucketCount returns the number of buckets from the bucket containing from
+// to the bucket containing to, inclusive
+func bucketCount(from, to time.Time, bucket string) int {
+	count := 0
+	end := bucketStart(to, bucket)
+	for start := bucketStart(from, bucket); !start.After(end); start = nextBucket(start, bucket) {
+		count++
+	}
+	return count
+}
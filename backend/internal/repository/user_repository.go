@@ -28,11 +28,11 @@ func (r *UserRepository) Create(userID, passwordHash string) (*models.User, erro
 	query := `
 		INSERT INTO users (id, user_id, password_hash, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, user_id, password_hash, created_at, updated_at
+		RETURNING id, user_id, password_hash, default_currency, created_at, updated_at
 	`
 
 	err := r.db.QueryRow(query, user.ID, user.UserID, user.PasswordHash, user.CreatedAt, user.UpdatedAt).
-		Scan(&user.ID, &user.UserID, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt)
+		Scan(&user.ID, &user.UserID, &user.PasswordHash, &user.DefaultCurrency, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -40,16 +40,37 @@ func (r *UserRepository) Create(userID, passwordHash string) (*models.User, erro
 	return user, nil
 }
 
+// CreateSeeded creates a user marked as created by the seeder
+func (r *UserRepository) CreateSeeded(userID, passwordHash string) (*models.User, error) {
+	user, err := r.Create(userID, passwordHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := r.db.Exec(`UPDATE users SET seeded = true WHERE id = $1`, user.ID); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// IsSeeded reports whether the user was created by the seeder
+func (r *UserRepository) IsSeeded(id uuid.UUID) (bool, error) {
+	var seeded bool
+	err := r.db.QueryRow(`SELECT seeded FROM users WHERE id = $1`, id).Scan(&seeded)
+	return seeded, err
+}
+
 func (r *UserRepository) GetByID(id uuid.UUID) (*models.User, error) {
 	user := &models.User{}
 	query := `
-		SELECT id, user_id, password_hash, created_at, updated_at
+		SELECT id, user_id, password_hash, default_currency, created_at, updated_at
 		FROM users
 		WHERE id = $1
 	`
 
 	err := r.db.QueryRow(query, id).
-		Scan(&user.ID, &user.UserID, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt)
+		Scan(&user.ID, &user.UserID, &user.PasswordHash, &user.DefaultCurrency, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -60,13 +81,13 @@ func (r *UserRepository) GetByID(id uuid.UUID) (*models.User, error) {
 func (r *UserRepository) GetByUserID(userID string) (*models.User, error) {
 	user := &models.User{}
 	query := `
-		SELECT id, user_id, password_hash, created_at, updated_at
+		SELECT id, user_id, password_hash, default_currency, created_at, updated_at
 		FROM users
 		WHERE user_id = $1
 	`
 
 	err := r.db.QueryRow(query, userID).
-		Scan(&user.ID, &user.UserID, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt)
+		Scan(&user.ID, &user.UserID, &user.PasswordHash, &user.DefaultCurrency, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -85,6 +106,17 @@ func (r *UserRepository) Update(user *models.User) error {
 	return err
 }
 
+func (r *UserRepository) UpdateDefaultCurrency(userID uuid.UUID, currency string) error {
+	query := `
+		UPDATE users
+		SET default_currency = $2, updated_at = $3
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(query, userID, currency, time.Now())
+	return err
+}
+
 func (r *UserRepository) UpdatePassword(userID uuid.UUID, passwordHash string) error {
 	query := `
 		UPDATE users
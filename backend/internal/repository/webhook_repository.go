@@ -0,0 +1,362 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+)
+
+const webhookColumns = `id, user_id, url, secret, events, description, enabled,
+	consecutive_failures, disabled_at, disabled_reason, created_at, updated_at`
+
+const webhookDeliveryColumns = `id, webhook_id, event_id, event_type, payload, status, attempts,
+	response_status, response_body, error, next_attempt_at, created_at, delivered_at`
+
+type WebhookRepository struct {
+	db *sql.DB
+}
+
+func NewWebhookRepository(db *sql.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+func (r *WebhookRepository) Create(webhook *models.Webhook) (*models.Webhook, error) {
+	query := `
+		INSERT INTO webhooks (id, user_id, url, secret, events, description, enabled)
+		VALUES ($1, $2, $3, $4, $5, $6, TRUE)
+		RETURNING ` + webhookColumns
+
+	return r.scan(r.db.QueryRow(query, uuid.New(), webhook.UserID, webhook.URL, webhook.Secret,
+		pq.Array(webhook.Events), webhook.Description))
+}
+
+func (r *WebhookRepository) GetByID(id uuid.UUID) (*models.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE id = $1`
+
+	return r.scan(r.db.QueryRow(query, id))
+}
+
+func (r *WebhookRepository) GetByUserID(userID uuid.UUID) ([]models.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE user_id = $1 ORDER BY created_at`
+
+	return r.query(query, userID)
+}
+
+// GetSubscribed returns the enabled webhooks of the user that receive the event type
+func (r *WebhookRepository) GetSubscribed(userID uuid.UUID, eventType string) ([]models.Webhook, error) {
+	query := `
+		SELECT ` + webhookColumns + `
+		FROM webhooks
+		WHERE user_id = $1 AND enabled AND $2 = ANY(events)
+	`
+
+	return r.query(query, userID, eventType)
+}
+
+func (r *WebhookRepository) CountByUserID(userID uuid.UUID) (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM webhooks WHERE user_id = $1`, userID).Scan(&count)
+	return count, err
+}
+
+// Update applies the set fields of the request. Enabling a webhook resets its failure count.
+func (r *WebhookRepository) Update(id uuid.UUID, req *models.UpdateWebhookRequest) error {
+	sets := []string{"updated_at = NOW()"}
+	var args []interface{}
+	set := func(column string, value interface{}) {
+		args = append(args, value)
+		sets = append(sets, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+
+	if req.URL != nil {
+		set("url", *req.URL)
+	}
+	if req.Events != nil {
+		set("events", pq.Array(*req.Events))
+	}
+	if req.Description != nil {
+		set("description", nilIfBlank(*req.Description))
+	}
+	if req.Enabled != nil {
+		set("enabled", *req.Enabled)
+		if *req.Enabled {
+			sets = append(sets, "consecutive_failures = 0", "disabled_at = NULL", "disabled_reason = NULL")
+		} else {
+			sets = append(sets, "disabled_at = COALESCE(disabled_at, NOW())")
+		}
+	}
+
+	args = append(args, id)
+	query := fmt.Sprintf(`UPDATE webhooks SET %s WHERE id = $%d`, strings.Join(sets, ", "), len(args))
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(query, args...); err != nil {
+		return err
+	}
+	if req.Enabled != nil && !*req.Enabled {
+		if err := failPendingDeliveries(tx, id, "Webhook was disabled"); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *WebhookRepository) UpdateSecret(id uuid.UUID, secret string) error {
+	_, err := r.db.Exec(`UPDATE webhooks SET secret = $1, updated_at = NOW() WHERE id = $2`, secret, id)
+	return err
+}
+
+// Delete removes the webhook and its delivery log
+func (r *WebhookRepository) Delete(id uuid.UUID) error {
+	_, err := r.db.Exec(`DELETE FROM webhooks WHERE id = $1`, id)
+	return err
+}
+
+// CreateDelivery stores a pending delivery that is due at nextAttemptAt
+func (r *WebhookRepository) CreateDelivery(delivery *models.WebhookDelivery, nextAttemptAt time.Time) (*models.WebhookDelivery, error) {
+	query := `
+		INSERT INTO webhook_deliveries (id, webhook_id, event_id, event_type, payload, status, next_attempt_at)
+		VALUES ($1, $2, $3, $4, $5, 'pending', $6)
+		RETURNING ` + webhookDeliveryColumns
+
+	return r.scanDelivery(r.db.QueryRow(query, uuid.New(), delivery.WebhookID, delivery.EventID,
+		delivery.EventType, string(delivery.Payload), nextAttemptAt))
+}
+
+func (r *WebhookRepository) GetDeliveryByID(id uuid.UUID) (*models.WebhookDelivery, error) {
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries WHERE id = $1`
+
+	return r.scanDelivery(r.db.QueryRow(query, id))
+}
+
+// GetDeliveriesByWebhookID returns the delivery log of a webhook, newest first
+func (r *WebhookRepository) GetDeliveriesByWebhookID(webhookID uuid.UUID, limit, offset int) ([]models.WebhookDelivery, error) {
+	query := `
+		SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries
+		WHERE webhook_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Query(query, webhookID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		delivery, err := r.scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// ClaimDueDeliveries returns up to limit pending deliveries of enabled webhooks
+// that are due and postpones them by lease, so concurrent workers and server
+// instances never attempt the same delivery twice.
+func (r *WebhookRepository) ClaimDueDeliveries(limit int, lease time.Duration) ([]uuid.UUID, error) {
+	query := `
+		UPDATE webhook_deliveries
+		SET next_attempt_at = NOW() + $2::float8 * INTERVAL '1 second'
+		WHERE id IN (
+			SELECT d.id
+			FROM webhook_deliveries d
+			JOIN webhooks w ON w.id = d.webhook_id
+			WHERE d.status = 'pending' AND d.next_attempt_at <= NOW() AND w.enabled
+			ORDER BY d.next_attempt_at
+			LIMIT $1
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		RETURNING id
+	`
+
+	rows, err := r.db.Query(query, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// RecordSuccess marks the delivery as succeeded and resets the failure count of its webhook
+func (r *WebhookRepository) RecordSuccess(delivery *models.WebhookDelivery, responseStatus int, responseBody string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		UPDATE webhook_deliveries
+		SET status = 'succeeded', attempts = attempts + 1, response_status = $1, response_body = $2,
+			error = NULL, next_attempt_at = NULL, delivered_at = NOW()
+		WHERE id = $3
+	`, responseStatus, responseBody, delivery.ID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`UPDATE webhooks SET consecutive_failures = 0 WHERE id = $1`, delivery.WebhookID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RecordFailure stores a failed attempt. A nil nextAttemptAt gives up on the
+// delivery. The webhook is disabled once it reaches disableAfter consecutive
+// failures; the return value reports whether this attempt disabled it.
+func (r *WebhookRepository) RecordFailure(delivery *models.WebhookDelivery, responseStatus *int, responseBody *string, errMsg string, nextAttemptAt *time.Time, disableAfter int) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	status := models.WebhookDeliveryPending
+	if nextAttemptAt == nil {
+		status = models.WebhookDeliveryFailed
+	}
+
+	_, err = tx.Exec(`
+		UPDATE webhook_deliveries
+		SET status = $1, attempts = attempts + 1, response_status = $2, response_body = $3,
+			error = $4, next_attempt_at = $5
+		WHERE id = $6
+	`, status, responseStatus, responseBody, errMsg, nextAttemptAt, delivery.ID)
+	if err != nil {
+		return false, err
+	}
+
+	// The self join exposes the state before the update, so disabled is only
+	// true for the attempt that disabled the webhook
+	var disabled, enabled bool
+	err = tx.QueryRow(`
+		UPDATE webhooks w
+		SET consecutive_failures = w.consecutive_failures + 1,
+			enabled = w.enabled AND w.consecutive_failures + 1 < $1,
+			disabled_at = CASE WHEN w.enabled AND w.consecutive_failures + 1 >= $1 THEN NOW() ELSE w.disabled_at END,
+			disabled_reason = CASE WHEN w.enabled AND w.consecutive_failures + 1 >= $1 THEN $2 ELSE w.disabled_reason END
+		FROM webhooks old
+		WHERE w.id = $3 AND old.id = w.id
+		RETURNING old.enabled AND NOT w.enabled, w.enabled
+	`, disableAfter, fmt.Sprintf("Disabled after %d consecutive failed delivery attempts", disableAfter), delivery.WebhookID).Scan(&disabled, &enabled)
+	if err != nil {
+		return false, err
+	}
+
+	// A disabled webhook is not retried, so its deliveries must not stay pending
+	if !enabled {
+		if err := failPendingDeliveries(tx, delivery.WebhookID, "Webhook was disabled"); err != nil {
+			return false, err
+		}
+	}
+
+	return disabled, tx.Commit()
+}
+
+// failPendingDeliveries marks the pending deliveries of a webhook as failed
+func failPendingDeliveries(tx *sql.Tx, webhookID uuid.UUID, reason string) error {
+	_, err := tx.Exec(`
+		UPDATE webhook_deliveries
+		SET status = 'failed', next_attempt_at = NULL, error = COALESCE(error || '; ', '') || $2
+		WHERE webhook_id = $1 AND status = 'pending'
+	`, webhookID, reason)
+	return err
+}
+
+// DeleteDeliveriesBefore removes finished deliveries created before the given time
+func (r *WebhookRepository) DeleteDeliveriesBefore(before time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM webhook_deliveries WHERE created_at < $1 AND status <> 'pending'`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func (r *WebhookRepository) query(query string, args ...interface{}) ([]models.Webhook, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []models.Webhook{}
+	for rows.Next() {
+		webhook, err := r.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, *webhook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+func (r *WebhookRepository) scan(row rowScanner) (*models.Webhook, error) {
+	webhook := &models.Webhook{}
+	var events pq.StringArray
+	err := row.Scan(&webhook.ID, &webhook.UserID, &webhook.URL, &webhook.Secret, &events,
+		&webhook.Description, &webhook.Enabled, &webhook.ConsecutiveFailures,
+		&webhook.DisabledAt, &webhook.DisabledReason, &webhook.CreatedAt, &webhook.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	webhook.Events = []string(events)
+
+	return webhook, nil
+}
+
+func (r *WebhookRepository) scanDelivery(row rowScanner) (*models.WebhookDelivery, error) {
+	delivery := &models.WebhookDelivery{}
+	var payload string
+	err := row.Scan(&delivery.ID, &delivery.WebhookID, &delivery.EventID, &delivery.EventType,
+		&payload, &delivery.Status, &delivery.Attempts, &delivery.ResponseStatus,
+		&delivery.ResponseBody, &delivery.Error, &delivery.NextAttemptAt,
+		&delivery.CreatedAt, &delivery.DeliveredAt)
+	if err != nil {
+		return nil, err
+	}
+	delivery.Payload = []byte(payload)
+
+	return delivery, nil
+}
+
+func nilIfBlank(value string) *string {
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,183 @@
+// Package seed generates realistic demo sessions for local development, load
+// testing and the demo account
+package seed
+
+import (
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+)
+
+// Options controls the generated data
+type Options struct {
+	Sessions int       // Number of sessions
+	Days     int       // The sessions are spread over this many days before End
+	End      time.Time // Latest possible session time
+	Seed     int64     // Random seed, the same seed generates the same data with new IDs
+	Location *time.Location
+}
+
+// Generator creates sessions from the demo vocabulary
+type Generator struct {
+	rng  *rand.Rand
+	opts Options
+
+	favoriteStores []string // Stores in order of preference
+	storeCreators  map[string][]string
+}
+
+// NewGenerator returns a generator for the options
+func NewGenerator(opts Options) *Generator {
+	if opts.Days <= 0 {
+		opts.Days = 1
+	}
+	if opts.End.IsZero() {
+		opts.End = time.Now()
+	}
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+
+	g := &Generator{
+		rng:           rand.New(rand.NewSource(opts.Seed)),
+		opts:          opts,
+		storeCreators: make(map[string][]string),
+	}
+
+	// Every user has favorite stores and each store has its own staff
+	g.favoriteStores = append([]string{}, storeNames...)
+	g.rng.Shuffle(len(g.favoriteStores), func(i, j int) {
+		g.favoriteStores[i], g.favoriteStores[j] = g.favoriteStores[j], g.favoriteStores[i]
+	})
+	for _, store := range storeNames {
+		count := 1 + g.rng.Intn(3)
+		for _, i := range g.rng.Perm(len(creatorNames))[:count] {
+			g.storeCreators[store] = append(g.storeCreators[store], creatorNames[i])
+		}
+	}
+
+	return g
+}
+
+// Sessions generates the sessions of a user, newest first. flavors[i] holds
+// the flavors of sessions[i] in flavor_order.
+func (g *Generator) Sessions(userID string) ([]models.ShishaSession, [][]models.CreateFlavorRequest) {
+	type generated struct {
+		session models.ShishaSession
+		flavors []models.CreateFlavorRequest
+	}
+
+	items := make([]generated, g.opts.Sessions)
+	for i := range items {
+		session, flavors := g.session(userID)
+		items[i] = generated{session: session, flavors: flavors}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].session.SessionDate.After(items[j].session.SessionDate)
+	})
+
+	sessions := make([]models.ShishaSession, len(items))
+	flavors := make([][]models.CreateFlavorRequest, len(items))
+	for i, item := range items {
+		sessions[i] = item.session
+		flavors[i] = item.flavors
+	}
+	return sessions, flavors
+}
+
+// session generates a single session
+func (g *Generator) session(userID string) (models.ShishaSession, []models.CreateFlavorRequest) {
+	session := models.ShishaSession{
+		ID:          uuid.New().String(), // Random so seeding again adds sessions instead of clashing
+		UserID:      userID,
+		CreatedBy:   userID,
+		SessionDate: g.sessionDate(),
+	}
+
+	// Most sessions are at a store, the rest at home
+	if g.rng.Float64() < 0.8 {
+		store := g.pickStore()
+		session.StoreName = &store
+
+		if g.rng.Float64() < 0.7 {
+			creators := g.storeCreators[store]
+			creator := creators[g.rng.Intn(len(creators))]
+			session.Creator = &creator
+		}
+
+		// ¥1,500 to ¥4,000 in steps of ¥100
+		amount := 1500 + g.rng.Intn(26)*100
+		session.Amount = &amount
+		currency := "JPY"
+		session.Currency = &currency
+
+		if g.rng.Float64() < 0.2 {
+			order := orderDetails[g.rng.Intn(len(orderDetails))]
+			session.OrderDetails = &order
+		}
+	}
+
+	if g.rng.Float64() < 0.3 {
+		mix := mixNames[g.rng.Intn(len(mixNames))]
+		session.MixName = &mix
+	}
+	if g.rng.Float64() < 0.4 {
+		note := notes[g.rng.Intn(len(notes))]
+		session.Notes = &note
+	}
+	if g.rng.Float64() < 0.7 {
+		duration := 45 + g.rng.Intn(12)*10
+		session.DurationMinutes = &duration
+	}
+	if g.rng.Float64() < 0.8 {
+		// Ratings lean positive
+		rating := []int{2, 3, 3, 4, 4, 4, 5, 5}[g.rng.Intn(8)]
+		session.Rating = &rating
+	}
+
+	return session, g.flavors()
+}
+
+// flavors picks one to three distinct flavors
+func (g *Generator) flavors() []models.CreateFlavorRequest {
+	count := 1 + g.rng.Intn(3)
+	flavors := make([]models.CreateFlavorRequest, 0, count)
+	for _, i := range g.rng.Perm(len(flavorNames))[:count] {
+		name := flavorNames[i]
+		brand := brandNames[g.rng.Intn(len(brandNames))]
+		flavors = append(flavors, models.CreateFlavorRequest{
+			FlavorName: &name,
+			Brand:      &brand,
+		})
+	}
+	return flavors
+}
+
+// sessionDate picks a day in the span, mostly in the evening local time
+func (g *Generator) sessionDate() time.Time {
+	end := g.opts.End.In(g.opts.Location)
+	day := end.AddDate(0, 0, -g.rng.Intn(g.opts.Days))
+	hour := 13 + g.rng.Intn(11)
+	minute := g.rng.Intn(4) * 15
+
+	date := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, g.opts.Location)
+	if date.After(g.opts.End) {
+		date = date.AddDate(0, 0, -1)
+	}
+	return date.UTC()
+}
+
+// pickStore picks a store, preferring the favorite ones
+func (g *Generator) pickStore() string {
+	// Geometric preference: the first store is visited most often
+	for _, store := range g.favoriteStores {
+		if g.rng.Float64() < 0.45 {
+			return store
+		}
+	}
+	return g.favoriteStores[0]
+}
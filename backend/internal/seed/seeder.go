@@ -0,0 +1,64 @@
+package seed
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+	"github.com/toof-jp/shisha-log/backend/internal/repository"
+	"github.com/toof-jp/shisha-log/backend/internal/service"
+)
+
+// Seeder writes generated users and sessions to the database
+type Seeder struct {
+	userRepo        *repository.UserRepository
+	sessionRepo     *repository.SessionRepository
+	passwordService *service.PasswordService
+}
+
+func NewSeeder(userRepo *repository.UserRepository, sessionRepo *repository.SessionRepository, passwordService *service.PasswordService) *Seeder {
+	return &Seeder{
+		userRepo:        userRepo,
+		sessionRepo:     sessionRepo,
+		passwordService: passwordService,
+	}
+}
+
+// ErrNotSeeded is returned for an existing user that the seeder did not create
+var ErrNotSeeded = errors.New("user was not created by the seeder")
+
+// SeedUser creates the user unless it exists and adds generated sessions.
+// With reset the existing sessions of the user are deleted first, which makes
+// seeding with the same options repeatable. Existing users are only touched
+// when the seeder created them, otherwise ErrNotSeeded is returned.
+func (s *Seeder) SeedUser(ctx context.Context, userID, password string, opts Options, reset bool) (*models.User, error) {
+	user, err := s.userRepo.GetByUserID(userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		passwordHash, err := s.passwordService.HashPassword(password)
+		if err != nil {
+			return nil, err
+		}
+		user, err = s.userRepo.CreateSeeded(userID, passwordHash)
+		if err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	} else if seeded, err := s.userRepo.IsSeeded(user.ID); err != nil {
+		return nil, err
+	} else if !seeded {
+		return nil, ErrNotSeeded
+	} else if reset {
+		if err := s.sessionRepo.DeleteByUserID(ctx, user.ID.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	sessions, flavors := NewGenerator(opts).Sessions(user.ID.String())
+	if _, err := s.sessionRepo.CreateBatch(ctx, sessions, flavors); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
@@ -0,0 +1,46 @@
+package seed
+
+// The vocabulary matches the frontend demo data (frontend/src/utils/demoData.ts)
+
+var flavorNames = []string{
+	"ダブルアップル", "ミント", "ブルーベリー", "グレープ", "レモン",
+	"オレンジ", "ピーチ", "ストロベリー", "ウォーターメロン", "マンゴー",
+	"パイナップル", "チェリー", "バナナ", "ココナッツ", "バニラ",
+	"シナモン", "カプチーノ", "コーラ", "エナジードリンク", "ローズ",
+}
+
+var storeNames = []string{
+	"シーシャカフェ 渋谷", "チルスポット 新宿", "スモークラウンジ 六本木",
+	"シーシャバー 池袋", "リラックスカフェ 原宿", "シーシャ横丁 上野",
+	"チルアウト 青山", "シーシャ天国 銀座",
+}
+
+var creatorNames = []string{
+	"田中さん", "佐藤さん", "鈴木さん", "高橋さん", "渡辺さん",
+	"伊藤さん", "山本さん", "中村さん",
+}
+
+var brandNames = []string{"Al Fakher", "Fumari"}
+
+var mixNames = []string{
+	"スペシャルミックス", "フルーツパンチ", "トロピカルミックス", "ベリーミント",
+	"アップルシナモン", "シトラスクール", "デザートミックス", "ハウスブレンド",
+}
+
+var notes = []string{
+	"甘さ控えめで吸いやすかった",
+	"煙量が多くて満足",
+	"後半少し辛くなった",
+	"ミントが効いていて爽やか",
+	"また頼みたい組み合わせ",
+	"熱管理が絶妙だった",
+	"フルーツ感が強め",
+	"最後まで味が持った",
+}
+
+var orderDetails = []string{
+	"フレーバー追加",
+	"ドリンクセット",
+	"ロングタイム",
+	"チャコール交換1回",
+}
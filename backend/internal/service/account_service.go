@@ -0,0 +1,439 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/toof-jp/shisha-log/backend/internal/archive"
+	"github.com/toof-jp/shisha-log/backend/internal/currency"
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+	"github.com/toof-jp/shisha-log/backend/internal/repository"
+)
+
+// Kinds of entities in an account archive
+const (
+	kindEquipment = "equipment"
+	kindInventory = "inventory"
+	kindRecipe    = "recipe"
+	kindBudget    = "budget"
+	kindSession   = "session"
+)
+
+// AccountService imports account archives
+type AccountService struct {
+	accountRepo   *repository.AccountRepository
+	sessionRepo   *repository.SessionRepository
+	equipmentRepo *repository.EquipmentRepository
+	inventoryRepo *repository.InventoryRepository
+	recipeRepo    *repository.RecipeRepository
+	budgetRepo    *repository.BudgetRepository
+}
+
+func NewAccountService(
+	accountRepo *repository.AccountRepository,
+	sessionRepo *repository.SessionRepository,
+	equipmentRepo *repository.EquipmentRepository,
+	inventoryRepo *repository.InventoryRepository,
+	recipeRepo *repository.RecipeRepository,
+	budgetRepo *repository.BudgetRepository,
+) *AccountService {
+	return &AccountService{
+		accountRepo:   accountRepo,
+		sessionRepo:   sessionRepo,
+		equipmentRepo: equipmentRepo,
+		inventoryRepo: inventoryRepo,
+		recipeRepo:    recipeRepo,
+		budgetRepo:    budgetRepo,
+	}
+}
+
+// accountImport holds the state of a single import
+type accountImport struct {
+	userID    string
+	namespace uuid.UUID
+	remap     bool
+	result    *models.AccountImportResult
+	rows      models.AccountImportRows
+
+	existingIDs  map[string]bool              // IDs of all existing entities
+	existingKeys map[string]map[string]string // Natural key to existing ID by kind
+	ids          map[string]map[string]string // Archive ID to imported or existing ID by kind
+}
+
+// Import imports an archive into the account of userID.
+//
+// Archive IDs are mapped to UUIDv5 IDs derived from the account and the
+// archive ID, so importing the same archive again finds the entities of the
+// first import and skips them. Archives of the same account keep their IDs.
+// An entity that matches existing data by its natural key (equipment category
+// and name, recipe or budget name, inventory flavor and purchase, session time
+// and store) is reported as a conflict and the existing entity is used in its
+// place. In replace mode all existing data is deleted first and the default
+// currency is taken from the archive.
+//
+// Every entity is validated before anything is written, an invalid archive
+// returns an *ArchiveError. The deletion and all inserts then run in one
+// transaction, so a failed import leaves the account as it was.
+func (s *AccountService) Import(ctx context.Context, userID string, header *archive.Header, sessions []models.SessionWithFlavors, mode string) (*models.AccountImportResult, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateArchive(header, sessions); err != nil {
+		return nil, err
+	}
+
+	im := &accountImport{
+		userID:    userID,
+		namespace: userUUID,
+		remap:     header.User.ID != userUUID,
+		result: &models.AccountImportResult{
+			Mode:           mode,
+			ArchiveVersion: header.Version,
+			Conflicts:      []models.AccountImportConflict{},
+		},
+		existingIDs:  make(map[string]bool),
+		existingKeys: make(map[string]map[string]string),
+		ids:          make(map[string]map[string]string),
+	}
+	im.result.IDsRemapped = im.remap
+	for _, kind := range []string{kindEquipment, kindInventory, kindRecipe, kindBudget, kindSession} {
+		im.existingKeys[kind] = make(map[string]string)
+		im.ids[kind] = make(map[string]string)
+	}
+
+	replace := mode == models.AccountImportReplace
+	if replace {
+		// Existing data is deleted in the import transaction, so nothing clashes
+		if currency.IsValidCode(header.User.DefaultCurrency) {
+			im.rows.DefaultCurrency = currency.Normalize(header.User.DefaultCurrency)
+		}
+	} else if err := s.loadExisting(ctx, im); err != nil {
+		return nil, err
+	}
+
+	im.addEquipment(header.Equipment)
+	im.addInventory(header.Inventory)
+	im.addRecipes(header.Recipes)
+	im.addBudgets(header.Budgets)
+	im.addSessions(sessions)
+
+	deleted, err := s.accountRepo.Import(ctx, userUUID, &im.rows, replace)
+	if err != nil {
+		return nil, err
+	}
+	im.result.Deleted = deleted
+
+	return im.result, nil
+}
+
+// loadExisting indexes the IDs and natural keys of the existing data
+func (s *AccountService) loadExisting(ctx context.Context, im *accountImport) error {
+	equipment, err := s.equipmentRepo.GetByUserID(ctx, im.userID, "")
+	if err != nil {
+		return err
+	}
+	for _, item := range equipment {
+		im.addExisting(kindEquipment, item.ID, equipmentKey(item))
+	}
+
+	inventory, err := s.inventoryRepo.GetByUserID(ctx, im.userID)
+	if err != nil {
+		return err
+	}
+	for _, item := range inventory {
+		im.addExisting(kindInventory, item.ID, inventoryKey(item))
+	}
+
+	recipes, err := s.recipeRepo.GetByUserID(ctx, im.userID)
+	if err != nil {
+		return err
+	}
+	for _, recipe := range recipes {
+		im.addExisting(kindRecipe, recipe.ID, normalizeKey(recipe.Name))
+	}
+
+	budgets, err := s.budgetRepo.GetByUserID(ctx, im.userID)
+	if err != nil {
+		return err
+	}
+	for _, budget := range budgets {
+		im.addExisting(kindBudget, budget.ID, normalizeKey(budget.Name))
+	}
+
+	return s.sessionRepo.ForEachSession(ctx, im.userID, func(session models.SessionWithFlavors) error {
+		im.addExisting(kindSession, session.ID, sessionKey(session.ShishaSession))
+		return nil
+	})
+}
+
+func (im *accountImport) addEquipment(equipment []models.Equipment) {
+	for _, item := range equipment {
+		id, create := im.resolve(kindEquipment, item.ID, equipmentKey(item), &im.result.Skipped.Equipment)
+		if !create {
+			continue
+		}
+
+		im.rows.Equipment = append(im.rows.Equipment, models.EquipmentInsert{
+			ID:       id,
+			UserID:   im.userID,
+			Category: item.Category,
+			Name:     item.Name,
+			Brand:    item.Brand,
+			Notes:    item.Notes,
+		})
+		im.result.Created.Equipment++
+	}
+}
+
+func (im *accountImport) addInventory(inventory []models.InventoryItem) {
+	for _, item := range inventory {
+		id, create := im.resolve(kindInventory, item.ID, inventoryKey(item), &im.result.Skipped.Inventory)
+		if !create {
+			continue
+		}
+
+		im.rows.Inventory = append(im.rows.Inventory, models.InventoryInsert{
+			ID:           id,
+			UserID:       im.userID,
+			FlavorName:   item.FlavorName,
+			Brand:        item.Brand,
+			WeightGrams:  item.WeightGrams,
+			PurchaseDate: nilIfEmpty(item.PurchaseDate),
+			Price:        item.Price,
+			OpenedDate:   nilIfEmpty(item.OpenedDate),
+			Notes:        item.Notes,
+		})
+		im.result.Created.Inventory++
+	}
+}
+
+func (im *accountImport) addRecipes(recipes []models.RecipeWithFlavors) {
+	for _, recipe := range recipes {
+		id, create := im.resolve(kindRecipe, recipe.ID, normalizeKey(recipe.Name), &im.result.Skipped.Recipes)
+		if !create {
+			continue
+		}
+
+		im.rows.Recipes = append(im.rows.Recipes, models.RecipeInsert{
+			ID:     id,
+			UserID: im.userID,
+			Name:   recipe.Name,
+			Notes:  recipe.Notes,
+		})
+
+		sort.SliceStable(recipe.Flavors, func(i, j int) bool {
+			return recipe.Flavors[i].FlavorOrder < recipe.Flavors[j].FlavorOrder
+		})
+		for i, flavor := range recipe.Flavors {
+			im.rows.RecipeFlavors = append(im.rows.RecipeFlavors, models.RecipeFlavorInsert{
+				ID:          uuid.New().String(),
+				RecipeID:    id,
+				FlavorName:  flavor.FlavorName,
+				Brand:       flavor.Brand,
+				Ratio:       flavor.Ratio,
+				FlavorOrder: i + 1, // Order starts from 1
+			})
+		}
+		im.result.Created.Recipes++
+	}
+}
+
+func (im *accountImport) addBudgets(budgets []models.Budget) {
+	for _, budget := range budgets {
+		id, create := im.resolve(kindBudget, budget.ID, normalizeKey(budget.Name), &im.result.Skipped.Budgets)
+		if !create {
+			continue
+		}
+
+		timezone := budget.Timezone
+		if timezone == "" {
+			timezone = "UTC"
+		}
+		im.rows.Budgets = append(im.rows.Budgets, models.BudgetInsert{
+			ID:        id,
+			UserID:    im.userID,
+			Name:      budget.Name,
+			Period:    budget.Period,
+			Amount:    budget.Amount,
+			Currency:  currency.Normalize(budget.Currency),
+			StoreName: budget.StoreName,
+			Timezone:  timezone,
+		})
+		im.result.Created.Budgets++
+	}
+}
+
+func (im *accountImport) addSessions(sessions []models.SessionWithFlavors) {
+	for _, session := range sessions {
+		id, create := im.resolve(kindSession, session.ID, sessionKey(session.ShishaSession), &im.result.Skipped.Sessions)
+		if !create {
+			continue
+		}
+
+		var sessionCurrency *string
+		if session.Currency != nil {
+			code := currency.Normalize(*session.Currency)
+			sessionCurrency = &code
+		}
+		im.rows.Sessions = append(im.rows.Sessions, models.SessionBatchInsert{
+			ID:              id,
+			UserID:          im.userID,
+			CreatedBy:       im.userID,
+			SessionDate:     session.SessionDate,
+			StoreName:       session.StoreName,
+			Notes:           session.Notes,
+			OrderDetails:    session.OrderDetails,
+			MixName:         session.MixName,
+			Creator:         session.Creator,
+			Amount:          session.Amount,
+			Currency:        sessionCurrency,
+			DurationMinutes: session.DurationMinutes,
+			Rating:          session.Rating,
+			RecipeID:        im.reference(kindRecipe, session.RecipeID),
+		})
+
+		sort.SliceStable(session.Flavors, func(i, j int) bool {
+			return session.Flavors[i].FlavorOrder < session.Flavors[j].FlavorOrder
+		})
+		for i, flavor := range session.Flavors {
+			im.rows.SessionFlavors = append(im.rows.SessionFlavors, models.FlavorInsert{
+				ID:          uuid.New().String(),
+				SessionID:   id,
+				FlavorName:  flavor.FlavorName,
+				Brand:       flavor.Brand,
+				FlavorOrder: i + 1, // Order starts from 1
+				InventoryID: im.reference(kindInventory, flavor.InventoryID),
+				Grams:       flavor.Grams,
+			})
+		}
+
+		seen := make(map[string]bool)
+		for _, equipmentID := range session.EquipmentIDs {
+			mapped := im.reference(kindEquipment, &equipmentID)
+			if mapped == nil || seen[*mapped] {
+				continue
+			}
+			seen[*mapped] = true
+			im.rows.SessionEquipment = append(im.rows.SessionEquipment, models.SessionEquipmentInsert{SessionID: id, EquipmentID: *mapped})
+		}
+		im.result.Created.Sessions++
+	}
+}
+
+// addExisting records an existing entity
+func (im *accountImport) addExisting(kind, id, key string) {
+	im.existingIDs[id] = true
+	if key != "" {
+		if _, ok := im.existingKeys[kind][key]; !ok {
+			im.existingKeys[kind][key] = id
+		}
+	}
+}
+
+// mapID returns the ID an archive entity gets in this account. Entities keep
+// stable IDs instead of getting new random ones, which is what lets a repeated
+// import recognize what it already created.
+func (im *accountImport) mapID(kind, archiveID string) string {
+	if !im.remap || archiveID == "" {
+		return archiveID
+	}
+	return uuid.NewSHA1(im.namespace, []byte(kind+":"+archiveID)).String()
+}
+
+// resolve decides whether an archive entity is created. It returns the ID the
+// entity has in this account and records skips and conflicts.
+func (im *accountImport) resolve(kind, archiveID, key string, skipped *int) (string, bool) {
+	id := im.mapID(kind, archiveID)
+	if id == "" {
+		id = uuid.New().String()
+	}
+
+	if im.existingIDs[id] {
+		im.ids[kind][archiveID] = id
+		*skipped++
+		return id, false
+	}
+
+	if existingID, ok := im.existingKeys[kind][key]; ok && key != "" {
+		im.ids[kind][archiveID] = existingID
+		im.result.Conflicts = append(im.result.Conflicts, models.AccountImportConflict{
+			Kind:       kind,
+			ArchiveID:  archiveID,
+			ExistingID: existingID,
+			Reason:     conflictReason(kind),
+		})
+		return existingID, false
+	}
+
+	im.ids[kind][archiveID] = id
+	im.addExisting(kind, id, key)
+	return id, true
+}
+
+// reference maps an archive ID referenced by another entity. References to
+// entities that are not in the archive are dropped.
+func (im *accountImport) reference(kind string, archiveID *string) *string {
+	if archiveID == nil {
+		return nil
+	}
+	id, ok := im.ids[kind][*archiveID]
+	if !ok {
+		return nil
+	}
+	return &id
+}
+
+// conflictReason explains why an entity clashes with existing data
+func conflictReason(kind string) string {
+	switch kind {
+	case kindEquipment:
+		return "equipment with the same category and name already exists"
+	case kindInventory:
+		return "inventory item with the same flavor, brand, weight and purchase date already exists"
+	case kindRecipe:
+		return "recipe with the same name already exists"
+	case kindBudget:
+		return "budget with the same name already exists"
+	default:
+		return "session at the same time and store already exists"
+	}
+}
+
+func equipmentKey(item models.Equipment) string {
+	return item.Category + "\x00" + normalizeKey(item.Name)
+}
+
+func inventoryKey(item models.InventoryItem) string {
+	key := fmt.Sprintf("%s\x00%s\x00%g", normalizeKey(item.FlavorName), normalizeOptional(item.Brand), item.WeightGrams)
+	if item.PurchaseDate != nil {
+		key += "\x00" + *item.PurchaseDate
+	}
+	return key
+}
+
+func sessionKey(session models.ShishaSession) string {
+	return session.SessionDate.UTC().Format("2006-01-02T15:04:05") + "\x00" + normalizeOptional(session.StoreName)
+}
+
+func normalizeKey(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+// nilIfEmpty returns nil for an empty optional string
+func nilIfEmpty(s *string) *string {
+	if s == nil || *s == "" {
+		return nil
+	}
+	return s
+}
+
+func normalizeOptional(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return normalizeKey(*value)
+}
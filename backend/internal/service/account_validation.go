@@ -0,0 +1,110 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/toof-jp/shisha-log/backend/internal/archive"
+	"github.com/toof-jp/shisha-log/backend/internal/currency"
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+)
+
+// ArchiveError reports an archive entity that can't be imported
+type ArchiveError struct {
+	Kind   string
+	ID     string
+	Reason string
+}
+
+func (e *ArchiveError) Error() string {
+	return fmt.Sprintf("%s %s: %s", e.Kind, e.ID, e.Reason)
+}
+
+// validateArchive checks every entity of an archive against the rules of the
+// API and the database constraints, so an import fails before writing anything
+func validateArchive(header *archive.Header, sessions []models.SessionWithFlavors) error {
+	for _, item := range header.Equipment {
+		if !models.IsValidEquipmentCategory(item.Category) {
+			return &ArchiveError{kindEquipment, item.ID, "unknown category " + item.Category}
+		}
+		if strings.TrimSpace(item.Name) == "" {
+			return &ArchiveError{kindEquipment, item.ID, "name is required"}
+		}
+	}
+
+	for _, item := range header.Inventory {
+		if strings.TrimSpace(item.FlavorName) == "" {
+			return &ArchiveError{kindInventory, item.ID, "flavor name is required"}
+		}
+		if item.WeightGrams <= 0 {
+			return &ArchiveError{kindInventory, item.ID, "weight must be greater than 0"}
+		}
+		if !isValidArchiveDate(item.PurchaseDate) || !isValidArchiveDate(item.OpenedDate) {
+			return &ArchiveError{kindInventory, item.ID, "dates must be YYYY-MM-DD"}
+		}
+	}
+
+	for _, recipe := range header.Recipes {
+		if strings.TrimSpace(recipe.Name) == "" {
+			return &ArchiveError{kindRecipe, recipe.ID, "name is required"}
+		}
+		for i, flavor := range recipe.Flavors {
+			if strings.TrimSpace(flavor.FlavorName) == "" {
+				return &ArchiveError{kindRecipe, recipe.ID, fmt.Sprintf("flavor %d: flavor name is required", i+1)}
+			}
+			if flavor.Ratio != nil && *flavor.Ratio <= 0 {
+				return &ArchiveError{kindRecipe, recipe.ID, fmt.Sprintf("flavor %d: ratio must be greater than 0", i+1)}
+			}
+		}
+	}
+
+	for _, budget := range header.Budgets {
+		if strings.TrimSpace(budget.Name) == "" {
+			return &ArchiveError{kindBudget, budget.ID, "name is required"}
+		}
+		if !models.IsValidBudgetPeriod(budget.Period) {
+			return &ArchiveError{kindBudget, budget.ID, "period must be weekly or monthly"}
+		}
+		if budget.Amount <= 0 {
+			return &ArchiveError{kindBudget, budget.ID, "amount must be greater than 0"}
+		}
+		if !currency.IsValidCode(budget.Currency) {
+			return &ArchiveError{kindBudget, budget.ID, "invalid currency code"}
+		}
+		if _, err := time.LoadLocation(budget.Timezone); err != nil {
+			return &ArchiveError{kindBudget, budget.ID, "invalid timezone"}
+		}
+	}
+
+	for _, session := range sessions {
+		if session.SessionDate.IsZero() {
+			return &ArchiveError{kindSession, session.ID, "session date is required"}
+		}
+		if session.Rating != nil && (*session.Rating < 1 || *session.Rating > 5) {
+			return &ArchiveError{kindSession, session.ID, "rating must be between 1 and 5"}
+		}
+		if session.DurationMinutes != nil && *session.DurationMinutes <= 0 {
+			return &ArchiveError{kindSession, session.ID, "duration must be greater than 0"}
+		}
+		if session.Currency != nil && !currency.IsValidCode(*session.Currency) {
+			return &ArchiveError{kindSession, session.ID, "invalid currency code"}
+		}
+		for i, flavor := range session.Flavors {
+			if flavor.Grams != nil && *flavor.Grams <= 0 {
+				return &ArchiveError{kindSession, session.ID, fmt.Sprintf("flavor %d: grams must be greater than 0", i+1)}
+			}
+		}
+	}
+
+	return nil
+}
+
+// isValidArchiveDate reports whether an optional date is empty or YYYY-MM-DD
+func isValidArchiveDate(date *string) bool {
+	if date == nil || *date == "" {
+		return true
+	}
+	_, err := time.Parse("2006-01-02", *date)
+	return err == nil
+}
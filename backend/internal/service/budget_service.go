@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/toof-jp/shisha-log/backend/internal/currency"
+	"github.com/toof-jp/shisha-log/backend/internal/events"
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+	"github.com/toof-jp/shisha-log/backend/internal/repository"
+)
+
+// BudgetService reports budget statuses and emits breach events
+type BudgetService struct {
+	budgetRepo  *repository.BudgetRepository
+	sessionRepo *repository.SessionRepository
+	userRepo    *repository.UserRepository
+	rateRepo    *repository.ExchangeRateRepository
+	bus         *events.Bus
+}
+
+func NewBudgetService(
+	budgetRepo *repository.BudgetRepository,
+	sessionRepo *repository.SessionRepository,
+	userRepo *repository.UserRepository,
+	rateRepo *repository.ExchangeRateRepository,
+	bus *events.Bus,
+) *BudgetService {
+	return &BudgetService{
+		budgetRepo:  budgetRepo,
+		sessionRepo: sessionRepo,
+		userRepo:    userRepo,
+		rateRepo:    rateRepo,
+		bus:         bus,
+	}
+}
+
+// GetStatuses returns the current period status of every budget of the user
+func (s *BudgetService) GetStatuses(ctx context.Context, userID string) ([]models.BudgetStatus, error) {
+	budgets, err := s.budgetRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.statuses(ctx, userID, budgets)
+}
+
+// CheckBreaches publishes a budget.breached event for every budget whose spend
+// exceeds its amount in the current period. Each budget is reported once per period.
+func (s *BudgetService) CheckBreaches(ctx context.Context, userID string) {
+	budgets, err := s.budgetRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		log.Printf("CheckBreaches error for user %s: %v", userID, err)
+		return
+	}
+	if len(budgets) == 0 {
+		return
+	}
+
+	statuses, err := s.statuses(ctx, userID, budgets)
+	if err != nil {
+		log.Printf("CheckBreaches error for user %s: %v", userID, err)
+		return
+	}
+
+	for _, status := range statuses {
+		if ctx.Err() != nil {
+			log.Printf("CheckBreaches for user %s stopped: %v", userID, ctx.Err())
+			return
+		}
+		if !status.Breached {
+			continue
+		}
+		if status.Budget.LastBreachedPeriod != nil && *status.Budget.LastBreachedPeriod == status.Period {
+			continue
+		}
+
+		marked, err := s.budgetRepo.MarkBreached(ctx, status.Budget.ID, status.Period)
+		if err != nil {
+			log.Printf("CheckBreaches error for budget %s: %v", status.Budget.ID, err)
+			continue
+		}
+		if !marked {
+			// A concurrent check already reported the breach
+			continue
+		}
+
+		s.bus.Publish(events.Event{
+			Type:   events.BudgetBreached,
+			UserID: userID,
+			Data:   models.BudgetBreachedEvent{Status: status},
+		})
+	}
+}
+
+func (s *BudgetService) statuses(ctx context.Context, userID string, budgets []models.Budget) ([]models.BudgetStatus, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.GetByID(userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	rates, err := s.rateRepo.GetAll(ctx, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	return s.sessionRepo.GetBudgetStatuses(ctx, userID, budgets, time.Now(), currency.NewConverter(rates), user.DefaultCurrency)
+}
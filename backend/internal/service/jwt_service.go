@@ -18,6 +18,7 @@ type JWTService struct {
 type Claims struct {
 	UserID   uuid.UUID `json:"user_id"`
 	Username string    `json:"username"`
+	Demo     bool      `json:"demo,omitempty"` // Issued for the read-only demo account
 	jwt.RegisteredClaims
 }
 
@@ -37,6 +38,16 @@ func NewJWTService(cfg *config.Config) *JWTService {
 }
 
 func (s *JWTService) GenerateToken(userID string) (string, error) {
+	return s.generateToken(userID, s.tokenDuration, false)
+}
+
+// GenerateDemoToken generates a token for the demo account that expires after
+// the given duration. Requests with it are read-only.
+func (s *JWTService) GenerateDemoToken(userID string, duration time.Duration) (string, error) {
+	return s.generateToken(userID, duration, true)
+}
+
+func (s *JWTService) generateToken(userID string, duration time.Duration, demo bool) (string, error) {
 	uid, err := uuid.Parse(userID)
 	if err != nil {
 		return "", err
@@ -45,8 +56,9 @@ func (s *JWTService) GenerateToken(userID string) (string, error) {
 	claims := &Claims{
 		UserID:   uid,
 		Username: "", // Kept for backward compatibility, can be removed in future
+		Demo:     demo,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.tokenDuration)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(duration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			Subject:   userID,
 		},
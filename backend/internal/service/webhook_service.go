@@ -0,0 +1,355 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/toof-jp/shisha-log/backend/internal/events"
+	"github.com/toof-jp/shisha-log/backend/internal/models"
+	"github.com/toof-jp/shisha-log/backend/internal/repository"
+	"github.com/toof-jp/shisha-log/backend/internal/webhook"
+)
+
+// WebhookPingEvent is the event type of test deliveries
+const WebhookPingEvent = "ping"
+
+const (
+	webhookMaxAttempts   = 6                   // Attempts per delivery before giving up
+	webhookRetryBase     = 30 * time.Second    // Delay after the first failure, doubled after every further failure
+	webhookDisableAfter  = 20                  // Consecutive failed attempts that disable a webhook
+	webhookTimeout       = 10 * time.Second    // Timeout of one attempt
+	webhookClaimLease    = time.Minute         // How long a claimed delivery is hidden from other workers
+	webhookPollInterval  = 5 * time.Second     // How often due retries are looked up
+	webhookClaimBatch    = 20                  // Deliveries attempted concurrently
+	webhookRetention     = 30 * 24 * time.Hour // How long finished deliveries are kept
+	webhookResponseLimit = 2048                // Bytes of the response body stored in the delivery log
+)
+
+var errPrivateWebhookTarget = errors.New("webhook URL must not point to a private or loopback address")
+
+// WebhookService delivers session events to the webhooks of a user.
+// Deliveries are stored before they are attempted, so retries survive restarts.
+type WebhookService struct {
+	repo                *repository.WebhookRepository
+	client              *http.Client
+	allowPrivateTargets bool
+	wake                chan struct{}
+}
+
+// NewWebhookService creates the service. Private and loopback targets such as
+// a local test receiver are only reachable when allowPrivateTargets is set.
+func NewWebhookService(repo *repository.WebhookRepository, allowPrivateTargets bool) *WebhookService {
+	dialer := &net.Dialer{Timeout: webhookTimeout}
+	if !allowPrivateTargets {
+		// Check the resolved address so DNS names cannot point into the private network
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || isPrivateIP(ip) {
+				return errPrivateWebhookTarget
+			}
+			return nil
+		}
+	}
+
+	return &WebhookService{
+		repo: repo,
+		client: &http.Client{
+			Timeout: webhookTimeout,
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: webhookTimeout,
+			},
+			// Redirects are reported as failures instead of being followed
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		allowPrivateTargets: allowPrivateTargets,
+		wake:                make(chan struct{}, 1),
+	}
+}
+
+// ValidateURL checks that a webhook URL can be delivered to
+func (s *WebhookService) ValidateURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return fmt.Errorf("url must be an absolute http or https URL")
+	}
+	if parsed.User != nil {
+		return fmt.Errorf("url must not contain credentials")
+	}
+	if s.allowPrivateTargets {
+		return nil
+	}
+
+	if parsed.Scheme != "https" {
+		return fmt.Errorf("url must use https")
+	}
+	host := parsed.Hostname()
+	if ip := net.ParseIP(host); (ip != nil && isPrivateIP(ip)) || strings.EqualFold(host, "localhost") {
+		return errPrivateWebhookTarget
+	}
+	return nil
+}
+
+// GenerateSecret returns a new random signing secret
+func (s *WebhookService) GenerateSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// HandleEvent queues a delivery of the event for every subscribed webhook of the user
+func (s *WebhookService) HandleEvent(event events.Event) {
+	userID, err := uuid.Parse(event.UserID)
+	if err != nil {
+		log.Printf("Webhook event %s has invalid user ID %q", event.Type, event.UserID)
+		return
+	}
+
+	webhooks, err := s.repo.GetSubscribed(userID, event.Type)
+	if err != nil {
+		log.Printf("Failed to get webhooks of user %s: %v", event.UserID, err)
+		return
+	}
+	if len(webhooks) == 0 {
+		return
+	}
+
+	eventID := uuid.New()
+	payload, err := json.Marshal(models.WebhookPayload{
+		ID:        eventID,
+		Type:      event.Type,
+		CreatedAt: event.OccurredAt.UTC(),
+		Data:      event.Data,
+	})
+	if err != nil {
+		log.Printf("Failed to encode webhook payload of %s: %v", event.Type, err)
+		return
+	}
+
+	for _, hook := range webhooks {
+		_, err := s.repo.CreateDelivery(&models.WebhookDelivery{
+			WebhookID: hook.ID,
+			EventID:   eventID,
+			EventType: event.Type,
+			Payload:   payload,
+		}, time.Now())
+		if err != nil {
+			log.Printf("Failed to queue webhook delivery for webhook %s: %v", hook.ID, err)
+		}
+	}
+
+	s.notify()
+}
+
+// Ping sends a test event to the webhook and returns the delivery after the first attempt
+func (s *WebhookService) Ping(ctx context.Context, hook *models.Webhook) (*models.WebhookDelivery, error) {
+	eventID := uuid.New()
+	payload, err := json.Marshal(models.WebhookPayload{
+		ID:        eventID,
+		Type:      WebhookPingEvent,
+		CreatedAt: time.Now().UTC(),
+		Data: map[string]interface{}{
+			"webhook_id": hook.ID,
+			"events":     hook.Events,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.deliverNow(ctx, hook, &models.WebhookDelivery{
+		WebhookID: hook.ID,
+		EventID:   eventID,
+		EventType: WebhookPingEvent,
+		Payload:   payload,
+	})
+}
+
+// Redeliver sends the payload of a previous delivery again as a new delivery
+// with the same event ID, and returns it after the first attempt
+func (s *WebhookService) Redeliver(ctx context.Context, hook *models.Webhook, previous *models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	return s.deliverNow(ctx, hook, &models.WebhookDelivery{
+		WebhookID: hook.ID,
+		EventID:   previous.EventID,
+		EventType: previous.EventType,
+		Payload:   previous.Payload,
+	})
+}
+
+// Run attempts due deliveries until ctx is done
+func (s *WebhookService) Run(ctx context.Context) {
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+	cleanup := time.NewTicker(time.Hour)
+	defer cleanup.Stop()
+
+	for {
+		s.processDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		case <-cleanup.C:
+			if _, err := s.repo.DeleteDeliveriesBefore(time.Now().Add(-webhookRetention)); err != nil {
+				log.Printf("Failed to delete old webhook deliveries: %v", err)
+			}
+		}
+	}
+}
+
+// notify wakes the worker without blocking when it is already awake
+func (s *WebhookService) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// deliverNow stores a delivery that the worker will not pick up during the
+// first attempt, and attempts it right away
+func (s *WebhookService) deliverNow(ctx context.Context, hook *models.Webhook, delivery *models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	created, err := s.repo.CreateDelivery(delivery, time.Now().Add(webhookClaimLease))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.attempt(ctx, hook, created); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetDeliveryByID(created.ID)
+}
+
+// processDue attempts claimed deliveries until none are due
+func (s *WebhookService) processDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		ids, err := s.repo.ClaimDueDeliveries(webhookClaimBatch, webhookClaimLease)
+		if err != nil {
+			log.Printf("Failed to claim webhook deliveries: %v", err)
+			return
+		}
+
+		var wg sync.WaitGroup
+		for _, id := range ids {
+			wg.Add(1)
+			go func(id uuid.UUID) {
+				defer wg.Done()
+				if err := s.deliverClaimed(ctx, id); err != nil {
+					log.Printf("Failed to deliver webhook delivery %s: %v", id, err)
+				}
+			}(id)
+		}
+		wg.Wait()
+
+		if len(ids) < webhookClaimBatch {
+			return
+		}
+	}
+}
+
+func (s *WebhookService) deliverClaimed(ctx context.Context, id uuid.UUID) error {
+	delivery, err := s.repo.GetDeliveryByID(id)
+	if err != nil {
+		return err
+	}
+	hook, err := s.repo.GetByID(delivery.WebhookID)
+	if err != nil {
+		return err
+	}
+
+	return s.attempt(ctx, hook, delivery)
+}
+
+// attempt sends the delivery once and records the outcome. The returned error
+// only reports failures to record it; delivery failures are stored in the log.
+func (s *WebhookService) attempt(ctx context.Context, hook *models.Webhook, delivery *models.WebhookDelivery) error {
+	responseStatus, responseBody, sendErr := s.send(ctx, hook, delivery)
+	if sendErr == nil {
+		return s.repo.RecordSuccess(delivery, responseStatus, responseBody)
+	}
+
+	var nextAttemptAt *time.Time
+	attempts := delivery.Attempts + 1
+	if attempts < webhookMaxAttempts {
+		next := time.Now().Add(webhookRetryBase << (attempts - 1))
+		nextAttemptAt = &next
+	}
+
+	var statusPtr *int
+	var bodyPtr *string
+	if responseStatus != 0 {
+		statusPtr = &responseStatus
+		bodyPtr = &responseBody
+	}
+
+	disabled, err := s.repo.RecordFailure(delivery, statusPtr, bodyPtr, sendErr.Error(), nextAttemptAt, webhookDisableAfter)
+	if err != nil {
+		return err
+	}
+	if disabled {
+		log.Printf("Webhook %s of user %s disabled after %d consecutive failures", hook.ID, hook.UserID, webhookDisableAfter)
+	}
+
+	return nil
+}
+
+// send posts the signed payload and returns the response status and the
+// truncated response body. Any status outside 2xx is an error.
+func (s *WebhookService) send(ctx context.Context, hook *models.Webhook, delivery *models.WebhookDelivery) (int, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Shisha-Log-Webhooks/1.0")
+	req.Header.Set(webhook.EventHeader, delivery.EventType)
+	req.Header.Set(webhook.DeliveryHeader, delivery.ID.String())
+	req.Header.Set(webhook.SignatureHeader, webhook.Sign(hook.Secret, time.Now(), delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	// Postgres text columns reject invalid UTF-8 and NUL bytes
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseLimit))
+	body := strings.ReplaceAll(strings.ToValidUTF8(string(raw), "\uFFFD"), "\x00", "")
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, body, fmt.Errorf("endpoint responded with status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, body, nil
+}
+
+func isPrivateIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsUnspecified() || ip.IsMulticast()
+}
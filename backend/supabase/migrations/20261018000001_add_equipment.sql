@@ -0,0 +1,42 @@
+-- Add per-user equipment inventory and link equipment to sessions
+-- This allows comparing sessions across bowls, hookahs, heat management devices and charcoal
+
+-- Create equipment table
+CREATE TABLE IF NOT EXISTS public.equipment (
+    id TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    user_id TEXT NOT NULL,
+    category TEXT NOT NULL,
+    name TEXT NOT NULL,
+    brand TEXT,
+    notes TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT check_equipment_category CHECK (category IN ('bowl', 'hookah', 'heat_management', 'charcoal'))
+);
+
+-- Create join table between sessions and the equipment used
+CREATE TABLE IF NOT EXISTS public.session_equipment (
+    session_id TEXT NOT NULL REFERENCES public.shisha_sessions(id) ON DELETE CASCADE,
+    equipment_id TEXT NOT NULL REFERENCES public.equipment(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (session_id, equipment_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_equipment_user_id ON public.equipment(user_id);
+CREATE INDEX IF NOT EXISTS idx_session_equipment_equipment_id ON public.session_equipment(equipment_id);
+
+CREATE TRIGGER update_equipment_updated_at
+    BEFORE UPDATE ON public.equipment
+    FOR EACH ROW EXECUTE FUNCTION public.update_updated_at_column();
+
+ALTER TABLE public.equipment ENABLE ROW LEVEL SECURITY;
+ALTER TABLE public.session_equipment ENABLE ROW LEVEL SECURITY;
+
+-- Add rating column to shisha_sessions so sessions can be compared across equipment
+ALTER TABLE public.shisha_sessions
+ADD COLUMN IF NOT EXISTS rating INTEGER DEFAULT NULL;
+
+ALTER TABLE public.shisha_sessions
+ADD CONSTRAINT check_rating_range CHECK (rating IS NULL OR (rating >= 1 AND rating <= 5));
+
+COMMENT ON COLUMN public.shisha_sessions.rating IS 'Optional session rating from 1 to 5';
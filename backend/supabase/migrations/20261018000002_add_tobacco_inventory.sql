@@ -0,0 +1,38 @@
+-- Add personal tobacco inventory and consumption tracking
+-- Session flavors can draw grams from an inventory item
+
+-- Create tobacco inventory table
+CREATE TABLE IF NOT EXISTS public.tobacco_inventory (
+    id TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    user_id TEXT NOT NULL,
+    flavor_name TEXT NOT NULL,
+    brand TEXT,
+    weight_grams NUMERIC(8, 2) NOT NULL,
+    purchase_date DATE,
+    price INTEGER,
+    opened_date DATE,
+    notes TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT check_weight_grams_positive CHECK (weight_grams > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_tobacco_inventory_user_id ON public.tobacco_inventory(user_id);
+
+CREATE TRIGGER update_tobacco_inventory_updated_at
+    BEFORE UPDATE ON public.tobacco_inventory
+    FOR EACH ROW EXECUTE FUNCTION public.update_updated_at_column();
+
+ALTER TABLE public.tobacco_inventory ENABLE ROW LEVEL SECURITY;
+
+-- Link session flavors to the inventory item they were drawn from
+ALTER TABLE public.session_flavors
+ADD COLUMN IF NOT EXISTS inventory_id TEXT REFERENCES public.tobacco_inventory(id) ON DELETE SET NULL,
+ADD COLUMN IF NOT EXISTS grams NUMERIC(8, 2);
+
+ALTER TABLE public.session_flavors
+ADD CONSTRAINT check_grams_positive CHECK (grams IS NULL OR grams > 0);
+
+CREATE INDEX IF NOT EXISTS idx_session_flavors_inventory_id ON public.session_flavors(inventory_id);
+
+COMMENT ON COLUMN public.session_flavors.grams IS 'Grams of tobacco drawn from the linked inventory item';
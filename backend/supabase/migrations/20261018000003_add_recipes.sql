@@ -0,0 +1,40 @@
+-- Add saved mix recipes that can be reused when logging sessions
+
+-- Create recipes table
+CREATE TABLE IF NOT EXISTS public.recipes (
+    id TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    user_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    notes TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Create recipe flavors table (ordered flavor list with ratios)
+CREATE TABLE IF NOT EXISTS public.recipe_flavors (
+    id TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    recipe_id TEXT NOT NULL REFERENCES public.recipes(id) ON DELETE CASCADE,
+    flavor_name TEXT NOT NULL,
+    brand TEXT,
+    ratio NUMERIC(6, 2),
+    flavor_order INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT check_recipe_flavor_order_positive CHECK (flavor_order > 0),
+    CONSTRAINT check_recipe_ratio_positive CHECK (ratio IS NULL OR ratio > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_recipes_user_id ON public.recipes(user_id);
+CREATE INDEX IF NOT EXISTS idx_recipe_flavors_order ON public.recipe_flavors(recipe_id, flavor_order);
+
+CREATE TRIGGER update_recipes_updated_at
+    BEFORE UPDATE ON public.recipes
+    FOR EACH ROW EXECUTE FUNCTION public.update_updated_at_column();
+
+ALTER TABLE public.recipes ENABLE ROW LEVEL SECURITY;
+ALTER TABLE public.recipe_flavors ENABLE ROW LEVEL SECURITY;
+
+-- Track which recipe a session was logged from
+ALTER TABLE public.shisha_sessions
+ADD COLUMN IF NOT EXISTS recipe_id TEXT REFERENCES public.recipes(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_shisha_sessions_recipe_id ON public.shisha_sessions(recipe_id);
@@ -0,0 +1,11 @@
+-- Add duration column to shisha_sessions table
+-- This allows computing the cost per hour of a session
+
+ALTER TABLE public.shisha_sessions
+ADD COLUMN IF NOT EXISTS duration_minutes INTEGER DEFAULT NULL;
+
+ALTER TABLE public.shisha_sessions
+ADD CONSTRAINT check_duration_minutes_positive CHECK (duration_minutes IS NULL OR duration_minutes > 0);
+
+-- Add comment to the column for documentation
+COMMENT ON COLUMN public.shisha_sessions.duration_minutes IS 'How long the session lasted in minutes';
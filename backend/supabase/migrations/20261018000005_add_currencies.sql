@@ -0,0 +1,30 @@
+-- Add multi-currency support for session amounts
+-- Sessions record the ISO 4217 currency of their amount, users get a default currency
+-- and exchange rates are maintained locally for conversion
+
+-- Default currency per user
+ALTER TABLE public.users
+ADD COLUMN IF NOT EXISTS default_currency CHAR(3) NOT NULL DEFAULT 'JPY';
+
+-- Currency of the session amount (NULL means the user's default currency)
+ALTER TABLE public.shisha_sessions
+ADD COLUMN IF NOT EXISTS currency CHAR(3) DEFAULT NULL;
+
+COMMENT ON COLUMN public.shisha_sessions.currency IS 'ISO 4217 currency code of the amount';
+
+-- Create exchange rates table: 1 base_currency = rate quote_currency on rate_date
+CREATE TABLE IF NOT EXISTS public.exchange_rates (
+    id TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    rate_date DATE NOT NULL,
+    base_currency CHAR(3) NOT NULL,
+    quote_currency CHAR(3) NOT NULL,
+    rate NUMERIC(20, 10) NOT NULL,
+    source TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT check_exchange_rate_positive CHECK (rate > 0),
+    CONSTRAINT unique_exchange_rate UNIQUE (rate_date, base_currency, quote_currency)
+);
+
+CREATE INDEX IF NOT EXISTS idx_exchange_rates_pair ON public.exchange_rates(base_currency, quote_currency, rate_date);
+
+ALTER TABLE public.exchange_rates ENABLE ROW LEVEL SECURITY;
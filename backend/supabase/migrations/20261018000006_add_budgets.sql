@@ -0,0 +1,25 @@
+-- Add per-user spending budgets
+
+CREATE TABLE IF NOT EXISTS public.budgets (
+    id TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    user_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    period TEXT NOT NULL,
+    amount NUMERIC(12, 2) NOT NULL,
+    currency CHAR(3) NOT NULL,
+    store_name TEXT,
+    timezone TEXT NOT NULL DEFAULT 'UTC',
+    last_breached_period TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT check_budget_period CHECK (period IN ('weekly', 'monthly')),
+    CONSTRAINT check_budget_amount_positive CHECK (amount > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_budgets_user_id ON public.budgets(user_id);
+
+CREATE TRIGGER update_budgets_updated_at
+    BEFORE UPDATE ON public.budgets
+    FOR EACH ROW EXECUTE FUNCTION public.update_updated_at_column();
+
+ALTER TABLE public.budgets ENABLE ROW LEVEL SECURITY;
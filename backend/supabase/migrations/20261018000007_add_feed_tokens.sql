@@ -0,0 +1,19 @@
+-- Add secret tokens for the per-user iCalendar feed of sessions
+-- A user has at most one active token; regenerating revokes the previous one
+
+CREATE TABLE IF NOT EXISTS public.feed_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    token TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_feed_tokens_user_id ON public.feed_tokens(user_id);
+
+COMMENT ON TABLE public.feed_tokens IS 'Secret tokens authorizing the iCalendar feed of a user';
+COMMENT ON COLUMN public.feed_tokens.last_used_at IS 'When a calendar app last fetched the feed';
+COMMENT ON COLUMN public.feed_tokens.revoked_at IS 'When the token was revoked or replaced';
+
+ALTER TABLE public.feed_tokens ENABLE ROW LEVEL SECURITY;
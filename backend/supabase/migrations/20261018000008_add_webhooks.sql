@@ -0,0 +1,52 @@
+-- Add user-configurable webhooks for session events and their delivery log
+-- Deliveries are retried with exponential backoff; a webhook is disabled after
+-- too many consecutive failed attempts
+
+CREATE TABLE IF NOT EXISTS public.webhooks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    events TEXT[] NOT NULL,
+    description TEXT,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    consecutive_failures INTEGER NOT NULL DEFAULT 0,
+    disabled_at TIMESTAMPTZ,
+    disabled_reason TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhooks_user_id ON public.webhooks(user_id);
+
+COMMENT ON TABLE public.webhooks IS 'HTTP endpoints notified about session events of a user';
+COMMENT ON COLUMN public.webhooks.secret IS 'Key of the HMAC-SHA256 signature sent with every delivery';
+COMMENT ON COLUMN public.webhooks.events IS 'Event types delivered to the endpoint, e.g. session.created';
+COMMENT ON COLUMN public.webhooks.consecutive_failures IS 'Failed delivery attempts since the last success';
+COMMENT ON COLUMN public.webhooks.disabled_reason IS 'Why the webhook was disabled automatically';
+
+CREATE TABLE IF NOT EXISTS public.webhook_deliveries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    webhook_id UUID NOT NULL REFERENCES public.webhooks(id) ON DELETE CASCADE,
+    event_id UUID NOT NULL,
+    event_type TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'succeeded', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    response_status INTEGER,
+    response_body TEXT,
+    error TEXT,
+    next_attempt_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_id ON public.webhook_deliveries(webhook_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON public.webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+
+COMMENT ON TABLE public.webhook_deliveries IS 'Delivery log of webhook events, kept for 30 days';
+COMMENT ON COLUMN public.webhook_deliveries.payload IS 'Exact JSON body that is signed and sent';
+COMMENT ON COLUMN public.webhook_deliveries.next_attempt_at IS 'When a pending delivery is attempted next';
+
+ALTER TABLE public.webhooks ENABLE ROW LEVEL SECURITY;
+ALTER TABLE public.webhook_deliveries ENABLE ROW LEVEL SECURITY;
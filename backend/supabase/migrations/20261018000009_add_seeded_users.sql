@@ -0,0 +1,7 @@
+-- Mark users created by the seed command and the demo account
+-- Seeding deletes and regenerates sessions, so it must never touch real accounts
+
+ALTER TABLE public.users
+ADD COLUMN IF NOT EXISTS seeded BOOLEAN NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN public.users.seeded IS 'True for users created by the seeder, which may reset their data';